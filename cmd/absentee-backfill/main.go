@@ -0,0 +1,112 @@
+// Command absentee-backfill re-runs absentee processing for a date range, for example after the server was
+// down or after shifts or leave were fixed. Processing is idempotent, so a range can safely be re-run.
+//
+// Usage:
+//
+//	go run ./cmd/absentee-backfill -from 2024-05-01 -to 2024-05-31 [-company 12] [-dry-run] [-json]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-face-auth/database"
+	"go-face-auth/database/repository"
+	"go-face-auth/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	from := flag.String("from", "", "first day to process, YYYY-MM-DD (required)")
+	to := flag.String("to", "", "last day to process, YYYY-MM-DD (defaults to -from)")
+	companyID := flag.Int("company", 0, "only process this company ID (defaults to all active companies)")
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	asJSON := flag.Bool("json", false, "print the full report as JSON")
+	flag.Parse()
+
+	if *from == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *to == "" {
+		*to = *from
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Error loading .env file, assuming environment variables are set.")
+	}
+	database.InitDB()
+	defer database.CloseDB()
+
+	db := database.DB
+	attendanceService := services.NewAttendanceService(
+		repository.NewEmployeeRepository(db),
+		repository.NewCompanyRepository(db),
+		repository.NewAttendanceRepository(db),
+		repository.NewFaceImageRepository(db),
+		repository.NewAttendanceLocationRepository(db),
+		repository.NewLeaveRequestRepository(db),
+		repository.NewShiftRepository(db),
+		repository.NewDivisionRepository(db),
+		repository.NewOvertimeRequestRepository(db),
+		repository.NewOvertimePolicyRepository(db),
+		repository.NewPublicHolidayRepository(db),
+		repository.NewAttendancePunchRepository(db),
+		repository.NewRemoteWorkRepository(db),
+		repository.NewAbsenteeRepository(db),
+		repository.NewAttendancePolicyRepository(db),
+		repository.NewLeaveTypeRepository(db),
+		services.NewPythonClient(),
+	)
+
+	req := services.AbsenteeBackfillRequest{StartDate: *from, EndDate: *to, DryRun: *dryRun}
+	if *companyID != 0 {
+		req.CompanyID = companyID
+	}
+
+	report, err := attendanceService.BackfillAbsentees(req)
+	if err != nil {
+		log.Fatalf("Absentee backfill failed: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport writes a human-readable summary with one line per change.
+func printReport(report *services.AbsenteeBackfillReport) {
+	mode := "applied"
+	if report.DryRun {
+		mode = "dry run, nothing written"
+	}
+	fmt.Printf("Absentee backfill %s to %s (%s)\n", report.StartDate, report.EndDate, mode)
+
+	for _, day := range report.Days {
+		fmt.Printf("\n%s  %s (ID %d): %d created, %d updated, %d closed, %d unchanged, %d pending, %d skipped, %d failed\n",
+			day.Date, day.CompanyName, day.CompanyID, day.Created, day.Updated, day.Closed, day.Unchanged, day.Pending, day.Skipped, day.Failed)
+		for _, change := range day.Changes {
+			if change.PreviousStatus != "" {
+				fmt.Printf("  %-6s %-30s %s -> %s\n", change.Action, change.EmployeeName, change.PreviousStatus, change.Status)
+			} else {
+				fmt.Printf("  %-6s %-30s %s\n", change.Action, change.EmployeeName, change.Status)
+			}
+		}
+	}
+
+	fmt.Printf("\nTotal: %d created, %d updated, %d closed, %d failed\n", report.Created, report.Updated, report.Closed, report.Failed)
+}
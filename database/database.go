@@ -49,6 +49,10 @@ func InitDB() {
 
 	log.Println("Successfully connected to MySQL database with GORM!")
 
+	if err := migrateAttendanceStatuses(DB); err != nil {
+		log.Fatalf("Error migrating attendance statuses: %v", err)
+	}
+
 	// AutoMigrate will create/update tables based on your models
 	log.Println("Running GORM AutoMigrate...")
 	err = DB.AutoMigrate(
@@ -69,11 +73,50 @@ func InitDB() {
 		&models.CustomOffer{},
 		&models.CustomPackageRequest{},
 		&models.DivisionTable{},
+		&models.OvertimeRequest{},
+		&models.OvertimePolicy{},
+		&models.PublicHoliday{},
+		&models.AttendanceCorrectionRequest{},
+		&models.AttendanceVersion{},
+		&models.KioskDevice{},
+		&models.KioskBatch{},
+		&models.KioskEntry{},
+		&models.AttendancePunch{},
+		&models.RemoteWorkPolicy{},
+		&models.RemoteWorkSchedule{},
+		&models.RemoteWorkRequest{},
+		&models.EmployeeHomeLocation{},
+		&models.ClientSite{},
+		&models.FieldVisit{},
+		&models.IdempotencyKey{},
+		&models.AttendancePolicy{},
+		&models.AbsenteeRun{},
+		&models.AttendanceAnomaly{},
+		&models.ExportJob{},
+		&models.ReportSubscription{},
+		&models.ReportDelivery{},
+		&models.LeavePolicy{},
+		&models.LeaveLedgerEntry{},
+		&models.LeaveType{},
 	)
 	if err != nil {
 		log.Fatalf("Error running GORM AutoMigrate: %v", err)
 	}
 	log.Println("GORM AutoMigrate completed.")
+
+	if err := backfillLeavePolicyStartYears(DB); err != nil {
+		log.Fatalf("Error backfilling leave policy start years: %v", err)
+	}
+	if err := backfillKioskAcceptedSequences(DB); err != nil {
+		log.Fatalf("Error backfilling kiosk accepted sequences: %v", err)
+	}
+	if err := purgeDeletedPublicHolidays(DB); err != nil {
+		log.Fatalf("Error purging deleted public holidays: %v", err)
+	}
+
+	if err := ensureAttendanceStatusConstraint(DB); err != nil {
+		log.Fatalf("Error constraining attendance statuses: %v", err)
+	}
 }
 
 func CloseDB() {
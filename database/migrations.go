@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"go-face-auth/models"
+
+	gorm "gorm.io/gorm"
+)
+
+// attendanceStatusConstraint is the check constraint on attendances_tables.status. It is only created when
+// missing, so a change to models.AttendanceStatuses needs a new constraint name.
+const attendanceStatusConstraint = "chk_attendances_tables_status"
+
+// migrateAttendanceStatuses rewrites statuses written before the attendance state machine existed, so that
+// the status column can be narrowed and constrained by AutoMigrate. Values that are not known at all are
+// closed as "incomplete", which keeps them visible to admins for review.
+func migrateAttendanceStatuses(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.AttendancesTable{}) {
+		return nil
+	}
+
+	for legacy, current := range models.LegacyAttendanceStatuses {
+		result := db.Model(&models.AttendancesTable{}).Where("status = ?", legacy).Update("status", current)
+		if result.Error != nil {
+			return fmt.Errorf("failed to migrate attendance status %q: %w", legacy, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Migrated %d attendance records from status %q to %q.", result.RowsAffected, legacy, current)
+		}
+	}
+
+	result := db.Model(&models.AttendancesTable{}).
+		Where("status IS NULL OR status NOT IN ?", models.AttendanceStatuses).
+		Update("status", models.AttendanceStatusIncomplete)
+	if result.Error != nil {
+		return fmt.Errorf("failed to migrate unknown attendance statuses: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Marked %d attendance records with an unknown status as %q.", result.RowsAffected, models.AttendanceStatusIncomplete)
+	}
+	return nil
+}
+
+// backfillLeavePolicyStartYears gives leave policies saved before they had a start year the year they were
+// created, which is when their balances started.
+func backfillLeavePolicyStartYears(db *gorm.DB) error {
+	result := db.Model(&models.LeavePolicy{}).Where("start_year = 0").Update("start_year", gorm.Expr("YEAR(created_at)"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill leave policy start years: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Set the start year of %d leave policies.", result.RowsAffected)
+	}
+	return nil
+}
+
+// backfillKioskAcceptedSequences makes kiosk entries accepted before sequences were claimed hold theirs, so they
+// cannot be replayed. Only the first acceptance of a sequence is kept, should an entry have been applied twice.
+func backfillKioskAcceptedSequences(db *gorm.DB) error {
+	result := db.Exec(`UPDATE kiosk_entries e
+		JOIN (SELECT MIN(id) AS id FROM kiosk_entries WHERE result = 'accepted' GROUP BY kiosk_device_id, sequence) first ON first.id = e.id
+		SET e.accepted_sequence = e.sequence
+		WHERE e.accepted_sequence IS NULL`)
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill kiosk accepted sequences: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Claimed the sequences of %d accepted kiosk entries.", result.RowsAffected)
+	}
+	return nil
+}
+
+// purgeDeletedPublicHolidays removes public holidays that were soft-deleted, which kept their dates from being
+// added again.
+func purgeDeletedPublicHolidays(db *gorm.DB) error {
+	result := db.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.PublicHoliday{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to purge deleted public holidays: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Purged %d deleted public holidays.", result.RowsAffected)
+	}
+	return nil
+}
+
+// ensureAttendanceStatusConstraint limits the status column to the known statuses at the database level.
+func ensureAttendanceStatusConstraint(db *gorm.DB) error {
+	if db.Migrator().HasConstraint(&models.AttendancesTable{}, attendanceStatusConstraint) {
+		return nil
+	}
+	sql := fmt.Sprintf("ALTER TABLE attendances_tables ADD CONSTRAINT %s CHECK (%s)", attendanceStatusConstraint, models.AttendanceStatusCheck())
+	if err := db.Exec(sql).Error; err != nil {
+		return fmt.Errorf("failed to add attendance status constraint: %w", err)
+	}
+	log.Println("Added attendance status constraint.")
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type absenteeRepository struct {
+	db *gorm.DB
+}
+
+func NewAbsenteeRepository(db *gorm.DB) AbsenteeRepository {
+	return &absenteeRepository{db: db}
+}
+
+// CreateAbsenteeRun records a pass of absentee processing.
+func (r *absenteeRepository) CreateAbsenteeRun(run *models.AbsenteeRun) error {
+	result := r.db.Omit("Company").Create(run)
+	if result.Error != nil {
+		log.Printf("Error creating absentee run for company %d: %v", run.CompanyID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetLatestAbsenteeRunForDate retrieves the most recent successful run that processed a company's day.
+func (r *absenteeRepository) GetLatestAbsenteeRunForDate(companyID int, date time.Time) (*models.AbsenteeRun, error) {
+	var run models.AbsenteeRun
+	result := r.db.Where("company_id = ? AND date = ? AND (error IS NULL OR error = '')", companyID, date.Format("2006-01-02")).Order("started_at DESC").First(&run)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Day not processed yet
+		}
+		log.Printf("Error getting latest absentee run for company %d on %s: %v", companyID, date.Format("2006-01-02"), result.Error)
+		return nil, result.Error
+	}
+	return &run, nil
+}
+
+// GetAbsenteeRunsPaginated retrieves a company's absentee runs, newest first.
+func (r *absenteeRepository) GetAbsenteeRunsPaginated(companyID int, page, pageSize int) ([]models.AbsenteeRun, int64, error) {
+	var runs []models.AbsenteeRun
+	var totalRecords int64
+
+	query := r.db.Model(&models.AbsenteeRun{}).Where("company_id = ?", companyID)
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting absentee runs: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("started_at DESC").Offset(offset).Limit(pageSize).Find(&runs).Error; err != nil {
+		log.Printf("Error getting paginated absentee runs: %v", err)
+		return nil, 0, err
+	}
+	return runs, totalRecords, nil
+}
+
+// GetLatestAbsenteeRunPerCompany retrieves the most recent run of every company that has been processed.
+func (r *absenteeRepository) GetLatestAbsenteeRunPerCompany() ([]models.AbsenteeRun, error) {
+	var runs []models.AbsenteeRun
+	latest := r.db.Model(&models.AbsenteeRun{}).Select("MAX(id)").Group("company_id")
+	if err := r.db.Preload("Company").Where("id IN (?)", latest).Order("started_at DESC").Find(&runs).Error; err != nil {
+		log.Printf("Error getting latest absentee runs: %v", err)
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// AbsenteeRepository defines the contract for absentee run history.
+type AbsenteeRepository interface {
+	CreateAbsenteeRun(run *models.AbsenteeRun) error
+	GetLatestAbsenteeRunForDate(companyID int, date time.Time) (*models.AbsenteeRun, error)
+	GetAbsenteeRunsPaginated(companyID int, page, pageSize int) ([]models.AbsenteeRun, int64, error)
+	GetLatestAbsenteeRunPerCompany() ([]models.AbsenteeRun, error)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"fmt"
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// analyticsGroupings maps each dimension to the SQL expressions of its key and label.
+var analyticsGroupings = map[string][2]string{
+	AnalyticsGroupTotal:    {"'total'", "'total'"},
+	AnalyticsGroupDay:      {"DATE_FORMAT(f.day, '%Y-%m-%d')", "DATE_FORMAT(f.day, '%Y-%m-%d')"},
+	AnalyticsGroupWeek:     {"DATE_FORMAT(DATE_SUB(f.day, INTERVAL WEEKDAY(f.day) DAY), '%Y-%m-%d')", "DATE_FORMAT(DATE_SUB(f.day, INTERVAL WEEKDAY(f.day) DAY), '%Y-%m-%d')"},
+	AnalyticsGroupMonth:    {"DATE_FORMAT(f.day, '%Y-%m')", "DATE_FORMAT(f.day, '%Y-%m')"},
+	AnalyticsGroupDivision: {"CAST(f.division_id AS CHAR)", "f.division_name"},
+	AnalyticsGroupShift:    {"CAST(f.shift_id AS CHAR)", "f.shift_name"},
+	AnalyticsGroupLocation: {"CAST(f.location_id AS CHAR)", "f.location_name"},
+	AnalyticsGroupWeekday:  {"CAST(DAYOFWEEK(f.day) - 1 AS CHAR)", "DAYNAME(f.day)"}, // 0 = Sunday
+}
+
+// analyticsRankings maps each ranking to the aggregate it orders by.
+var analyticsRankings = map[string]string{
+	AnalyticsRankLate:     "late",
+	AnalyticsRankAbsent:   "absent",
+	AnalyticsRankOvertime: "overtime_minutes",
+}
+
+// analyticsAggregates are the sums every analytics query selects from the attendance facts.
+const analyticsAggregates = `SUM(f.attended) AS attended, SUM(f.absent) AS absent, SUM(f.on_leave) AS on_leave, SUM(f.is_late) AS late,
+	SUM(CASE WHEN f.is_late = 1 AND f.minutes_after_start IS NOT NULL THEN 1 ELSE 0 END) AS timed_late,
+	COALESCE(SUM(CASE WHEN f.is_late = 1 THEN f.minutes_after_start END), 0) AS late_minutes,
+	COALESCE(SUM(CASE WHEN f.status = ? THEN f.overtime_minutes END), 0) AS overtime_minutes`
+
+// attendanceFacts builds a derived table with one row per attendance record of a company: its local day,
+// division, effective shift, matched location and whether it counts as attended, absent, on leave or late.
+// The effective shift is the division's first shift, else the employee's own, as in the attendance service,
+// and the late threshold follows the attendance policies before falling back to the shift's grace period.
+// Lateness is measured from the check-in time because the status is overwritten on check-out.
+func attendanceFacts(companyID int, start, end time.Time, tzOffsetSeconds int) (string, []interface{}) {
+	sql := `SELECT f2.*,
+		CASE WHEN f2.minutes_after_start IS NOT NULL THEN f2.minutes_after_start > f2.late_threshold ELSE f2.status = ? END AS is_late
+	FROM (
+		SELECT b.*, DATE(b.local_check_in) AS day,
+			CASE WHEN b.status IN ? THEN 1 ELSE 0 END AS attended,
+			CASE WHEN b.status = ? THEN 1 ELSE 0 END AS absent,
+			CASE WHEN b.status IN ? THEN 1 ELSE 0 END AS on_leave,
+			CASE WHEN b.status IN ? AND b.shift_start IS NOT NULL AND b.is_correction = FALSE
+				THEN GREATEST(TIMESTAMPDIFF(MINUTE, TIMESTAMP(DATE(b.local_check_in), b.shift_start), b.local_check_in), 0) END AS minutes_after_start
+		FROM (
+			SELECT a.employee_id, emp.name AS employee_name, a.status, a.is_correction, a.overtime_minutes,
+				DATE_ADD(a.check_in_time, INTERVAL ? SECOND) AS local_check_in,
+				emp.division_id, d.name AS division_name,
+				s.id AS shift_id, s.name AS shift_name, s.start_time AS shift_start,
+				COALESCE(sp.late_threshold_minutes, dp.late_threshold_minutes, cp.late_threshold_minutes, s.grace_period_minutes, 0) AS late_threshold,
+				loc.id AS location_id, loc.name AS location_name
+			FROM attendances_tables a
+			JOIN employees_tables emp ON emp.id = a.employee_id
+			LEFT JOIN division_tables d ON d.id = emp.division_id
+			LEFT JOIN shifts_tables s ON s.id = COALESCE((SELECT MIN(ds.shifts_table_id) FROM division_shifts ds WHERE ds.division_table_id = emp.division_id), emp.shift_id)
+			LEFT JOIN attendance_policies sp ON sp.company_id = emp.company_id AND sp.shift_id = s.id AND sp.deleted_at IS NULL
+			LEFT JOIN attendance_policies dp ON dp.company_id = emp.company_id AND dp.division_id = emp.division_id AND dp.shift_id IS NULL AND dp.deleted_at IS NULL
+			LEFT JOIN attendance_policies cp ON cp.company_id = emp.company_id AND cp.division_id IS NULL AND cp.shift_id IS NULL AND cp.deleted_at IS NULL
+			LEFT JOIN attendance_locations loc ON loc.id = (
+				SELECT p.location_id FROM attendance_punches p
+				WHERE p.attendance_id = a.id AND p.result = 'accepted' AND p.deleted_at IS NULL
+				ORDER BY p.punched_at LIMIT 1)
+			WHERE emp.company_id = ? AND a.check_in_time >= ? AND a.check_in_time < ? AND a.status <> ?
+		) b
+	) f2`
+	args := []interface{}{
+		models.AttendanceStatusLate,
+		models.AttendedAttendanceStatuses,
+		models.AttendanceStatusAbsent,
+		[]models.AttendanceStatus{models.AttendanceStatusOnLeave, models.AttendanceStatusOnSick},
+		models.AttendedAttendanceStatuses,
+		tzOffsetSeconds,
+		companyID, start, end, models.AttendanceStatusOvertimeIn, // Open overtime sessions have no duration yet
+	}
+	return sql, args
+}
+
+// GetAttendanceAggregates sums a company's attendance over a range, grouped by one dimension.
+func (r *analyticsRepository) GetAttendanceAggregates(companyID int, start, end time.Time, tzOffsetSeconds int, groupBy string) ([]AttendanceAggregate, error) {
+	grouping, ok := analyticsGroupings[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown analytics grouping %q", groupBy)
+	}
+
+	factsSQL, factsArgs := attendanceFacts(companyID, start, end, tzOffsetSeconds)
+	sql := fmt.Sprintf("SELECT %s AS `key`, %s AS label, %s FROM (%s) f GROUP BY 1, 2 ORDER BY 1", grouping[0], grouping[1], analyticsAggregates, factsSQL)
+	args := append([]interface{}{models.AttendanceStatusOvertimeOut}, factsArgs...)
+
+	var aggregates []AttendanceAggregate
+	if err := r.db.Raw(sql, args...).Scan(&aggregates).Error; err != nil {
+		log.Printf("Error aggregating attendance by %s for company %d: %v", groupBy, companyID, err)
+		return nil, err
+	}
+	return aggregates, nil
+}
+
+// GetEmployeeAttendanceRanking returns the employees with the highest late count, absences or overtime in a range.
+func (r *analyticsRepository) GetEmployeeAttendanceRanking(companyID int, start, end time.Time, tzOffsetSeconds int, rankBy string, limit int) ([]EmployeeAttendanceAggregate, error) {
+	orderBy, ok := analyticsRankings[rankBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown analytics ranking %q", rankBy)
+	}
+
+	factsSQL, factsArgs := attendanceFacts(companyID, start, end, tzOffsetSeconds)
+	sql := fmt.Sprintf("SELECT f.employee_id, MAX(f.employee_name) AS employee_name, %s FROM (%s) f GROUP BY f.employee_id HAVING %s > 0 ORDER BY %s DESC, f.employee_id LIMIT ?",
+		analyticsAggregates, factsSQL, orderBy, orderBy)
+	args := append([]interface{}{models.AttendanceStatusOvertimeOut}, factsArgs...)
+	args = append(args, limit)
+
+	var ranking []EmployeeAttendanceAggregate
+	if err := r.db.Raw(sql, args...).Scan(&ranking).Error; err != nil {
+		log.Printf("Error ranking employees by %s for company %d: %v", rankBy, companyID, err)
+		return nil, err
+	}
+	return ranking, nil
+}
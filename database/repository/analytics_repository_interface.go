@@ -0,0 +1,50 @@
+package repository
+
+import "time"
+
+// Dimensions attendance analytics can be grouped by.
+const (
+	AnalyticsGroupTotal    = "total"
+	AnalyticsGroupDay      = "day"
+	AnalyticsGroupWeek     = "week"
+	AnalyticsGroupMonth    = "month"
+	AnalyticsGroupDivision = "division"
+	AnalyticsGroupShift    = "shift"
+	AnalyticsGroupLocation = "location"
+	AnalyticsGroupWeekday  = "weekday"
+)
+
+// Measures employees can be ranked by.
+const (
+	AnalyticsRankLate     = "late"
+	AnalyticsRankAbsent   = "absent"
+	AnalyticsRankOvertime = "overtime"
+)
+
+// AttendanceAggregate holds the attendance counts of one group. Key is nil for records outside any group,
+// such as employees without a division or check-ins not matched to a location.
+type AttendanceAggregate struct {
+	Key             *string
+	Label           *string
+	Attended        int
+	Absent          int
+	OnLeave         int
+	Late            int
+	TimedLate       int // Late check-ins whose minutes could be measured against a shift
+	LateMinutes     int
+	OvertimeMinutes int
+}
+
+// EmployeeAttendanceAggregate holds the attendance counts of one employee.
+type EmployeeAttendanceAggregate struct {
+	EmployeeID   int
+	EmployeeName string
+	AttendanceAggregate
+}
+
+// AnalyticsRepository defines the contract for aggregated attendance queries. Ranges are half-open, and
+// tzOffsetSeconds shifts stored times into the company's local time before days and lateness are derived.
+type AnalyticsRepository interface {
+	GetAttendanceAggregates(companyID int, start, end time.Time, tzOffsetSeconds int, groupBy string) ([]AttendanceAggregate, error)
+	GetEmployeeAttendanceRanking(companyID int, start, end time.Time, tzOffsetSeconds int, rankBy string, limit int) ([]EmployeeAttendanceAggregate, error)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type attendanceAnomalyRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceAnomalyRepository(db *gorm.DB) AttendanceAnomalyRepository {
+	return &attendanceAnomalyRepository{db: db}
+}
+
+// CreateAttendanceAnomaly queues an anomaly for review. It reports false without an error when the company
+// already has an anomaly with the same fingerprint.
+func (r *attendanceAnomalyRepository) CreateAttendanceAnomaly(anomaly *models.AttendanceAnomaly) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(anomaly)
+	if result.Error != nil {
+		log.Printf("Error creating attendance anomaly %q: %v", anomaly.Fingerprint, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetAttendanceAnomalyByID retrieves an anomaly with its employees.
+func (r *attendanceAnomalyRepository) GetAttendanceAnomalyByID(id uint) (*models.AttendanceAnomaly, error) {
+	var anomaly models.AttendanceAnomaly
+	result := r.db.Preload("Employee").Preload("RelatedEmployee").First(&anomaly, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Anomaly not found
+		}
+		log.Printf("Error getting attendance anomaly with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &anomaly, nil
+}
+
+// GetAttendanceAnomaliesPaginated retrieves a company's anomalies, newest first, optionally by status, kind and employee name.
+func (r *attendanceAnomalyRepository) GetAttendanceAnomaliesPaginated(companyID int, status, kind, search string, page, pageSize int) ([]models.AttendanceAnomaly, int64, error) {
+	var anomalies []models.AttendanceAnomaly
+	var totalRecords int64
+
+	query := r.db.Model(&models.AttendanceAnomaly{}).Where("attendance_anomalies.company_id = ?", companyID)
+
+	if status != "" {
+		query = query.Where("attendance_anomalies.status = ?", status)
+	}
+	if kind != "" {
+		query = query.Where("attendance_anomalies.kind = ?", kind)
+	}
+	if search != "" {
+		query = query.Joins("JOIN employees_tables ON attendance_anomalies.employee_id = employees_tables.id").
+			Where("employees_tables.name LIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting attendance anomalies: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Employee").Preload("RelatedEmployee").
+		Order("attendance_anomalies.created_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&anomalies).Error
+	if err != nil {
+		log.Printf("Error getting paginated attendance anomalies: %v", err)
+		return nil, 0, err
+	}
+
+	return anomalies, totalRecords, nil
+}
+
+// UpdateAttendanceAnomaly saves the review of an anomaly.
+func (r *attendanceAnomalyRepository) UpdateAttendanceAnomaly(anomaly *models.AttendanceAnomaly) error {
+	if err := r.db.Omit("Employee", "RelatedEmployee").Save(anomaly).Error; err != nil {
+		log.Printf("Error updating attendance anomaly with ID %d: %v", anomaly.ID, err)
+		return err
+	}
+	return nil
+}
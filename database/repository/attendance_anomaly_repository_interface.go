@@ -0,0 +1,11 @@
+package repository
+
+import "go-face-auth/models"
+
+// AttendanceAnomalyRepository defines the contract for the attendance anomaly review queue.
+type AttendanceAnomalyRepository interface {
+	CreateAttendanceAnomaly(anomaly *models.AttendanceAnomaly) (bool, error)
+	GetAttendanceAnomalyByID(id uint) (*models.AttendanceAnomaly, error)
+	GetAttendanceAnomaliesPaginated(companyID int, status, kind, search string, page, pageSize int) ([]models.AttendanceAnomaly, int64, error)
+	UpdateAttendanceAnomaly(anomaly *models.AttendanceAnomaly) error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type attendanceCorrectionRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceCorrectionRequestRepository(db *gorm.DB) AttendanceCorrectionRequestRepository {
+	return &attendanceCorrectionRequestRepository{db: db}
+}
+
+// CreateCorrectionRequest inserts a new attendance correction request into the database.
+func (r *attendanceCorrectionRequestRepository) CreateCorrectionRequest(correctionRequest *models.AttendanceCorrectionRequest) error {
+	result := r.db.Create(correctionRequest)
+	if result.Error != nil {
+		log.Printf("Error creating attendance correction request: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Attendance correction request created with ID: %d", correctionRequest.ID)
+	return nil
+}
+
+// GetCorrectionRequestByID retrieves an attendance correction request by its ID.
+func (r *attendanceCorrectionRequestRepository) GetCorrectionRequestByID(id uint) (*models.AttendanceCorrectionRequest, error) {
+	var correctionRequest models.AttendanceCorrectionRequest
+	result := r.db.Preload("Employee").First(&correctionRequest, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Correction request not found
+		}
+		log.Printf("Error getting attendance correction request with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &correctionRequest, nil
+}
+
+// UpdateCorrectionRequest updates an existing attendance correction request.
+func (r *attendanceCorrectionRequestRepository) UpdateCorrectionRequest(correctionRequest *models.AttendanceCorrectionRequest) error {
+	result := r.db.Save(correctionRequest)
+	if result.Error != nil {
+		log.Printf("Error updating attendance correction request: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Attendance correction request updated with ID: %d", correctionRequest.ID)
+	return nil
+}
+
+// GetCorrectionRequestsByEmployeeID retrieves an employee's correction requests, optionally filtered by proposed time.
+func (r *attendanceCorrectionRequestRepository) GetCorrectionRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.AttendanceCorrectionRequest, error) {
+	var correctionRequests []models.AttendanceCorrectionRequest
+	query := r.db.Where("employee_id = ?", employeeID)
+
+	if startDate != nil {
+		query = query.Where("proposed_time >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("proposed_time < ?", endDate.Add(24*time.Hour))
+	}
+
+	result := query.Order("created_at DESC").Find(&correctionRequests)
+	if result.Error != nil {
+		log.Printf("Error getting attendance correction requests for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return correctionRequests, nil
+}
+
+// GetCompanyCorrectionRequestsPaginated retrieves paginated and filtered correction requests for a company.
+func (r *attendanceCorrectionRequestRepository) GetCompanyCorrectionRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendanceCorrectionRequest, int64, error) {
+	var correctionRequests []models.AttendanceCorrectionRequest
+	var totalRecords int64
+
+	query := r.db.Model(&models.AttendanceCorrectionRequest{}).
+		Joins("JOIN employees_tables ON attendance_correction_requests.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID)
+
+	if status != "" {
+		query = query.Where("attendance_correction_requests.status = ?", status)
+	}
+	if search != "" {
+		query = query.Where("employees_tables.name LIKE ?", "%"+search+"%")
+	}
+	if startDate != nil {
+		query = query.Where("attendance_correction_requests.proposed_time >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("attendance_correction_requests.proposed_time < ?", endDate.Add(24*time.Hour))
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting attendance correction requests: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Employee").
+		Order("attendance_correction_requests.created_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&correctionRequests)
+
+	if result.Error != nil {
+		log.Printf("Error getting paginated attendance correction requests: %v", result.Error)
+		return nil, 0, result.Error
+	}
+
+	return correctionRequests, totalRecords, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// AttendanceCorrectionRequestRepository defines the contract for attendance correction request database operations.
+type AttendanceCorrectionRequestRepository interface {
+	CreateCorrectionRequest(correctionRequest *models.AttendanceCorrectionRequest) error
+	GetCorrectionRequestByID(id uint) (*models.AttendanceCorrectionRequest, error)
+	UpdateCorrectionRequest(correctionRequest *models.AttendanceCorrectionRequest) error
+	GetCorrectionRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.AttendanceCorrectionRequest, error)
+	GetCompanyCorrectionRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendanceCorrectionRequest, int64, error)
+}
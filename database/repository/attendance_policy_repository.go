@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type attendancePolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendancePolicyRepository(db *gorm.DB) AttendancePolicyRepository {
+	return &attendancePolicyRepository{db: db}
+}
+
+// GetAttendancePoliciesByCompanyID retrieves the company attendance policy and all of its overrides.
+func (r *attendancePolicyRepository) GetAttendancePoliciesByCompanyID(companyID int) ([]models.AttendancePolicy, error) {
+	var policies []models.AttendancePolicy
+	if err := r.db.Where("company_id = ?", companyID).Order("id ASC").Find(&policies).Error; err != nil {
+		log.Printf("Error getting attendance policies for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetAttendancePolicyByID retrieves an attendance policy by its ID.
+func (r *attendancePolicyRepository) GetAttendancePolicyByID(id uint) (*models.AttendancePolicy, error) {
+	var policy models.AttendancePolicy
+	if err := r.db.First(&policy, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error getting attendance policy %d: %v", id, err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetAttendancePolicyForScope retrieves the policy of a company, or of one of its divisions or shifts.
+func (r *attendancePolicyRepository) GetAttendancePolicyForScope(companyID int, divisionID *uint, shiftID *int) (*models.AttendancePolicy, error) {
+	query := r.db.Where("company_id = ?", companyID)
+	if divisionID != nil {
+		query = query.Where("division_id = ?", *divisionID)
+	} else {
+		query = query.Where("division_id IS NULL")
+	}
+	if shiftID != nil {
+		query = query.Where("shift_id = ?", *shiftID)
+	} else {
+		query = query.Where("shift_id IS NULL")
+	}
+
+	var policy models.AttendancePolicy
+	if err := query.First(&policy).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error getting attendance policy for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SaveAttendancePolicy creates or updates an attendance policy.
+func (r *attendancePolicyRepository) SaveAttendancePolicy(policy *models.AttendancePolicy) error {
+	if err := r.db.Save(policy).Error; err != nil {
+		log.Printf("Error saving attendance policy for company %d: %v", policy.CompanyID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteAttendancePolicy removes an attendance policy, so its scope falls back to the broader rules.
+func (r *attendancePolicyRepository) DeleteAttendancePolicy(id uint) error {
+	if err := r.db.Delete(&models.AttendancePolicy{}, id).Error; err != nil {
+		log.Printf("Error deleting attendance policy %d: %v", id, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "go-face-auth/models"
+
+// AttendancePolicyRepository defines the contract for company attendance rules and their overrides.
+type AttendancePolicyRepository interface {
+	GetAttendancePoliciesByCompanyID(companyID int) ([]models.AttendancePolicy, error)
+	GetAttendancePolicyByID(id uint) (*models.AttendancePolicy, error)
+	GetAttendancePolicyForScope(companyID int, divisionID *uint, shiftID *int) (*models.AttendancePolicy, error)
+	SaveAttendancePolicy(policy *models.AttendancePolicy) error
+	DeleteAttendancePolicy(id uint) error
+}
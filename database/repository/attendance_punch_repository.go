@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type attendancePunchRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendancePunchRepository(db *gorm.DB) AttendancePunchRepository {
+	return &attendancePunchRepository{db: db}
+}
+
+// CreateAttendancePunch inserts the location evidence of a punch attempt.
+func (r *attendancePunchRepository) CreateAttendancePunch(punch *models.AttendancePunch) error {
+	result := r.db.Create(punch)
+	if result.Error != nil {
+		log.Printf("Error creating attendance punch: %v", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetLatestAcceptedPunchBefore retrieves the employee's most recent accepted punch before the given time.
+func (r *attendancePunchRepository) GetLatestAcceptedPunchBefore(employeeID int, before time.Time) (*models.AttendancePunch, error) {
+	var punch models.AttendancePunch
+	result := r.db.Where("employee_id = ? AND result = ? AND punched_at < ?", employeeID, "accepted", before).
+		Order("punched_at DESC").First(&punch)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // No earlier punch
+		}
+		log.Printf("Error getting latest punch for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return &punch, nil
+}
+
+// GetAttendancePunchesPaginated retrieves a company's punch attempts, optionally only rejected/accepted or flagged ones.
+func (r *attendancePunchRepository) GetAttendancePunchesPaginated(companyID int, result string, flaggedOnly bool, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendancePunch, int64, error) {
+	var punches []models.AttendancePunch
+	var totalRecords int64
+
+	query := r.db.Model(&models.AttendancePunch{}).
+		Joins("JOIN employees_tables ON attendance_punches.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID)
+
+	if result != "" {
+		query = query.Where("attendance_punches.result = ?", result)
+	}
+	if flaggedOnly {
+		query = query.Where("attendance_punches.flags <> ''")
+	}
+	if search != "" {
+		query = query.Where("employees_tables.name LIKE ?", "%"+search+"%")
+	}
+	if startDate != nil {
+		query = query.Where("attendance_punches.punched_at >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("attendance_punches.punched_at < ?", endDate.AddDate(0, 0, 1))
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting attendance punches: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Employee").
+		Order("attendance_punches.punched_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&punches).Error
+	if err != nil {
+		log.Printf("Error getting paginated attendance punches: %v", err)
+		return nil, 0, err
+	}
+
+	return punches, totalRecords, nil
+}
+
+// GetAcceptedPunchesByCompany retrieves a company's accepted punches in [start, end), oldest first.
+func (r *attendancePunchRepository) GetAcceptedPunchesByCompany(companyID int, start, end time.Time) ([]models.AttendancePunch, error) {
+	var punches []models.AttendancePunch
+	err := r.db.Joins("JOIN employees_tables ON attendance_punches.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ? AND attendance_punches.result = ?", companyID, "accepted").
+		Where("attendance_punches.punched_at >= ? AND attendance_punches.punched_at < ?", start, end).
+		Order("attendance_punches.punched_at ASC").
+		Find(&punches).Error
+	if err != nil {
+		log.Printf("Error getting accepted punches for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return punches, nil
+}
+
+// GetFirstPunchesFromNewDevices retrieves the accepted punches in [start, end) that were the first from their
+// device for the employee, when the employee had punched from another device before.
+func (r *attendancePunchRepository) GetFirstPunchesFromNewDevices(companyID int, start, end time.Time) ([]models.AttendancePunch, error) {
+	var punches []models.AttendancePunch
+	err := r.db.Joins("JOIN employees_tables ON attendance_punches.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ? AND attendance_punches.result = ? AND attendance_punches.device_id <> ''", companyID, "accepted").
+		Where("attendance_punches.punched_at >= ? AND attendance_punches.punched_at < ?", start, end).
+		Where(`NOT EXISTS (SELECT 1 FROM attendance_punches earlier WHERE earlier.employee_id = attendance_punches.employee_id
+			AND earlier.device_id = attendance_punches.device_id AND earlier.result = 'accepted' AND earlier.deleted_at IS NULL
+			AND (earlier.punched_at < attendance_punches.punched_at OR (earlier.punched_at = attendance_punches.punched_at AND earlier.id < attendance_punches.id)))`).
+		Where(`EXISTS (SELECT 1 FROM attendance_punches earlier WHERE earlier.employee_id = attendance_punches.employee_id
+			AND earlier.device_id <> '' AND earlier.device_id <> attendance_punches.device_id AND earlier.result = 'accepted'
+			AND earlier.deleted_at IS NULL AND earlier.punched_at < attendance_punches.punched_at)`).
+		Order("attendance_punches.punched_at ASC").
+		Find(&punches).Error
+	if err != nil {
+		log.Printf("Error getting new-device punches for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return punches, nil
+}
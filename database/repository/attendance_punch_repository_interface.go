@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// AttendancePunchRepository defines the contract for attendance punch (location evidence) database operations.
+type AttendancePunchRepository interface {
+	CreateAttendancePunch(punch *models.AttendancePunch) error
+	GetLatestAcceptedPunchBefore(employeeID int, before time.Time) (*models.AttendancePunch, error)
+	GetAttendancePunchesPaginated(companyID int, result string, flaggedOnly bool, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendancePunch, int64, error)
+	GetAcceptedPunchesByCompany(companyID int, start, end time.Time) ([]models.AttendancePunch, error)
+	GetFirstPunchesFromNewDevices(companyID int, start, end time.Time) ([]models.AttendancePunch, error)
+}
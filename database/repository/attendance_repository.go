@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"fmt"
 	"go-face-auth/models"
 	"log"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type attendanceRepository struct {
@@ -16,28 +18,133 @@ func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
 	return &attendanceRepository{db: db}
 }
 
-// CreateAttendance inserts a new attendance record.
-func (r *attendanceRepository) CreateAttendance(attendance *models.AttendancesTable) error {
-	result := r.db.Create(attendance)
-	if result.Error != nil {
-		log.Printf("Error creating attendance: %v", result.Error)
-		return result.Error
+// LockEmployeeAttendance takes the employee's attendance lock, which is held across every instance of the API,
+// and returns the function that releases it.
+func (r *attendanceRepository) LockEmployeeAttendance(employeeID int) (func(), error) {
+	return acquireNamedLock(r.db, fmt.Sprintf("employee_attendance:%d", employeeID))
+}
+
+// CreateAttendance inserts a new attendance record together with its first history version.
+func (r *attendanceRepository) CreateAttendance(attendance *models.AttendancesTable, change models.AttendanceChange) error {
+	if err := models.ValidateAttendanceTransition(change.Event, "", attendance.Status); err != nil {
+		log.Printf("Rejected attendance for employee %d: %v", attendance.EmployeeID, err)
+		return err
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attendance).Error; err != nil {
+			return err
+		}
+		return recordAttendanceVersion(tx, attendance.ID, "create", change, nil, models.NewAttendanceSnapshot(attendance))
+	})
+	if err != nil {
+		log.Printf("Error creating attendance: %v", err)
+		return err
 	}
 	log.Printf("Attendance record created with ID: %d", attendance.ID)
 	return nil
 }
 
-// UpdateAttendance updates an existing attendance record.
-func (r *attendanceRepository) UpdateAttendance(attendance *models.AttendancesTable) error {
-	result := r.db.Save(attendance)
-	if result.Error != nil {
-		log.Printf("Error updating attendance record with ID %d: %v", attendance.ID, result.Error)
-		return result.Error
+// UpdateAttendance updates an existing attendance record and records the before/after values as a new history version.
+// A status change must be a legal transition for the change's event; the stored row is locked while it is checked.
+func (r *attendanceRepository) UpdateAttendance(attendance *models.AttendancesTable, change models.AttendanceChange) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.AttendancesTable
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, attendance.ID).Error; err != nil {
+			return err
+		}
+		if err := models.ValidateAttendanceTransition(change.Event, current.Status, attendance.Status); err != nil {
+			return err
+		}
+		if err := tx.Save(attendance).Error; err != nil {
+			return err
+		}
+		return recordAttendanceVersion(tx, attendance.ID, "update", change, models.NewAttendanceSnapshot(&current), models.NewAttendanceSnapshot(attendance))
+	})
+	if err != nil {
+		log.Printf("Error updating attendance record with ID %d: %v", attendance.ID, err)
+		return err
 	}
 	log.Printf("Attendance record with ID %d updated.", attendance.ID)
 	return nil
 }
 
+// recordAttendanceVersion appends a history version for an attendance record. Records created before
+// history was kept get a "baseline" version holding their original values first, so they can be reverted to.
+func recordAttendanceVersion(tx *gorm.DB, attendanceID int, action string, change models.AttendanceChange, before, after *models.AttendanceSnapshot) error {
+	var latestVersion int
+	if err := tx.Model(&models.AttendanceVersion{}).Where("attendance_id = ?", attendanceID).Select("COALESCE(MAX(version), 0)").Scan(&latestVersion).Error; err != nil {
+		return err
+	}
+
+	if latestVersion == 0 && before != nil {
+		latestVersion++
+		baseline := &models.AttendanceVersion{
+			AttendanceID: attendanceID,
+			Version:      latestVersion,
+			Action:       "baseline",
+			ActorType:    models.AttendanceActorSystem,
+			Reason:       "Values recorded before change history was kept.",
+			After:        before,
+		}
+		if err := tx.Create(baseline).Error; err != nil {
+			return err
+		}
+	}
+
+	if change.Action != "" {
+		action = change.Action
+	}
+	version := &models.AttendanceVersion{
+		AttendanceID: attendanceID,
+		Version:      latestVersion + 1,
+		Action:       action,
+		ActorType:    change.ActorType,
+		ActorID:      change.ActorID,
+		Reason:       change.Reason,
+		Before:       before,
+		After:        after,
+	}
+	return tx.Create(version).Error
+}
+
+// GetAttendanceByID retrieves an attendance record with its employee.
+func (r *attendanceRepository) GetAttendanceByID(id int) (*models.AttendancesTable, error) {
+	var attendance models.AttendancesTable
+	result := r.db.Preload("Employee").First(&attendance, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Attendance not found
+		}
+		log.Printf("Error getting attendance with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &attendance, nil
+}
+
+// GetAttendanceVersions retrieves the change history of an attendance record, oldest first.
+func (r *attendanceRepository) GetAttendanceVersions(attendanceID int) ([]models.AttendanceVersion, error) {
+	var versions []models.AttendanceVersion
+	if err := r.db.Where("attendance_id = ?", attendanceID).Order("version asc").Find(&versions).Error; err != nil {
+		log.Printf("Error getting versions for attendance %d: %v", attendanceID, err)
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetAttendanceVersion retrieves a single history version of an attendance record.
+func (r *attendanceRepository) GetAttendanceVersion(attendanceID, version int) (*models.AttendanceVersion, error) {
+	var attendanceVersion models.AttendanceVersion
+	result := r.db.Where("attendance_id = ? AND version = ?", attendanceID, version).First(&attendanceVersion)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Version not found
+		}
+		log.Printf("Error getting version %d of attendance %d: %v", version, attendanceID, result.Error)
+		return nil, result.Error
+	}
+	return &attendanceVersion, nil
+}
+
 // GetLatestAttendanceByEmployeeID retrieves the latest OPEN attendance record for an employee (check_out_time IS NULL).
 func (r *attendanceRepository) GetLatestAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error) {
 	var attendance models.AttendancesTable
@@ -71,10 +178,24 @@ func (r *attendanceRepository) GetLatestAttendanceForDate(employeeID int, date t
 	return &attendance, nil
 }
 
+// GetAttendancesForDate retrieves all records an employee has for a given date, oldest first.
+func (r *attendanceRepository) GetAttendancesForDate(employeeID int, date time.Time) ([]models.AttendancesTable, error) {
+	var attendances []models.AttendancesTable
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	result := r.db.Where("employee_id = ? AND check_in_time >= ? AND check_in_time < ?", employeeID, startOfDay, endOfDay).Order("check_in_time ASC").Find(&attendances)
+	if result.Error != nil {
+		log.Printf("Error getting attendances for employee %d on %s: %v", employeeID, date.Format("2006-01-02"), result.Error)
+		return nil, result.Error
+	}
+	return attendances, nil
+}
+
 // GetLatestOvertimeAttendanceByEmployeeID retrieves the latest overtime attendance record for an employee.
 func (r *attendanceRepository) GetLatestOvertimeAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error) {
 	var attendance models.AttendancesTable
-	result := r.db.Where("employee_id = ? AND (status = ? OR status = ?)", employeeID, "overtime_in", "overtime_out").Order("check_in_time DESC").Limit(1).First(&attendance)
+	result := r.db.Where("employee_id = ? AND (status = ? OR status = ?)", employeeID, models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut).Order("check_in_time DESC").Limit(1).First(&attendance)
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
@@ -87,13 +208,28 @@ func (r *attendanceRepository) GetLatestOvertimeAttendanceByEmployeeID(employeeI
 	return &attendance, nil
 }
 
+// GetOvertimeAttendancesForDate retrieves all overtime sessions an employee started on a given date.
+func (r *attendanceRepository) GetOvertimeAttendancesForDate(employeeID int, date time.Time) ([]models.AttendancesTable, error) {
+	var attendances []models.AttendancesTable
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	result := r.db.Where("employee_id = ? AND (status = ? OR status = ?) AND check_in_time >= ? AND check_in_time < ?", employeeID, models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut, startOfDay, endOfDay).
+		Order("check_in_time ASC").Find(&attendances)
+	if result.Error != nil {
+		log.Printf("Error getting overtime attendances for employee %d on %s: %v", employeeID, date.Format("2006-01-02"), result.Error)
+		return nil, result.Error
+	}
+	return attendances, nil
+}
+
 // GetPresentEmployeesCountToday retrieves the count of employees marked as 'present' for a given company today.
 func (r *attendanceRepository) GetPresentEmployeesCountToday(companyID int) (int64, error) {
 	var count int64
 	startOfDay := time.Now().Truncate(24 * time.Hour)
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	result := r.db.Model(&models.AttendancesTable{}).Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND attendances_tables.status = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ?", companyID, "present", startOfDay, endOfDay).Count(&count)
+	result := r.db.Model(&models.AttendancesTable{}).Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND attendances_tables.status = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ?", companyID, models.AttendanceStatusPresent, startOfDay, endOfDay).Count(&count)
 	if result.Error != nil {
 		log.Printf("Error getting present employees count today for company %d: %v", companyID, result.Error)
 		return 0, result.Error
@@ -107,7 +243,7 @@ func (r *attendanceRepository) GetAbsentEmployeesCountToday(companyID int) (int6
 	startOfDay := time.Now().Truncate(24 * time.Hour)
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	result := r.db.Model(&models.AttendancesTable{}).Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND attendances_tables.status = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ?", companyID, "absent", startOfDay, endOfDay).Count(&count)
+	result := r.db.Model(&models.AttendancesTable{}).Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND attendances_tables.status = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ?", companyID, models.AttendanceStatusAbsent, startOfDay, endOfDay).Count(&count)
 	if result.Error != nil {
 		log.Printf("Error getting absent employees count today for company %d: %v", companyID, result.Error)
 		return 0, result.Error
@@ -148,7 +284,7 @@ func (r *attendanceRepository) GetRecentOvertimeAttendancesByCompanyID(companyID
 	log.Printf("Repository: Fetching recent overtime attendances for company %d, limit %d", companyID, limit)
 	result := r.db.Preload("Employee").
 		Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").
-		Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, "overtime_in", "overtime_out").
+		Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut).
 		Order("check_in_time DESC").
 		Limit(limit).Find(&attendances)
 	if result.Error != nil {
@@ -186,7 +322,7 @@ func (r *attendanceRepository) GetCompanyAttendancesFiltered(companyID int, star
 
 	// Filter by attendance type
 	if attendanceType == "regular" {
-		query = query.Where("attendances_tables.status NOT IN (?, ?)", "overtime_in", "overtime_out")
+		query = query.Where("attendances_tables.status NOT IN (?, ?)", models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut)
 	}
 
 	if startDate != nil {
@@ -206,6 +342,43 @@ func (r *attendanceRepository) GetCompanyAttendancesFiltered(companyID int, star
 	return attendances, nil
 }
 
+// CountCompanyAttendancesFiltered counts a company's attendance records, optionally within a date range.
+func (r *attendanceRepository) CountCompanyAttendancesFiltered(companyID int, startDate, endDate *time.Time) (int64, error) {
+	var count int64
+	result := r.companyAttendancesInRange(companyID, startDate, endDate).Model(&models.AttendancesTable{}).Count(&count)
+	if result.Error != nil {
+		log.Printf("Error counting attendances for company %d: %v", companyID, result.Error)
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// FindCompanyAttendancesInBatches passes a company's attendance records, optionally within a date range, to fn in
+// batches of batchSize in ID order, so that large exports never hold all records in memory.
+func (r *attendanceRepository) FindCompanyAttendancesInBatches(companyID int, startDate, endDate *time.Time, batchSize int, fn func([]models.AttendancesTable) error) error {
+	var batch []models.AttendancesTable
+	result := r.companyAttendancesInRange(companyID, startDate, endDate).Preload("Employee").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	})
+	if result.Error != nil {
+		log.Printf("Error querying attendances in batches for company %d: %v", companyID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// companyAttendancesInRange selects a company's attendance records, with an inclusive end date.
+func (r *attendanceRepository) companyAttendancesInRange(companyID int, startDate, endDate *time.Time) *gorm.DB {
+	query := r.db.Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ?", companyID)
+	if startDate != nil {
+		query = query.Where("attendances_tables.check_in_time >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("attendances_tables.check_in_time < ?", (*endDate).Add(24*time.Hour))
+	}
+	return query
+}
+
 // HasAttendanceForDate checks if an employee has any attendance record for a specific date.
 func (r *attendanceRepository) HasAttendanceForDate(employeeID int, date time.Time) (bool, error) {
 	var count int64
@@ -243,7 +416,7 @@ func (r *attendanceRepository) HasAttendanceForDateRange(employeeID int, startDa
 // GetCompanyOvertimeAttendancesFiltered retrieves all overtime attendance records for a given company ID, optionally filtered by date range.
 func (r *attendanceRepository) GetCompanyOvertimeAttendancesFiltered(companyID int, startDate, endDate *time.Time) ([]models.AttendancesTable, error) {
 	var attendances []models.AttendancesTable
-	query := r.db.Preload("Employee").Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, "overtime_in", "overtime_out")
+	query := r.db.Preload("Employee").Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut)
 
 	if startDate != nil {
 		query = query.Where("attendances_tables.check_in_time >= ?", *startDate)
@@ -325,7 +498,7 @@ func (r *attendanceRepository) GetOvertimeAttendancesPaginated(companyID int, st
 	query := r.db.Model(&models.AttendancesTable{}).
 		Preload("Employee").
 		Joins("join employees_tables on employees_tables.id = attendances_tables.employee_id").
-		Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, "overtime_in", "overtime_out")
+		Where("employees_tables.company_id = ? AND (attendances_tables.status = ? OR attendances_tables.status = ?)", companyID, models.AttendanceStatusOvertimeIn, models.AttendanceStatusOvertimeOut)
 
 	// Apply date filters
 	if startDate != nil {
@@ -440,8 +613,8 @@ func (r *attendanceRepository) GetUnaccountedEmployeesFiltered(companyID int, st
 func (r *attendanceRepository) GetOvertimeAttendancesFiltered(companyID int, startDate, endDate *time.Time, search string) ([]models.AttendancesTable, error) {
 	var attendances []models.AttendancesTable
 	query := r.db.Preload("Employee").
-		Joins("JOIN employees ON employees.id = attendances_tables.employee_id").
-		Where("employees.company_id = ?", companyID).
+		Joins("JOIN employees_tables ON employees_tables.id = attendances_tables.employee_id").
+		Where("employees_tables.company_id = ?", companyID).
 		Where("attendances_tables.status LIKE ?", "%overtime%")
 
 	if startDate != nil {
@@ -451,22 +624,22 @@ func (r *attendanceRepository) GetOvertimeAttendancesFiltered(companyID int, sta
 		query = query.Where("attendances_tables.check_in_time <= ?", endDate.Add(23*time.Hour+59*time.Minute+59*time.Second))
 	}
 	if search != "" {
-		query = query.Where("employees.name LIKE ?", "%"+search+"%")
+		query = query.Where("employees_tables.name LIKE ?", "%"+search+"%")
 	}
 
 	err := query.Order("attendances_tables.check_in_time DESC").Find(&attendances).Error
 	return attendances, err
 }
 
-
+// FindIncompleteAttendancesByCompany retrieves the regular records of a day that were never checked out.
 func (r *attendanceRepository) FindIncompleteAttendancesByCompany(companyID int, date time.Time) ([]models.AttendancesTable, error) {
 	var attendances []models.AttendancesTable
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	result := r.db.Joins("JOIN employees_tables ON employees_tables.id = attendances_tables.employee_id").
-		Where("employees_tables.company_id = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ? AND attendances_tables.check_out_time IS NULL",
-			companyID, startOfDay, endOfDay).
+		Where("employees_tables.company_id = ? AND attendances_tables.check_in_time >= ? AND attendances_tables.check_in_time < ? AND attendances_tables.check_out_time IS NULL AND attendances_tables.status IN ?",
+			companyID, startOfDay, endOfDay, models.OpenAttendanceStatuses).
 		Find(&attendances)
 
 	if result.Error != nil {
@@ -476,6 +649,22 @@ func (r *attendanceRepository) FindIncompleteAttendancesByCompany(companyID int,
 	return attendances, nil
 }
 
+// FindOpenOvertimeAttendancesByCompany retrieves the overtime sessions of a company that were started before
+// the cutoff and never checked out.
+func (r *attendanceRepository) FindOpenOvertimeAttendancesByCompany(companyID int, startedBefore time.Time) ([]models.AttendancesTable, error) {
+	var attendances []models.AttendancesTable
+	result := r.db.Preload("Employee").Joins("JOIN employees_tables ON employees_tables.id = attendances_tables.employee_id").
+		Where("employees_tables.company_id = ? AND attendances_tables.status = ? AND attendances_tables.check_out_time IS NULL AND attendances_tables.check_in_time < ?",
+			companyID, models.AttendanceStatusOvertimeIn, startedBefore).
+		Find(&attendances)
+
+	if result.Error != nil {
+		log.Printf("Error finding open overtime attendances for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return attendances, nil
+}
+
 // GetTodayAttendanceByEmployeeID retrieves the latest attendance record for a specific employee for the current day.
 func (r *attendanceRepository) GetTodayAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error) {
 	var attendance models.AttendancesTable
@@ -7,11 +7,17 @@ import (
 
 // AttendanceRepository defines the contract for attendance-related database operations.
 type AttendanceRepository interface {
-	CreateAttendance(attendance *models.AttendancesTable) error
-	UpdateAttendance(attendance *models.AttendancesTable) error
+	LockEmployeeAttendance(employeeID int) (func(), error)
+	CreateAttendance(attendance *models.AttendancesTable, change models.AttendanceChange) error
+	UpdateAttendance(attendance *models.AttendancesTable, change models.AttendanceChange) error
+	GetAttendanceByID(id int) (*models.AttendancesTable, error)
+	GetAttendanceVersions(attendanceID int) ([]models.AttendanceVersion, error)
+	GetAttendanceVersion(attendanceID, version int) (*models.AttendanceVersion, error)
 	GetLatestAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error)
 	GetLatestAttendanceForDate(employeeID int, date time.Time) (*models.AttendancesTable, error)
+	GetAttendancesForDate(employeeID int, date time.Time) ([]models.AttendancesTable, error)
 	GetLatestOvertimeAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error)
+	GetOvertimeAttendancesForDate(employeeID int, date time.Time) ([]models.AttendancesTable, error)
 	GetPresentEmployeesCountToday(companyID int) (int64, error)
 	GetAbsentEmployeesCountToday(companyID int) (int64, error)
 	GetAttendancesByCompanyID(companyID int) ([]models.AttendancesTable, error)
@@ -19,6 +25,8 @@ type AttendanceRepository interface {
 	GetRecentOvertimeAttendancesByCompanyID(companyID int, limit int) ([]models.AttendancesTable, error)
 	GetEmployeeAttendances(employeeID int, startDate, endDate *time.Time) ([]models.AttendancesTable, error)
 	GetCompanyAttendancesFiltered(companyID int, startDate, endDate *time.Time, attendanceType string) ([]models.AttendancesTable, error)
+	CountCompanyAttendancesFiltered(companyID int, startDate, endDate *time.Time) (int64, error)
+	FindCompanyAttendancesInBatches(companyID int, startDate, endDate *time.Time, batchSize int, fn func([]models.AttendancesTable) error) error
 	HasAttendanceForDate(employeeID int, date time.Time) (bool, error)
 	HasAttendanceForDateRange(employeeID int, startDate, endDate *time.Time) (bool, error)
 	GetCompanyOvertimeAttendancesFiltered(companyID int, startDate, endDate *time.Time) ([]models.AttendancesTable, error)
@@ -28,6 +36,7 @@ type AttendanceRepository interface {
 	GetUnaccountedEmployeesFiltered(companyID int, startDate, endDate *time.Time, search string) ([]models.EmployeesTable, error)
 	GetOvertimeAttendancesFiltered(companyID int, startDate, endDate *time.Time, search string) ([]models.AttendancesTable, error)
 	FindIncompleteAttendancesByCompany(companyID int, date time.Time) ([]models.AttendancesTable, error)
+	FindOpenOvertimeAttendancesByCompany(companyID int, startedBefore time.Time) ([]models.AttendancesTable, error)
 	GetTodayAttendanceByEmployeeID(employeeID int) (*models.AttendancesTable, error)
 	GetRecentAttendancesByEmployeeID(employeeID int, limit int) ([]models.AttendancesTable, error)
 }
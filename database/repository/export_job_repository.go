@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type exportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewExportJobRepository(db *gorm.DB) ExportJobRepository {
+	return &exportJobRepository{db: db}
+}
+
+func (r *exportJobRepository) CreateExportJob(job *models.ExportJob) error {
+	if err := r.db.Create(job).Error; err != nil {
+		log.Printf("Error creating export job for company %d: %v", job.CompanyID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *exportJobRepository) GetExportJobByID(id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	result := r.db.First(&job, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Export job not found
+		}
+		log.Printf("Error getting export job with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// GetExportJobsPaginated retrieves a company's export jobs, newest first.
+func (r *exportJobRepository) GetExportJobsPaginated(companyID int, page, pageSize int) ([]models.ExportJob, int64, error) {
+	var jobs []models.ExportJob
+	var totalRecords int64
+
+	query := r.db.Model(&models.ExportJob{}).Where("company_id = ?", companyID)
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting export jobs: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&jobs).Error; err != nil {
+		log.Printf("Error getting paginated export jobs: %v", err)
+		return nil, 0, err
+	}
+
+	return jobs, totalRecords, nil
+}
+
+func (r *exportJobRepository) UpdateExportJob(job *models.ExportJob) error {
+	if err := r.db.Save(job).Error; err != nil {
+		log.Printf("Error updating export job with ID %d: %v", job.ID, err)
+		return err
+	}
+	return nil
+}
+
+// GetExpiredExportJobs retrieves completed exports whose files are past their expiry.
+func (r *exportJobRepository) GetExpiredExportJobs(now time.Time) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := r.db.Where("status = ? AND expires_at < ?", models.ExportJobStatusCompleted, now).Find(&jobs).Error
+	if err != nil {
+		log.Printf("Error getting expired export jobs: %v", err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// GetStaleExportJobs retrieves exports created before the given time that never finished, e.g. because the
+// server restarted while they were running.
+func (r *exportJobRepository) GetStaleExportJobs(createdBefore time.Time) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := r.db.Where("status IN ? AND created_at < ?", []string{models.ExportJobStatusPending, models.ExportJobStatusRunning}, createdBefore).Find(&jobs).Error
+	if err != nil {
+		log.Printf("Error getting stale export jobs: %v", err)
+		return nil, err
+	}
+	return jobs, nil
+}
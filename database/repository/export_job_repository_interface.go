@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// ExportJobRepository defines the contract for background export database operations.
+type ExportJobRepository interface {
+	CreateExportJob(job *models.ExportJob) error
+	GetExportJobByID(id uint) (*models.ExportJob, error)
+	GetExportJobsPaginated(companyID int, page, pageSize int) ([]models.ExportJob, int64, error)
+	UpdateExportJob(job *models.ExportJob) error
+	GetExpiredExportJobs(now time.Time) ([]models.ExportJob, error)
+	GetStaleExportJobs(createdBefore time.Time) ([]models.ExportJob, error)
+}
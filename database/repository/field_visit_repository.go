@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fieldVisitRepository struct {
+	db *gorm.DB
+}
+
+func NewFieldVisitRepository(db *gorm.DB) FieldVisitRepository {
+	return &fieldVisitRepository{db: db}
+}
+
+// CreateClientSite inserts a new client site.
+func (r *fieldVisitRepository) CreateClientSite(site *models.ClientSite) error {
+	result := r.db.Create(site)
+	if result.Error != nil {
+		log.Printf("Error creating client site: %v", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetClientSiteByID retrieves a client site by its ID.
+func (r *fieldVisitRepository) GetClientSiteByID(id uint) (*models.ClientSite, error) {
+	var site models.ClientSite
+	result := r.db.First(&site, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Client site not found
+		}
+		log.Printf("Error getting client site with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &site, nil
+}
+
+// GetClientSitesByCompanyID retrieves all client sites of a company.
+func (r *fieldVisitRepository) GetClientSitesByCompanyID(companyID uint) ([]models.ClientSite, error) {
+	var sites []models.ClientSite
+	result := r.db.Where("company_id = ?", companyID).Order("name ASC").Find(&sites)
+	if result.Error != nil {
+		log.Printf("Error getting client sites for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return sites, nil
+}
+
+// UpdateClientSite updates an existing client site.
+func (r *fieldVisitRepository) UpdateClientSite(site *models.ClientSite) error {
+	result := r.db.Save(site)
+	if result.Error != nil {
+		log.Printf("Error updating client site %d: %v", site.ID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteClientSite deletes a client site. Visits keep their reference for history.
+func (r *fieldVisitRepository) DeleteClientSite(id uint) error {
+	result := r.db.Delete(&models.ClientSite{}, id)
+	if result.Error != nil {
+		log.Printf("Error deleting client site %d: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// CreateFieldVisit inserts a new field visit.
+func (r *fieldVisitRepository) CreateFieldVisit(visit *models.FieldVisit) error {
+	result := r.db.Create(visit)
+	if result.Error != nil {
+		log.Printf("Error creating field visit: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Field visit created with ID: %d", visit.ID)
+	return nil
+}
+
+// GetFieldVisitByID retrieves a field visit by its ID.
+func (r *fieldVisitRepository) GetFieldVisitByID(id uint) (*models.FieldVisit, error) {
+	var visit models.FieldVisit
+	result := r.db.Preload("ClientSite").First(&visit, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Field visit not found
+		}
+		log.Printf("Error getting field visit with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &visit, nil
+}
+
+// UpdateFieldVisit updates an existing field visit.
+func (r *fieldVisitRepository) UpdateFieldVisit(visit *models.FieldVisit) error {
+	result := r.db.Omit("Employee", "ClientSite").Save(visit)
+	if result.Error != nil {
+		log.Printf("Error updating field visit %d: %v", visit.ID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetOpenFieldVisit retrieves the visit an employee has started but not yet ended.
+func (r *fieldVisitRepository) GetOpenFieldVisit(employeeID int) (*models.FieldVisit, error) {
+	var visit models.FieldVisit
+	result := r.db.Where("employee_id = ? AND ended_at IS NULL", employeeID).Order("started_at DESC").First(&visit)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // No visit in progress
+		}
+		log.Printf("Error getting open field visit for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return &visit, nil
+}
+
+// GetFieldVisitsByEmployeeID retrieves an employee's field visits, optionally filtered by date range.
+func (r *fieldVisitRepository) GetFieldVisitsByEmployeeID(employeeID int, startDate, endDate *time.Time) ([]models.FieldVisit, error) {
+	var visits []models.FieldVisit
+	query := r.db.Preload("ClientSite").Where("employee_id = ?", employeeID)
+
+	if startDate != nil {
+		query = query.Where("started_at >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("started_at < ?", endDate.AddDate(0, 0, 1)) // End date is inclusive
+	}
+
+	result := query.Order("started_at DESC").Find(&visits)
+	if result.Error != nil {
+		log.Printf("Error getting field visits for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return visits, nil
+}
+
+// GetCompanyFieldVisits retrieves the field visits of a company's employees started in [from, to), in start order.
+func (r *fieldVisitRepository) GetCompanyFieldVisits(companyID int, employeeID *int, from, to time.Time) ([]models.FieldVisit, error) {
+	var visits []models.FieldVisit
+	query := r.db.Joins("JOIN employees_tables ON field_visits.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID).
+		Where("field_visits.started_at >= ? AND field_visits.started_at < ?", from, to)
+	if employeeID != nil {
+		query = query.Where("field_visits.employee_id = ?", *employeeID)
+	}
+
+	result := query.Preload("Employee").Preload("ClientSite").Order("field_visits.started_at ASC").Find(&visits)
+	if result.Error != nil {
+		log.Printf("Error getting field visits for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return visits, nil
+}
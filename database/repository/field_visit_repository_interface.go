@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// FieldVisitRepository defines the contract for field visit and client site database operations.
+type FieldVisitRepository interface {
+	CreateClientSite(site *models.ClientSite) error
+	GetClientSiteByID(id uint) (*models.ClientSite, error)
+	GetClientSitesByCompanyID(companyID uint) ([]models.ClientSite, error)
+	UpdateClientSite(site *models.ClientSite) error
+	DeleteClientSite(id uint) error
+
+	CreateFieldVisit(visit *models.FieldVisit) error
+	GetFieldVisitByID(id uint) (*models.FieldVisit, error)
+	UpdateFieldVisit(visit *models.FieldVisit) error
+	GetOpenFieldVisit(employeeID int) (*models.FieldVisit, error)
+	GetFieldVisitsByEmployeeID(employeeID int, startDate, endDate *time.Time) ([]models.FieldVisit, error)
+	GetCompanyFieldVisits(companyID int, employeeID *int, from, to time.Time) ([]models.FieldVisit, error)
+}
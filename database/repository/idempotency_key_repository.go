@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// CreateIdempotencyKey inserts a new idempotency key. It fails when the key is already stored for the
+// same company and endpoint, which is what makes concurrent duplicates lose the race.
+func (r *idempotencyKeyRepository) CreateIdempotencyKey(key *models.IdempotencyKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		log.Printf("Error creating idempotency key %q: %v", key.Key, err)
+		return err
+	}
+	return nil
+}
+
+// GetIdempotencyKey retrieves a stored idempotency key of a company for an endpoint.
+func (r *idempotencyKeyRepository) GetIdempotencyKey(companyID int, endpoint, key string) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	result := r.db.Where("company_id = ? AND endpoint = ? AND `key` = ?", companyID, endpoint, key).First(&idempotencyKey)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Key not seen before
+		}
+		log.Printf("Error getting idempotency key %q: %v", key, result.Error)
+		return nil, result.Error
+	}
+	return &idempotencyKey, nil
+}
+
+// UpdateIdempotencyKey stores the outcome of the request.
+func (r *idempotencyKeyRepository) UpdateIdempotencyKey(key *models.IdempotencyKey) error {
+	if err := r.db.Save(key).Error; err != nil {
+		log.Printf("Error updating idempotency key with ID %d: %v", key.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey removes an idempotency key so that the request can be retried.
+func (r *idempotencyKeyRepository) DeleteIdempotencyKey(id uint) error {
+	if err := r.db.Delete(&models.IdempotencyKey{}, id).Error; err != nil {
+		log.Printf("Error deleting idempotency key with ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes the keys whose replay window has passed.
+func (r *idempotencyKeyRepository) DeleteExpiredIdempotencyKeys(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		log.Printf("Error deleting expired idempotency keys: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
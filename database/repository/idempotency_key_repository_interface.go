@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// IdempotencyKeyRepository defines the contract for stored idempotent request outcomes.
+type IdempotencyKeyRepository interface {
+	CreateIdempotencyKey(key *models.IdempotencyKey) error
+	GetIdempotencyKey(companyID int, endpoint, key string) (*models.IdempotencyKey, error)
+	UpdateIdempotencyKey(key *models.IdempotencyKey) error
+	DeleteIdempotencyKey(id uint) error
+	DeleteExpiredIdempotencyKeys(now time.Time) (int64, error)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"fmt"
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type kioskRepository struct {
+	db *gorm.DB
+}
+
+func NewKioskRepository(db *gorm.DB) KioskRepository {
+	return &kioskRepository{db: db}
+}
+
+// CreateKioskDevice inserts a new kiosk device.
+func (r *kioskRepository) CreateKioskDevice(device *models.KioskDevice) error {
+	result := r.db.Create(device)
+	if result.Error != nil {
+		log.Printf("Error creating kiosk device: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Kiosk device created with ID: %d", device.ID)
+	return nil
+}
+
+// GetKioskDeviceByID retrieves a kiosk device by its ID.
+func (r *kioskRepository) GetKioskDeviceByID(id uint) (*models.KioskDevice, error) {
+	var device models.KioskDevice
+	result := r.db.First(&device, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Kiosk device not found
+		}
+		log.Printf("Error getting kiosk device with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &device, nil
+}
+
+// GetKioskDevicesByCompanyID retrieves all kiosk devices registered for a company.
+func (r *kioskRepository) GetKioskDevicesByCompanyID(companyID int) ([]models.KioskDevice, error) {
+	var devices []models.KioskDevice
+	if err := r.db.Where("company_id = ?", companyID).Order("created_at DESC").Find(&devices).Error; err != nil {
+		log.Printf("Error getting kiosk devices for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return devices, nil
+}
+
+// UpdateKioskDevice updates an existing kiosk device.
+func (r *kioskRepository) UpdateKioskDevice(device *models.KioskDevice) error {
+	result := r.db.Save(device)
+	if result.Error != nil {
+		log.Printf("Error updating kiosk device with ID %d: %v", device.ID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// LockKioskDevice runs fn while holding the device's lock, so that its uploads are processed one at a time across
+// every instance of the API, and then saves the last sequence and sync time fn left on the device.
+func (r *kioskRepository) LockKioskDevice(id uint, fn func(device *models.KioskDevice) error) error {
+	unlock, err := acquireNamedLock(r.db, fmt.Sprintf("kiosk_device:%d", id))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var device models.KioskDevice
+	if err := r.db.First(&device, id).Error; err != nil {
+		log.Printf("Error getting kiosk device with ID %d: %v", id, err)
+		return err
+	}
+	if err := fn(&device); err != nil {
+		return err
+	}
+	if err := r.db.Model(&device).Select("last_sequence", "last_synced_at").Updates(&device).Error; err != nil {
+		log.Printf("Error updating kiosk device with ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// CreateKioskBatch inserts a kiosk batch together with its entries.
+func (r *kioskRepository) CreateKioskBatch(batch *models.KioskBatch) error {
+	result := r.db.Create(batch)
+	if result.Error != nil {
+		log.Printf("Error creating kiosk batch: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Kiosk batch created with ID: %d (%d entries)", batch.ID, len(batch.Entries))
+	return nil
+}
+
+// UpdateKioskBatch saves a kiosk batch's status and counts; its entries are saved on their own.
+func (r *kioskRepository) UpdateKioskBatch(batch *models.KioskBatch) error {
+	if err := r.db.Omit(clause.Associations).Save(batch).Error; err != nil {
+		log.Printf("Error updating kiosk batch with ID %d: %v", batch.ID, err)
+		return err
+	}
+	return nil
+}
+
+// ClaimKioskEntry inserts an entry that holds its accepted sequence, reporting false without an error when another
+// entry of the device already holds that sequence.
+func (r *kioskRepository) ClaimKioskEntry(entry *models.KioskEntry) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		log.Printf("Error claiming sequence %d of kiosk device %d: %v", entry.Sequence, entry.KioskDeviceID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveKioskEntry inserts or updates a kiosk entry.
+func (r *kioskRepository) SaveKioskEntry(entry *models.KioskEntry) error {
+	if err := r.db.Save(entry).Error; err != nil {
+		log.Printf("Error saving kiosk entry for kiosk device %d: %v", entry.KioskDeviceID, err)
+		return err
+	}
+	return nil
+}
+
+// GetKioskBatchByID retrieves a kiosk batch with its device and entries.
+func (r *kioskRepository) GetKioskBatchByID(id uint) (*models.KioskBatch, error) {
+	var batch models.KioskBatch
+	result := r.db.Preload("KioskDevice").Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sequence ASC")
+	}).First(&batch, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Kiosk batch not found
+		}
+		log.Printf("Error getting kiosk batch with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &batch, nil
+}
+
+// GetKioskBatchesPaginated retrieves a company's kiosk batches, optionally filtered by status.
+func (r *kioskRepository) GetKioskBatchesPaginated(companyID int, status string, page, pageSize int) ([]models.KioskBatch, int64, error) {
+	var batches []models.KioskBatch
+	var totalRecords int64
+
+	query := r.db.Model(&models.KioskBatch{}).Where("company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting kiosk batches: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("KioskDevice").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&batches)
+
+	if result.Error != nil {
+		log.Printf("Error getting paginated kiosk batches: %v", result.Error)
+		return nil, 0, result.Error
+	}
+
+	return batches, totalRecords, nil
+}
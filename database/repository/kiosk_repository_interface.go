@@ -0,0 +1,18 @@
+package repository
+
+import "go-face-auth/models"
+
+// KioskRepository defines the contract for kiosk device and offline batch database operations.
+type KioskRepository interface {
+	CreateKioskDevice(device *models.KioskDevice) error
+	GetKioskDeviceByID(id uint) (*models.KioskDevice, error)
+	GetKioskDevicesByCompanyID(companyID int) ([]models.KioskDevice, error)
+	UpdateKioskDevice(device *models.KioskDevice) error
+	LockKioskDevice(id uint, fn func(device *models.KioskDevice) error) error
+	CreateKioskBatch(batch *models.KioskBatch) error
+	UpdateKioskBatch(batch *models.KioskBatch) error
+	ClaimKioskEntry(entry *models.KioskEntry) (bool, error)
+	SaveKioskEntry(entry *models.KioskEntry) error
+	GetKioskBatchByID(id uint) (*models.KioskBatch, error)
+	GetKioskBatchesPaginated(companyID int, status string, page, pageSize int) ([]models.KioskBatch, int64, error)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type leaveBalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveBalanceRepository(db *gorm.DB) LeaveBalanceRepository {
+	return &leaveBalanceRepository{db: db}
+}
+
+// GetLeavePolicyByCompanyID retrieves the leave policy configured for a company.
+func (r *leaveBalanceRepository) GetLeavePolicyByCompanyID(companyID int) (*models.LeavePolicy, error) {
+	var policy models.LeavePolicy
+	result := r.db.Where("company_id = ?", companyID).First(&policy)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Company does not track leave balances
+		}
+		log.Printf("Error getting leave policy for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return &policy, nil
+}
+
+// GetLeavePolicies retrieves the leave policies of all companies.
+func (r *leaveBalanceRepository) GetLeavePolicies() ([]models.LeavePolicy, error) {
+	var policies []models.LeavePolicy
+	if err := r.db.Order("company_id").Find(&policies).Error; err != nil {
+		log.Printf("Error getting leave policies: %v", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SaveLeavePolicy creates or updates a company's leave policy.
+func (r *leaveBalanceRepository) SaveLeavePolicy(policy *models.LeavePolicy) error {
+	if err := r.db.Save(policy).Error; err != nil {
+		log.Printf("Error saving leave policy for company %d: %v", policy.CompanyID, err)
+		return err
+	}
+	return nil
+}
+
+// CreateLeaveLedgerEntry posts an entry, reporting false without an error when an entry with the same reference
+// has already been posted.
+func (r *leaveBalanceRepository) CreateLeaveLedgerEntry(entry *models.LeaveLedgerEntry) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		log.Printf("Error creating %s leave ledger entry for employee %d: %v", entry.Kind, entry.EmployeeID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetLeaveLedgerEntriesByEmployeeID retrieves an employee's ledger in the order it was posted.
+func (r *leaveBalanceRepository) GetLeaveLedgerEntriesByEmployeeID(employeeID int) ([]models.LeaveLedgerEntry, error) {
+	var entries []models.LeaveLedgerEntry
+	if err := r.db.Where("employee_id = ?", employeeID).Order("year, effective_date, id").Find(&entries).Error; err != nil {
+		log.Printf("Error getting leave ledger for employee %d: %v", employeeID, err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetLeaveLedgerEntriesByLeaveRequestID retrieves the debits and credits posted for a leave request.
+func (r *leaveBalanceRepository) GetLeaveLedgerEntriesByLeaveRequestID(leaveRequestID uint) ([]models.LeaveLedgerEntry, error) {
+	var entries []models.LeaveLedgerEntry
+	if err := r.db.Where("leave_request_id = ?", leaveRequestID).Order("id").Find(&entries).Error; err != nil {
+		log.Printf("Error getting leave ledger entries for leave request %d: %v", leaveRequestID, err)
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "go-face-auth/models"
+
+// LeaveBalanceRepository defines the contract for leave policy and leave ledger database operations.
+type LeaveBalanceRepository interface {
+	GetLeavePolicyByCompanyID(companyID int) (*models.LeavePolicy, error)
+	GetLeavePolicies() ([]models.LeavePolicy, error)
+	SaveLeavePolicy(policy *models.LeavePolicy) error
+	CreateLeaveLedgerEntry(entry *models.LeaveLedgerEntry) (bool, error)
+	GetLeaveLedgerEntriesByEmployeeID(employeeID int) ([]models.LeaveLedgerEntry, error)
+	GetLeaveLedgerEntriesByLeaveRequestID(leaveRequestID uint) ([]models.LeaveLedgerEntry, error)
+}
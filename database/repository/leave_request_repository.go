@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type leaveRequestRepository struct {
@@ -109,6 +110,27 @@ func (r *leaveRequestRepository) UpdateLeaveRequest(leaveRequest *models.LeaveRe
 	return nil
 }
 
+// UpdateLeaveRequestWithLedgerEntries updates a leave request and posts the leave ledger entries its new status
+// calls for in one transaction, so a review never lands without its debit or credit. Entries whose reference has
+// already been posted are skipped.
+func (r *leaveRequestRepository) UpdateLeaveRequestWithLedgerEntries(leaveRequest *models.LeaveRequest, entries []models.LeaveLedgerEntry) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(leaveRequest).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entries).Error
+	})
+	if err != nil {
+		log.Printf("Error updating leave request %d with its leave ledger entries: %v", leaveRequest.ID, err)
+		return err
+	}
+	log.Printf("Leave request updated with ID: %d", leaveRequest.ID)
+	return nil
+}
+
 // GetRecentLeaveRequestsByCompanyID retrieves recent leave requests for a given company ID.
 func (r *leaveRequestRepository) GetRecentLeaveRequestsByCompanyID(companyID int, limit int) ([]models.LeaveRequest, error) {
 	var leaveRequests []models.LeaveRequest
@@ -229,4 +251,20 @@ func (r *leaveRequestRepository) GetOnLeaveEmployeesCountToday(companyID int) (i
 		return 0, result.Error
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+// GetApprovedLeaveRequestsOverlapping retrieves all approved leave requests of a company that overlap the given date range.
+func (r *leaveRequestRepository) GetApprovedLeaveRequestsOverlapping(companyID int, startDate, endDate time.Time) ([]models.LeaveRequest, error) {
+	var leaveRequests []models.LeaveRequest
+	result := r.db.Preload("Employee").
+		Joins("JOIN employees_tables ON leave_requests.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ? AND leave_requests.status = ?", companyID, "approved").
+		Where("leave_requests.end_date >= ? AND leave_requests.start_date <= ?", startDate, endDate.Add(23*time.Hour+59*time.Minute+59*time.Second)).
+		Order("leave_requests.start_date ASC").
+		Find(&leaveRequests)
+	if result.Error != nil {
+		log.Printf("Error getting approved leave requests for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return leaveRequests, nil
+}
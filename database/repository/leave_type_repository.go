@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type leaveTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveTypeRepository(db *gorm.DB) LeaveTypeRepository {
+	return &leaveTypeRepository{db: db}
+}
+
+// CreateLeaveType adds a leave type, reporting false without an error when the company already has its code.
+func (r *leaveTypeRepository) CreateLeaveType(leaveType *models.LeaveType) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(leaveType)
+	if result.Error != nil {
+		log.Printf("Error creating leave type %q for company %d: %v", leaveType.Code, leaveType.CompanyID, result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *leaveTypeRepository) GetLeaveTypeByID(id uint) (*models.LeaveType, error) {
+	var leaveType models.LeaveType
+	result := r.db.First(&leaveType, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Leave type not found
+		}
+		log.Printf("Error getting leave type with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &leaveType, nil
+}
+
+// GetLeaveTypesByCompanyID retrieves a company's leave types, active or not, in the order they were added.
+func (r *leaveTypeRepository) GetLeaveTypesByCompanyID(companyID int) ([]models.LeaveType, error) {
+	var leaveTypes []models.LeaveType
+	if err := r.db.Where("company_id = ?", companyID).Order("id").Find(&leaveTypes).Error; err != nil {
+		log.Printf("Error getting leave types for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return leaveTypes, nil
+}
+
+func (r *leaveTypeRepository) UpdateLeaveType(leaveType *models.LeaveType) error {
+	if err := r.db.Save(leaveType).Error; err != nil {
+		log.Printf("Error updating leave type with ID %d: %v", leaveType.ID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "go-face-auth/models"
+
+// LeaveTypeRepository defines the contract for leave type catalogue database operations.
+type LeaveTypeRepository interface {
+	CreateLeaveType(leaveType *models.LeaveType) (bool, error)
+	GetLeaveTypeByID(id uint) (*models.LeaveType, error)
+	GetLeaveTypesByCompanyID(companyID int) ([]models.LeaveType, error)
+	UpdateLeaveType(leaveType *models.LeaveType) error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// namedLockTimeout is how long acquiring a named lock waits for its current holder.
+const namedLockTimeout = 30 * time.Second
+
+// ErrLockTimeout is returned when a named lock is still held by someone else after namedLockTimeout.
+var ErrLockTimeout = errors.New("timed out waiting for a database lock")
+
+// acquireNamedLock takes a MySQL named lock on a connection of its own, so that it holds across every running
+// instance of the API, and returns the function that releases it. Row locks are not used for this, because the
+// inserts made while the lock is held check their foreign keys against the rows that would be locked.
+func acquireNamedLock(db *gorm.DB, name string) (func(), error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		log.Printf("Error getting a connection for lock %s: %v", name, err)
+		return nil, err
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(namedLockTimeout.Seconds())).Scan(&acquired); err != nil {
+		conn.Close()
+		log.Printf("Error acquiring lock %s: %v", name, err)
+		return nil, err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, ErrLockTimeout
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", name); err != nil {
+			log.Printf("Error releasing lock %s: %v", name, err)
+			// A session that may still hold the lock must not go back to the pool; closing it releases the lock.
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
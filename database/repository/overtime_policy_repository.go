@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type overtimePolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewOvertimePolicyRepository(db *gorm.DB) OvertimePolicyRepository {
+	return &overtimePolicyRepository{db: db}
+}
+
+// GetOvertimePolicyByCompanyID retrieves the overtime policy configured for a company.
+func (r *overtimePolicyRepository) GetOvertimePolicyByCompanyID(companyID int) (*models.OvertimePolicy, error) {
+	var policy models.OvertimePolicy
+	result := r.db.Where("company_id = ?", companyID).First(&policy)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Company still uses the default policy
+		}
+		log.Printf("Error getting overtime policy for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return &policy, nil
+}
+
+// SaveOvertimePolicy creates or updates a company's overtime policy.
+func (r *overtimePolicyRepository) SaveOvertimePolicy(policy *models.OvertimePolicy) error {
+	result := r.db.Save(policy)
+	if result.Error != nil {
+		log.Printf("Error saving overtime policy for company %d: %v", policy.CompanyID, result.Error)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "go-face-auth/models"
+
+// OvertimePolicyRepository defines the contract for overtime policy database operations.
+type OvertimePolicyRepository interface {
+	GetOvertimePolicyByCompanyID(companyID int) (*models.OvertimePolicy, error)
+	SaveOvertimePolicy(policy *models.OvertimePolicy) error
+}
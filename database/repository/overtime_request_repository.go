@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type overtimeRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewOvertimeRequestRepository(db *gorm.DB) OvertimeRequestRepository {
+	return &overtimeRequestRepository{db: db}
+}
+
+// CreateOvertimeRequest inserts a new overtime request into the database.
+func (r *overtimeRequestRepository) CreateOvertimeRequest(overtimeRequest *models.OvertimeRequest) error {
+	result := r.db.Create(overtimeRequest)
+	if result.Error != nil {
+		log.Printf("Error creating overtime request: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Overtime request created with ID: %d", overtimeRequest.ID)
+	return nil
+}
+
+// GetOvertimeRequestByID retrieves an overtime request by its ID.
+func (r *overtimeRequestRepository) GetOvertimeRequestByID(id uint) (*models.OvertimeRequest, error) {
+	var overtimeRequest models.OvertimeRequest
+	result := r.db.Preload("Employee").First(&overtimeRequest, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Overtime request not found
+		}
+		log.Printf("Error getting overtime request with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &overtimeRequest, nil
+}
+
+// UpdateOvertimeRequest updates an existing overtime request record in the database.
+func (r *overtimeRequestRepository) UpdateOvertimeRequest(overtimeRequest *models.OvertimeRequest) error {
+	result := r.db.Save(overtimeRequest)
+	if result.Error != nil {
+		log.Printf("Error updating overtime request: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Overtime request updated with ID: %d", overtimeRequest.ID)
+	return nil
+}
+
+// GetOvertimeRequestsByEmployeeID retrieves all overtime requests for a given employee ID, optionally filtered by date range.
+func (r *overtimeRequestRepository) GetOvertimeRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.OvertimeRequest, error) {
+	var overtimeRequests []models.OvertimeRequest
+	query := r.db.Where("employee_id = ?", employeeID)
+
+	if startDate != nil {
+		query = query.Where("date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("date <= ?", endDate.Format("2006-01-02"))
+	}
+
+	result := query.Order("date DESC").Find(&overtimeRequests)
+	if result.Error != nil {
+		log.Printf("Error getting overtime requests for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return overtimeRequests, nil
+}
+
+// GetCompanyOvertimeRequestsPaginated retrieves paginated and filtered overtime requests for a company.
+func (r *overtimeRequestRepository) GetCompanyOvertimeRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.OvertimeRequest, int64, error) {
+	var overtimeRequests []models.OvertimeRequest
+	var totalRecords int64
+
+	query := r.db.Model(&models.OvertimeRequest{}).
+		Joins("JOIN employees_tables ON overtime_requests.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID)
+
+	if status != "" {
+		query = query.Where("overtime_requests.status = ?", status)
+	}
+	if search != "" {
+		query = query.Where("employees_tables.name LIKE ?", "%"+search+"%")
+	}
+	if startDate != nil {
+		query = query.Where("overtime_requests.date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("overtime_requests.date <= ?", endDate.Format("2006-01-02"))
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting overtime requests: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Employee").
+		Order("overtime_requests.created_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&overtimeRequests)
+
+	if result.Error != nil {
+		log.Printf("Error getting paginated overtime requests: %v", result.Error)
+		return nil, 0, result.Error
+	}
+
+	return overtimeRequests, totalRecords, nil
+}
+
+// GetApprovedOvertimeRequestForDate retrieves the approved overtime request of an employee for a given date.
+func (r *overtimeRequestRepository) GetApprovedOvertimeRequestForDate(employeeID int, date time.Time) (*models.OvertimeRequest, error) {
+	var overtimeRequest models.OvertimeRequest
+	result := r.db.Where("employee_id = ? AND status = ? AND date = ?", employeeID, "approved", date.Format("2006-01-02")).
+		Order("updated_at DESC").First(&overtimeRequest)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // No approved overtime request for this date
+		}
+		log.Printf("Error getting approved overtime request for employee %d on %s: %v", employeeID, date.Format("2006-01-02"), result.Error)
+		return nil, result.Error
+	}
+	return &overtimeRequest, nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// OvertimeRequestRepository defines the contract for overtime_request-related database operations.
+type OvertimeRequestRepository interface {
+	CreateOvertimeRequest(overtimeRequest *models.OvertimeRequest) error
+	GetOvertimeRequestByID(id uint) (*models.OvertimeRequest, error)
+	UpdateOvertimeRequest(overtimeRequest *models.OvertimeRequest) error
+	GetOvertimeRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.OvertimeRequest, error)
+	GetCompanyOvertimeRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.OvertimeRequest, int64, error)
+	GetApprovedOvertimeRequestForDate(employeeID int, date time.Time) (*models.OvertimeRequest, error)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type publicHolidayRepository struct {
+	db *gorm.DB
+}
+
+func NewPublicHolidayRepository(db *gorm.DB) PublicHolidayRepository {
+	return &publicHolidayRepository{db: db}
+}
+
+// CreatePublicHoliday inserts a new public holiday into the database.
+func (r *publicHolidayRepository) CreatePublicHoliday(holiday *models.PublicHoliday) error {
+	result := r.db.Create(holiday)
+	if result.Error != nil {
+		log.Printf("Error creating public holiday: %v", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetPublicHolidayByID retrieves a public holiday by its ID.
+func (r *publicHolidayRepository) GetPublicHolidayByID(id uint) (*models.PublicHoliday, error) {
+	var holiday models.PublicHoliday
+	result := r.db.First(&holiday, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		log.Printf("Error getting public holiday with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &holiday, nil
+}
+
+// GetPublicHolidaysByCompanyID retrieves a company's public holidays, optionally filtered by date range.
+func (r *publicHolidayRepository) GetPublicHolidaysByCompanyID(companyID int, startDate, endDate *time.Time) ([]models.PublicHoliday, error) {
+	var holidays []models.PublicHoliday
+	query := r.db.Where("company_id = ?", companyID)
+
+	if startDate != nil {
+		query = query.Where("date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("date <= ?", endDate.Format("2006-01-02"))
+	}
+
+	result := query.Order("date ASC").Find(&holidays)
+	if result.Error != nil {
+		log.Printf("Error getting public holidays for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return holidays, nil
+}
+
+// DeletePublicHoliday removes a public holiday by its ID. The row is deleted for good, so that the date can be
+// added again without tripping the company/date unique index.
+func (r *publicHolidayRepository) DeletePublicHoliday(id uint) error {
+	result := r.db.Unscoped().Delete(&models.PublicHoliday{}, id)
+	if result.Error != nil {
+		log.Printf("Error deleting public holiday with ID %d: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// PublicHolidayRepository defines the contract for public holiday database operations.
+type PublicHolidayRepository interface {
+	CreatePublicHoliday(holiday *models.PublicHoliday) error
+	GetPublicHolidayByID(id uint) (*models.PublicHoliday, error)
+	GetPublicHolidaysByCompanyID(companyID int, startDate, endDate *time.Time) ([]models.PublicHoliday, error)
+	DeletePublicHoliday(id uint) error
+}
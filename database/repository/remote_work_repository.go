@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type remoteWorkRepository struct {
+	db *gorm.DB
+}
+
+func NewRemoteWorkRepository(db *gorm.DB) RemoteWorkRepository {
+	return &remoteWorkRepository{db: db}
+}
+
+// GetRemoteWorkPolicyByCompanyID retrieves the remote work policy configured for a company.
+func (r *remoteWorkRepository) GetRemoteWorkPolicyByCompanyID(companyID int) (*models.RemoteWorkPolicy, error) {
+	var policy models.RemoteWorkPolicy
+	result := r.db.Where("company_id = ?", companyID).First(&policy)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Company still uses the default policy
+		}
+		log.Printf("Error getting remote work policy for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return &policy, nil
+}
+
+// SaveRemoteWorkPolicy creates or updates a company's remote work policy.
+func (r *remoteWorkRepository) SaveRemoteWorkPolicy(policy *models.RemoteWorkPolicy) error {
+	result := r.db.Save(policy)
+	if result.Error != nil {
+		log.Printf("Error saving remote work policy for company %d: %v", policy.CompanyID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// CreateRemoteWorkSchedule inserts a new remote work schedule.
+func (r *remoteWorkRepository) CreateRemoteWorkSchedule(schedule *models.RemoteWorkSchedule) error {
+	result := r.db.Create(schedule)
+	if result.Error != nil {
+		log.Printf("Error creating remote work schedule: %v", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetRemoteWorkScheduleByID retrieves a remote work schedule by its ID.
+func (r *remoteWorkRepository) GetRemoteWorkScheduleByID(id uint) (*models.RemoteWorkSchedule, error) {
+	var schedule models.RemoteWorkSchedule
+	result := r.db.First(&schedule, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Schedule not found
+		}
+		log.Printf("Error getting remote work schedule with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+// GetRemoteWorkSchedulesByCompanyID retrieves all remote work schedules of a company.
+func (r *remoteWorkRepository) GetRemoteWorkSchedulesByCompanyID(companyID int) ([]models.RemoteWorkSchedule, error) {
+	var schedules []models.RemoteWorkSchedule
+	result := r.db.Preload("Employee").Where("company_id = ?", companyID).Order("created_at DESC").Find(&schedules)
+	if result.Error != nil {
+		log.Printf("Error getting remote work schedules for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return schedules, nil
+}
+
+// GetRemoteWorkSchedulesForEmployee retrieves the schedules assigned to an employee directly or through their division.
+func (r *remoteWorkRepository) GetRemoteWorkSchedulesForEmployee(employeeID int, divisionID *int) ([]models.RemoteWorkSchedule, error) {
+	var schedules []models.RemoteWorkSchedule
+	query := r.db.Where("employee_id = ?", employeeID)
+	if divisionID != nil {
+		query = r.db.Where("employee_id = ? OR division_id = ?", employeeID, *divisionID)
+	}
+	result := query.Find(&schedules)
+	if result.Error != nil {
+		log.Printf("Error getting remote work schedules for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return schedules, nil
+}
+
+// DeleteRemoteWorkSchedule deletes a remote work schedule.
+func (r *remoteWorkRepository) DeleteRemoteWorkSchedule(id uint) error {
+	result := r.db.Delete(&models.RemoteWorkSchedule{}, id)
+	if result.Error != nil {
+		log.Printf("Error deleting remote work schedule %d: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// CreateRemoteWorkRequest inserts a new remote work request.
+func (r *remoteWorkRepository) CreateRemoteWorkRequest(remoteWorkRequest *models.RemoteWorkRequest) error {
+	result := r.db.Create(remoteWorkRequest)
+	if result.Error != nil {
+		log.Printf("Error creating remote work request: %v", result.Error)
+		return result.Error
+	}
+	log.Printf("Remote work request created with ID: %d", remoteWorkRequest.ID)
+	return nil
+}
+
+// GetRemoteWorkRequestByID retrieves a remote work request by its ID.
+func (r *remoteWorkRepository) GetRemoteWorkRequestByID(id uint) (*models.RemoteWorkRequest, error) {
+	var remoteWorkRequest models.RemoteWorkRequest
+	result := r.db.Preload("Employee").First(&remoteWorkRequest, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Remote work request not found
+		}
+		log.Printf("Error getting remote work request with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &remoteWorkRequest, nil
+}
+
+// UpdateRemoteWorkRequest updates an existing remote work request.
+func (r *remoteWorkRepository) UpdateRemoteWorkRequest(remoteWorkRequest *models.RemoteWorkRequest) error {
+	result := r.db.Save(remoteWorkRequest)
+	if result.Error != nil {
+		log.Printf("Error updating remote work request: %v", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// GetRemoteWorkRequestsByEmployeeID retrieves an employee's remote work requests overlapping the given date range.
+func (r *remoteWorkRepository) GetRemoteWorkRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.RemoteWorkRequest, error) {
+	var remoteWorkRequests []models.RemoteWorkRequest
+	query := r.db.Where("employee_id = ?", employeeID)
+
+	if startDate != nil {
+		query = query.Where("end_date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("start_date <= ?", endDate.Format("2006-01-02"))
+	}
+
+	result := query.Order("start_date DESC").Find(&remoteWorkRequests)
+	if result.Error != nil {
+		log.Printf("Error getting remote work requests for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return remoteWorkRequests, nil
+}
+
+// GetCompanyRemoteWorkRequestsPaginated retrieves paginated and filtered remote work requests for a company.
+func (r *remoteWorkRepository) GetCompanyRemoteWorkRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.RemoteWorkRequest, int64, error) {
+	var remoteWorkRequests []models.RemoteWorkRequest
+	var totalRecords int64
+
+	query := r.db.Model(&models.RemoteWorkRequest{}).
+		Joins("JOIN employees_tables ON remote_work_requests.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID)
+
+	if status != "" {
+		query = query.Where("remote_work_requests.status = ?", status)
+	}
+	if search != "" {
+		query = query.Where("employees_tables.name LIKE ?", "%"+search+"%")
+	}
+	if startDate != nil {
+		query = query.Where("remote_work_requests.end_date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("remote_work_requests.start_date <= ?", endDate.Format("2006-01-02"))
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting remote work requests: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	result := query.Preload("Employee").
+		Order("remote_work_requests.created_at DESC").
+		Offset(offset).
+		Limit(pageSize).Find(&remoteWorkRequests)
+
+	if result.Error != nil {
+		log.Printf("Error getting paginated remote work requests: %v", result.Error)
+		return nil, 0, result.Error
+	}
+
+	return remoteWorkRequests, totalRecords, nil
+}
+
+// GetApprovedRemoteWorkRequestForDate retrieves the approved remote work request covering an employee's date.
+func (r *remoteWorkRepository) GetApprovedRemoteWorkRequestForDate(employeeID int, date time.Time) (*models.RemoteWorkRequest, error) {
+	var remoteWorkRequest models.RemoteWorkRequest
+	day := date.Format("2006-01-02")
+	result := r.db.Where("employee_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", employeeID, "approved", day, day).
+		Order("updated_at DESC").First(&remoteWorkRequest)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // No approved remote work request for this date
+		}
+		log.Printf("Error getting approved remote work request for employee %d on %s: %v", employeeID, day, result.Error)
+		return nil, result.Error
+	}
+	return &remoteWorkRequest, nil
+}
+
+// GetHomeLocationByEmployeeID retrieves the home location registered by an employee.
+func (r *remoteWorkRepository) GetHomeLocationByEmployeeID(employeeID uint) (*models.EmployeeHomeLocation, error) {
+	var homeLocation models.EmployeeHomeLocation
+	result := r.db.Where("employee_id = ?", employeeID).First(&homeLocation)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // No home location registered
+		}
+		log.Printf("Error getting home location for employee %d: %v", employeeID, result.Error)
+		return nil, result.Error
+	}
+	return &homeLocation, nil
+}
+
+// GetHomeLocationByID retrieves a home location by its ID.
+func (r *remoteWorkRepository) GetHomeLocationByID(id uint) (*models.EmployeeHomeLocation, error) {
+	var homeLocation models.EmployeeHomeLocation
+	result := r.db.Preload("Employee").First(&homeLocation, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Home location not found
+		}
+		log.Printf("Error getting home location with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &homeLocation, nil
+}
+
+// GetCompanyHomeLocations retrieves the home locations registered by a company's employees, optionally filtered by status.
+func (r *remoteWorkRepository) GetCompanyHomeLocations(companyID int, status string) ([]models.EmployeeHomeLocation, error) {
+	var homeLocations []models.EmployeeHomeLocation
+	query := r.db.Joins("JOIN employees_tables ON employee_home_locations.employee_id = employees_tables.id").
+		Where("employees_tables.company_id = ?", companyID)
+	if status != "" {
+		query = query.Where("employee_home_locations.status = ?", status)
+	}
+
+	result := query.Preload("Employee").Order("employee_home_locations.updated_at DESC").Find(&homeLocations)
+	if result.Error != nil {
+		log.Printf("Error getting home locations for company %d: %v", companyID, result.Error)
+		return nil, result.Error
+	}
+	return homeLocations, nil
+}
+
+// SaveHomeLocation creates or updates an employee's home location.
+func (r *remoteWorkRepository) SaveHomeLocation(homeLocation *models.EmployeeHomeLocation) error {
+	result := r.db.Save(homeLocation)
+	if result.Error != nil {
+		log.Printf("Error saving home location for employee %d: %v", homeLocation.EmployeeID, result.Error)
+		return result.Error
+	}
+	return nil
+}
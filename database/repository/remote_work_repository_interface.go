@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// RemoteWorkRepository defines the contract for remote work policy, schedule, request and home location database operations.
+type RemoteWorkRepository interface {
+	GetRemoteWorkPolicyByCompanyID(companyID int) (*models.RemoteWorkPolicy, error)
+	SaveRemoteWorkPolicy(policy *models.RemoteWorkPolicy) error
+
+	CreateRemoteWorkSchedule(schedule *models.RemoteWorkSchedule) error
+	GetRemoteWorkScheduleByID(id uint) (*models.RemoteWorkSchedule, error)
+	GetRemoteWorkSchedulesByCompanyID(companyID int) ([]models.RemoteWorkSchedule, error)
+	GetRemoteWorkSchedulesForEmployee(employeeID int, divisionID *int) ([]models.RemoteWorkSchedule, error)
+	DeleteRemoteWorkSchedule(id uint) error
+
+	CreateRemoteWorkRequest(remoteWorkRequest *models.RemoteWorkRequest) error
+	GetRemoteWorkRequestByID(id uint) (*models.RemoteWorkRequest, error)
+	UpdateRemoteWorkRequest(remoteWorkRequest *models.RemoteWorkRequest) error
+	GetRemoteWorkRequestsByEmployeeID(employeeID uint, startDate, endDate *time.Time) ([]models.RemoteWorkRequest, error)
+	GetCompanyRemoteWorkRequestsPaginated(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.RemoteWorkRequest, int64, error)
+	GetApprovedRemoteWorkRequestForDate(employeeID int, date time.Time) (*models.RemoteWorkRequest, error)
+
+	GetHomeLocationByEmployeeID(employeeID uint) (*models.EmployeeHomeLocation, error)
+	GetHomeLocationByID(id uint) (*models.EmployeeHomeLocation, error)
+	GetCompanyHomeLocations(companyID int, status string) ([]models.EmployeeHomeLocation, error)
+	SaveHomeLocation(homeLocation *models.EmployeeHomeLocation) error
+}
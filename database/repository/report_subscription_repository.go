@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reportSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewReportSubscriptionRepository(db *gorm.DB) ReportSubscriptionRepository {
+	return &reportSubscriptionRepository{db: db}
+}
+
+func (r *reportSubscriptionRepository) CreateReportSubscription(subscription *models.ReportSubscription) error {
+	if err := r.db.Create(subscription).Error; err != nil {
+		log.Printf("Error creating report subscription for company %d: %v", subscription.CompanyID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) GetReportSubscriptionByID(id uint) (*models.ReportSubscription, error) {
+	var subscription models.ReportSubscription
+	result := r.db.First(&subscription, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil // Subscription not found
+		}
+		log.Printf("Error getting report subscription with ID %d: %v", id, result.Error)
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+func (r *reportSubscriptionRepository) GetReportSubscriptionsByCompanyID(companyID int) ([]models.ReportSubscription, error) {
+	var subscriptions []models.ReportSubscription
+	if err := r.db.Where("company_id = ?", companyID).Order("name").Find(&subscriptions).Error; err != nil {
+		log.Printf("Error getting report subscriptions for company %d: %v", companyID, err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// GetDueReportSubscriptions retrieves the active subscriptions whose next delivery is due.
+func (r *reportSubscriptionRepository) GetDueReportSubscriptions(now time.Time) ([]models.ReportSubscription, error) {
+	var subscriptions []models.ReportSubscription
+	if err := r.db.Where("is_active = ? AND next_run_at <= ?", true, now).Order("next_run_at").Find(&subscriptions).Error; err != nil {
+		log.Printf("Error getting due report subscriptions: %v", err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *reportSubscriptionRepository) UpdateReportSubscription(subscription *models.ReportSubscription) error {
+	if err := r.db.Save(subscription).Error; err != nil {
+		log.Printf("Error updating report subscription with ID %d: %v", subscription.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) DeleteReportSubscription(id uint) error {
+	if err := r.db.Delete(&models.ReportSubscription{}, id).Error; err != nil {
+		log.Printf("Error deleting report subscription with ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+func (r *reportSubscriptionRepository) CreateReportDelivery(delivery *models.ReportDelivery) error {
+	if err := r.db.Create(delivery).Error; err != nil {
+		log.Printf("Error recording delivery of report subscription %d: %v", delivery.SubscriptionID, err)
+		return err
+	}
+	return nil
+}
+
+// GetReportDeliveriesPaginated retrieves a company's delivery history, newest first, optionally of one subscription.
+func (r *reportSubscriptionRepository) GetReportDeliveriesPaginated(companyID int, subscriptionID uint, page, pageSize int) ([]models.ReportDelivery, int64, error) {
+	var deliveries []models.ReportDelivery
+	var totalRecords int64
+
+	query := r.db.Model(&models.ReportDelivery{}).Where("company_id = ?", companyID)
+	if subscriptionID != 0 {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+
+	if err := query.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting report deliveries: %v", err)
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		log.Printf("Error getting paginated report deliveries: %v", err)
+		return nil, 0, err
+	}
+
+	return deliveries, totalRecords, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"go-face-auth/models"
+	"time"
+)
+
+// ReportSubscriptionRepository defines the contract for scheduled report and delivery history database operations.
+type ReportSubscriptionRepository interface {
+	CreateReportSubscription(subscription *models.ReportSubscription) error
+	GetReportSubscriptionByID(id uint) (*models.ReportSubscription, error)
+	GetReportSubscriptionsByCompanyID(companyID int) ([]models.ReportSubscription, error)
+	GetDueReportSubscriptions(now time.Time) ([]models.ReportSubscription, error)
+	UpdateReportSubscription(subscription *models.ReportSubscription) error
+	DeleteReportSubscription(id uint) error
+	CreateReportDelivery(delivery *models.ReportDelivery) error
+	GetReportDeliveriesPaginated(companyID int, subscriptionID uint, page, pageSize int) ([]models.ReportDelivery, int64, error)
+}
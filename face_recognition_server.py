@@ -187,10 +187,12 @@ def process_face_recognition_request(client_image_b64, db_image_path):
             result = DeepFace.verify(rgb_client_img, rgb_db_img, anti_spoofing=True, threshold=0.5, enforce_detection=False)
             logger.info(f"Verification result: {result}")
 
+            # Distance and threshold are reported so the backend can review borderline matches
+            score = {"distance": float(result['distance']), "threshold": float(result['threshold'])}
             if result['verified']:
-                return {"status": "recognized", "message": "Face recognized!"}
+                return {"status": "recognized", "message": "Face recognized!", **score}
             else:
-                return {"status": "unrecognized", "message": "Face not recognized."}
+                return {"status": "unrecognized", "message": "Face not recognized.", **score}
 
         except Exception as e:
             logger.error(f"Verification error: {e}")
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbsenteeHandler defines the interface for absentee job settings and run history handlers.
+type AbsenteeHandler interface {
+	GetAbsenteePolicy(c *gin.Context)
+	UpdateAbsenteePolicy(c *gin.Context)
+	GetAbsenteeRuns(c *gin.Context)
+	GetLatestAbsenteeRuns(c *gin.Context)
+}
+
+// absenteeHandler is the concrete implementation of AbsenteeHandler.
+type absenteeHandler struct {
+	absenteeService services.AbsenteeService
+}
+
+// NewAbsenteeHandler creates a new instance of AbsenteeHandler.
+func NewAbsenteeHandler(absenteeService services.AbsenteeService) AbsenteeHandler {
+	return &absenteeHandler{
+		absenteeService: absenteeService,
+	}
+}
+
+// Admin Handlers
+
+// GetAbsenteePolicy returns the company's absentee job settings, falling back to the defaults.
+func (h *absenteeHandler) GetAbsenteePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	policy, err := h.absenteeService.GetAbsenteePolicy(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Absentee policy retrieved successfully.", policy)
+}
+
+// UpdateAbsenteePolicy saves the company's absentee job settings into its attendance policy. They apply from
+// the next scheduled run.
+func (h *absenteeHandler) UpdateAbsenteePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.UpdateAbsenteePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	policy, err := h.absenteeService.UpdateAbsenteePolicy(int(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Absentee policy updated successfully.", policy)
+}
+
+// GetAbsenteeRuns lists the company's absentee runs, newest first.
+func (h *absenteeHandler) GetAbsenteeRuns(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	runs, totalRecords, err := h.absenteeService.GetAbsenteeRuns(int(compIDFloat), page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve absentee runs.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         runs,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Absentee runs retrieved successfully.", paginatedData)
+}
+
+// GetLatestAbsenteeRuns returns the last absentee run of every company.
+func (h *absenteeHandler) GetLatestAbsenteeRuns(c *gin.Context) {
+	runs, err := h.absenteeService.GetLatestAbsenteeRuns()
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve absentee runs.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Absentee runs retrieved successfully.", runs)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler defines the interface for analytics handlers.
+type AnalyticsHandler interface {
+	GetAttendanceAnalytics(c *gin.Context)
+}
+
+// analyticsHandler is the concrete implementation of AnalyticsHandler.
+type analyticsHandler struct {
+	analyticsService services.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new instance of AnalyticsHandler.
+func NewAnalyticsHandler(analyticsService services.AnalyticsService) AnalyticsHandler {
+	return &analyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetAttendanceAnalytics returns the company's attendance trend over startDate..endDate, its breakdowns by
+// division, shift, location and weekday, and the employees with the most late days, absences and overtime.
+func (h *analyticsHandler) GetAttendanceAnalytics(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	top := 0
+	if topStr := c.Query("top"); topStr != "" {
+		parsed, err := strconv.Atoi(topStr)
+		if err != nil || parsed < 1 {
+			helper.SendError(c, http.StatusBadRequest, "Invalid top value. Use a positive number.")
+			return
+		}
+		top = parsed
+	}
+
+	analytics, err := h.analyticsService.GetAttendanceAnalytics(int(compIDFloat), services.AttendanceAnalyticsRequest{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Interval:  c.Query("interval"),
+		TopN:      top,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsRange) || errors.Is(err, services.ErrInvalidAnalyticsInterval) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, services.ErrCompanyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve attendance analytics.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance analytics retrieved successfully.", analytics)
+}
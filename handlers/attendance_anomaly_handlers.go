@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceAnomalyHandler defines the interface for the attendance anomaly review queue handlers.
+type AttendanceAnomalyHandler interface {
+	GetAttendanceAnomalies(c *gin.Context)
+	ScanAttendanceAnomalies(c *gin.Context)
+	ReviewAttendanceAnomaly(c *gin.Context)
+}
+
+// attendanceAnomalyHandler is the concrete implementation of AttendanceAnomalyHandler.
+type attendanceAnomalyHandler struct {
+	attendanceAnomalyService services.AttendanceAnomalyService
+}
+
+// NewAttendanceAnomalyHandler creates a new instance of AttendanceAnomalyHandler.
+func NewAttendanceAnomalyHandler(attendanceAnomalyService services.AttendanceAnomalyService) AttendanceAnomalyHandler {
+	return &attendanceAnomalyHandler{
+		attendanceAnomalyService: attendanceAnomalyService,
+	}
+}
+
+// Admin Handlers
+
+// GetAttendanceAnomalies lists the company's anomalies, newest first, filtered by status, kind and employee name.
+func (h *attendanceAnomalyHandler) GetAttendanceAnomalies(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status") // "open", "confirmed" or "dismissed"
+	kind := c.Query("kind")
+	search := c.Query("search")
+
+	anomalies, totalRecords, err := h.attendanceAnomalyService.GetAttendanceAnomalies(int(compIDFloat), status, kind, search, page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve attendance anomalies.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         anomalies,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance anomalies retrieved successfully.", paginatedData)
+}
+
+// ScanAttendanceAnomalies runs the anomaly detection for the company now, instead of waiting for the daily job.
+func (h *attendanceAnomalyHandler) ScanAttendanceAnomalies(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	anomalies, err := h.attendanceAnomalyService.DetectCompanyAnomalies(int(compIDFloat), time.Now())
+	if err != nil {
+		if errors.Is(err, services.ErrCompanyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to scan for attendance anomalies.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance anomaly scan completed successfully.", gin.H{"detected": len(anomalies), "items": anomalies})
+}
+
+// ReviewAttendanceAnomaly confirms or dismisses an open anomaly.
+func (h *attendanceAnomalyHandler) ReviewAttendanceAnomaly(c *gin.Context) {
+	anomalyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid attendance anomaly ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.ReviewAttendanceAnomalyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	anomaly, err := h.attendanceAnomalyService.ReviewAttendanceAnomaly(uint(adminIDFloat), int(compIDFloat), uint(anomalyID), req)
+	if err != nil {
+		if errors.Is(err, services.ErrAttendanceAnomalyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrAttendanceAnomalyAlreadyReviewed) {
+			helper.SendError(c, http.StatusConflict, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance anomaly reviewed successfully.", anomaly)
+}
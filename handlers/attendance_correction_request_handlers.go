@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+	"go-face-auth/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceCorrectionRequestHandler defines the interface for attendance correction request handlers.
+type AttendanceCorrectionRequestHandler interface {
+	SubmitCorrectionRequest(c *gin.Context)
+	GetMyCorrectionRequests(c *gin.Context)
+	CancelCorrectionRequest(c *gin.Context)
+	GetCompanyCorrectionRequests(c *gin.Context)
+	ReviewCorrectionRequest(hub *websocket.Hub) gin.HandlerFunc
+}
+
+// attendanceCorrectionRequestHandler is the concrete implementation of AttendanceCorrectionRequestHandler.
+type attendanceCorrectionRequestHandler struct {
+	correctionRequestService services.AttendanceCorrectionRequestService
+}
+
+// NewAttendanceCorrectionRequestHandler creates a new instance of AttendanceCorrectionRequestHandler.
+func NewAttendanceCorrectionRequestHandler(correctionRequestService services.AttendanceCorrectionRequestService) AttendanceCorrectionRequestHandler {
+	return &attendanceCorrectionRequestHandler{
+		correctionRequestService: correctionRequestService,
+	}
+}
+
+type CreateCorrectionRequestPayload struct {
+	CorrectionType string                `form:"correction_type" binding:"required,oneof=check_in check_out"`
+	ProposedTime   string                `form:"proposed_time" binding:"required,datetime=2006-01-02 15:04"` // Company local time
+	Reason         string                `form:"reason" binding:"required,min=10"`
+	Attachment     *multipart.FileHeader `form:"attachment"` // Optional supporting file
+}
+
+type ReviewCorrectionRequestPayload struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Notes  string `json:"notes"`
+}
+
+// Employee Handlers
+
+func (h *attendanceCorrectionRequestHandler) SubmitCorrectionRequest(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req CreateCorrectionRequestPayload
+	// Use c.ShouldBind for multipart/form-data
+	if err := c.ShouldBind(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	correctionRequest, err := h.correctionRequestService.SubmitCorrectionRequest(uint(empIDFloat), req.CorrectionType, req.ProposedTime, req.Reason, req.Attachment)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Correction request submitted successfully.", correctionRequest)
+}
+
+func (h *attendanceCorrectionRequestHandler) GetMyCorrectionRequests(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	correctionRequests, err := h.correctionRequestService.GetMyCorrectionRequests(uint(empIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve correction requests.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Correction requests retrieved successfully.", correctionRequests)
+}
+
+func (h *attendanceCorrectionRequestHandler) CancelCorrectionRequest(c *gin.Context) {
+	correctionRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid correction request ID.")
+		return
+	}
+
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	if _, err := h.correctionRequestService.CancelCorrectionRequest(uint(correctionRequestID), uint(empIDFloat)); err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Correction request cancelled successfully.", nil)
+}
+
+// Admin Handlers
+
+func (h *attendanceCorrectionRequestHandler) GetCompanyCorrectionRequests(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+	compID := int(compIDFloat)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+	search := c.Query("search")
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	correctionRequests, totalRecords, err := h.correctionRequestService.GetCompanyCorrectionRequests(compID, status, search, startDate, endDate, page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve correction requests.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         correctionRequests,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Correction requests retrieved successfully.", paginatedData)
+}
+
+// ReviewCorrectionRequest approves or rejects a correction request and notifies the employee over their websocket.
+func (h *attendanceCorrectionRequestHandler) ReviewCorrectionRequest(hub *websocket.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correctionRequestID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid correction request ID.")
+			return
+		}
+
+		adminID, exists := c.Get("id")
+		if !exists {
+			helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+			return
+		}
+		adminIDFloat, ok := adminID.(float64)
+		if !ok {
+			helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+			return
+		}
+
+		var req ReviewCorrectionRequestPayload
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+			return
+		}
+
+		correctionRequest, err := h.correctionRequestService.ReviewCorrectionRequest(uint(correctionRequestID), uint(adminIDFloat), req.Status, req.Notes)
+		if err != nil {
+			if errors.Is(err, services.ErrCorrectionRequestNotFound) {
+				helper.SendError(c, http.StatusNotFound, err.Error())
+			} else if errors.Is(err, services.ErrCorrectionRequestUnauthorized) {
+				helper.SendError(c, http.StatusForbidden, err.Error())
+			} else {
+				helper.SendError(c, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+
+		go hub.SendMessageToEmployee(correctionRequest.Employee.CompanyID, int(correctionRequest.EmployeeID), "attendance_correction_reviewed", gin.H{
+			"id":                    correctionRequest.ID,
+			"status":                correctionRequest.Status,
+			"correction_type":       correctionRequest.CorrectionType,
+			"proposed_time":         correctionRequest.ProposedTime,
+			"review_notes":          correctionRequest.ReviewNotes,
+			"applied_attendance_id": correctionRequest.AppliedAttendanceID,
+		})
+
+		helper.SendSuccess(c, http.StatusOK, "Correction request status updated successfully.", correctionRequest)
+	}
+}
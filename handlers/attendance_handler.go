@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"errors"
-	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -22,26 +22,31 @@ type AttendanceHandler interface {
 	HandleOvertimeCheckOut(hub *websocket.Hub, c *gin.Context)
 	GetAttendances(c *gin.Context)
 	GetEmployeeAttendanceHistory(c *gin.Context)
-	ExportEmployeeAttendanceToExcel(c *gin.Context)
-	ExportAllAttendancesToExcel(c *gin.Context)
+	ExportEmployeeAttendance(c *gin.Context)
+	ExportAllAttendances(hub *websocket.Hub, c *gin.Context)
 	GetUnaccountedEmployees(c *gin.Context)
-	ExportUnaccountedToExcel(c *gin.Context)
-	ExportOvertimeToExcel(c *gin.Context)
+	ExportUnaccounted(c *gin.Context)
+	ExportOvertime(c *gin.Context)
 	GetOvertimeAttendances(c *gin.Context)
+	GetAttendancePunches(c *gin.Context)
 	CorrectAttendance(c *gin.Context)
+	CloseOvertimeSession(hub *websocket.Hub, c *gin.Context)
+	BackfillAbsentees(c *gin.Context)
 }
 
 // attendanceHandler is the concrete implementation of AttendanceHandler.
 type attendanceHandler struct {
 	attendanceService services.AttendanceService
 	adminCompanyService services.AdminCompanyService
+	exportJobService services.ExportJobService
 }
 
 // NewAttendanceHandler creates a new instance of AttendanceHandler.
-func NewAttendanceHandler(attendanceService services.AttendanceService, adminCompanyService services.AdminCompanyService) AttendanceHandler {
+func NewAttendanceHandler(attendanceService services.AttendanceService, adminCompanyService services.AdminCompanyService, exportJobService services.ExportJobService) AttendanceHandler {
 	return &attendanceHandler{
 		attendanceService: attendanceService,
 		adminCompanyService:      adminCompanyService,
+		exportJobService: exportJobService,
 	}
 }
 
@@ -55,11 +60,13 @@ func (h *attendanceHandler) HandleAttendance(hub *websocket.Hub, c *gin.Context)
 
 	message, employee, now, err := h.attendanceService.HandleAttendance(req)
 	if err != nil {
-		if errors.Is(err, services.ErrFaceNotRecognized) {
+		if errors.Is(err, services.ErrFaceNotRecognized) || errors.Is(err, services.ErrAttendanceLocked) {
 			helper.SendError(c, http.StatusConflict, err.Error())
 		} else if errors.Is(err, services.ErrNoRegisteredFaceImages) || errors.Is(err, services.ErrEmployeeNotFound) {
 			helper.SendError(c, http.StatusNotFound, err.Error())
-		} else if errors.Is(err, services.ErrOutsideAttendanceLocation) || errors.Is(err, services.ErrNoShiftAssigned) || errors.Is(err, services.ErrOutsideShiftHours) {
+		} else if errors.Is(err, services.ErrAlreadyCheckedOut) || errors.Is(err, services.ErrMustCheckOutOvertime) || errors.Is(err, services.ErrInvalidAttendanceTransition) {
+			helper.SendError(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, services.ErrOutsideAttendanceLocation) || errors.Is(err, services.ErrNoShiftAssigned) || errors.Is(err, services.ErrOutsideShiftHours) || errors.Is(err, services.ErrMockLocationDetected) || errors.Is(err, services.ErrInvalidLocationAccuracy) || errors.Is(err, services.ErrLocationAccuracyInsufficient) || errors.Is(err, services.ErrInvalidLocationQRCode) || errors.Is(err, services.ErrNoApprovedHomeLocation) || errors.Is(err, services.ErrOutsideHomeLocation) {
 			helper.SendError(c, http.StatusBadRequest, err.Error())
 		} else {
 			helper.SendError(c, http.StatusInternalServerError, err.Error())
@@ -101,13 +108,13 @@ func (h *attendanceHandler) HandleOvertimeCheckIn(hub *websocket.Hub, c *gin.Con
 		return
 	}
 
-	employee,now, err := h.attendanceService.HandleOvertimeCheckIn(req)
+	employee, attendance, err := h.attendanceService.HandleOvertimeCheckIn(req)
 	if err != nil {
-		if errors.Is(err, services.ErrFaceNotRecognized) {
+		if errors.Is(err, services.ErrFaceNotRecognized) || errors.Is(err, services.ErrAttendanceLocked) {
 			helper.SendError(c, http.StatusConflict, err.Error())
 		} else if errors.Is(err, services.ErrNoRegisteredFaceImages) || errors.Is(err, services.ErrEmployeeNotFound) {
 			helper.SendError(c, http.StatusNotFound, err.Error())
-		} else if errors.Is(err, services.ErrOutsideAttendanceLocation) || errors.Is(err, services.ErrNoShiftAssigned) || errors.Is(err, services.ErrOvertimeDuringShift) || errors.Is(err, services.ErrAlreadyCheckedInOvertime) {
+		} else if errors.Is(err, services.ErrOutsideAttendanceLocation) || errors.Is(err, services.ErrNoShiftAssigned) || errors.Is(err, services.ErrOvertimeDuringShift) || errors.Is(err, services.ErrAlreadyCheckedInOvertime) || errors.Is(err, services.ErrNoApprovedOvertimeRequest) || errors.Is(err, services.ErrMockLocationDetected) || errors.Is(err, services.ErrInvalidLocationAccuracy) || errors.Is(err, services.ErrLocationAccuracyInsufficient) || errors.Is(err, services.ErrInvalidLocationQRCode) || errors.Is(err, services.ErrNoApprovedHomeLocation) || errors.Is(err, services.ErrOutsideHomeLocation) {
 			helper.SendError(c, http.StatusBadRequest, err.Error())
 		} else {
 			helper.SendError(c, http.StatusInternalServerError, err.Error())
@@ -116,10 +123,12 @@ func (h *attendanceHandler) HandleOvertimeCheckIn(hub *websocket.Hub, c *gin.Con
 	}
 
 	helper.SendSuccess(c, http.StatusOK, "Overtime check-in successful!", gin.H{
-		"employee_id":   employee.ID,
-		"employee_name": employee.Name,
-		"timestamp":     now,
-		"status":        "overtime_in",
+		"employee_id":            employee.ID,
+		"employee_name":          employee.Name,
+		"timestamp":              attendance.CheckInTime,
+		"status":                 attendance.Status,
+		"overtime_request_id":    attendance.OvertimeRequestID,
+		"is_overtime_unapproved": attendance.IsOvertimeUnapproved,
 	})
 }
 
@@ -131,13 +140,13 @@ func (h *attendanceHandler) HandleOvertimeCheckOut(hub *websocket.Hub, c *gin.Co
 		return
 	}
 
-	employee, CheckInTime, now, OvertimeMinutes, err := h.attendanceService.HandleOvertimeCheckOut(req)
+	employee, attendance, err := h.attendanceService.HandleOvertimeCheckOut(req)
 	if err != nil {
-		if errors.Is(err, services.ErrFaceNotRecognized) {
+		if errors.Is(err, services.ErrFaceNotRecognized) || errors.Is(err, services.ErrAttendanceLocked) {
 			helper.SendError(c, http.StatusConflict, err.Error())
 		} else if errors.Is(err, services.ErrNoRegisteredFaceImages) || errors.Is(err, services.ErrEmployeeNotFound) {
 			helper.SendError(c, http.StatusNotFound, err.Error())
-		} else if errors.Is(err, services.ErrNotCheckedInForOvertime) {
+		} else if errors.Is(err, services.ErrNotCheckedInForOvertime) || errors.Is(err, services.ErrMockLocationDetected) || errors.Is(err, services.ErrInvalidLocationAccuracy) || errors.Is(err, services.ErrLocationAccuracyInsufficient) {
 			helper.SendError(c, http.StatusBadRequest, err.Error())
 		} else {
 			helper.SendError(c, http.StatusInternalServerError, err.Error())
@@ -146,12 +155,14 @@ func (h *attendanceHandler) HandleOvertimeCheckOut(hub *websocket.Hub, c *gin.Co
 	}
 
 	helper.SendSuccess(c, http.StatusOK, "Overtime check-out successful!", gin.H{
-		"employee_id":      employee.ID,
-		"employee_name":    employee.Name,
-		"check_in_time":    CheckInTime,
-		"check_out_time":   now,
-		"overtime_minutes": OvertimeMinutes,
-		"status":           "overtime_out",
+		"employee_id":               employee.ID,
+		"employee_name":             employee.Name,
+		"check_in_time":             attendance.CheckInTime,
+		"check_out_time":            attendance.CheckOutTime,
+		"overtime_minutes":          attendance.OvertimeMinutes,
+		"status":                    attendance.Status,
+		"approved_overtime_minutes": attendance.ApprovedOvertimeMinutes,
+		"is_overtime_unapproved":    attendance.IsOvertimeUnapproved,
 	})
 }
 
@@ -251,8 +262,8 @@ func (h *attendanceHandler) GetEmployeeAttendanceHistory(c *gin.Context) {
 	helper.SendSuccess(c, http.StatusOK, "Employee attendance history retrieved successfully.", attendances)
 }
 
-// ExportEmployeeAttendanceToExcel exports attendance records for a specific employee to an Excel file.
-func (h *attendanceHandler) ExportEmployeeAttendanceToExcel(c *gin.Context) {
+// ExportEmployeeAttendance exports attendance records for a specific employee as xlsx, csv or pdf.
+func (h *attendanceHandler) ExportEmployeeAttendance(c *gin.Context) {
 	employeeID := c.Param("employeeID")
 	parsedEmployeeID, err := strconv.Atoi(employeeID)
 	if err != nil {
@@ -260,6 +271,11 @@ func (h *attendanceHandler) ExportEmployeeAttendanceToExcel(c *gin.Context) {
 		return
 	}
 
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
@@ -284,26 +300,30 @@ func (h *attendanceHandler) ExportEmployeeAttendanceToExcel(c *gin.Context) {
 		endDate = &endDateVal
 	}
 
-	file, fileName, err := h.attendanceService.ExportEmployeeAttendanceToExcel(parsedEmployeeID, startDate, endDate)
+	report, err := h.attendanceService.ExportEmployeeAttendance(parsedEmployeeID, startDate, endDate)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
 		return
 	}
 
-	// Set response headers for Excel file download
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	sendReport(c, report, format)
+}
 
-	// Write the Excel file to the response writer
-	if err := file.Write(c.Writer); err != nil {
-		log.Printf("Error writing excel file to response: %v", err)
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+// ExportAllAttendances exports all attendance records for the company as xlsx, csv or pdf. Spreadsheet exports
+// are streamed from the database into the response; exports too large for a request run as a background job
+// whose file can be downloaded once it is ready.
+func (h *attendanceHandler) ExportAllAttendances(hub *websocket.Hub, c *gin.Context) {
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
 		return
 	}
-}
 
-// ExportAllAttendancesToExcel exports all attendance records for the company to an Excel file.
-func (h *attendanceHandler) ExportAllAttendancesToExcel(c *gin.Context) {
 	companyID, exists := c.Get("companyID")
 	if !exists {
 		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
@@ -316,6 +336,11 @@ func (h *attendanceHandler) ExportAllAttendancesToExcel(c *gin.Context) {
 	}
 	compID := int(compIDFloat)
 
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
@@ -340,22 +365,39 @@ func (h *attendanceHandler) ExportAllAttendancesToExcel(c *gin.Context) {
 		endDate = &endDateVal
 	}
 
-	file, fileName, err := h.attendanceService.ExportAllAttendancesToExcel(compID, startDate, endDate)
+	count, err := h.attendanceService.CountAllAttendances(compID, startDate, endDate)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
+		return
+	}
+	if !helper.IsStreamableReportFormat(format) && count > services.MaxPDFExportRows {
+		helper.SendError(c, http.StatusBadRequest, services.ErrPDFExportTooLarge.Error())
+		return
+	}
+	if count > services.MaxStreamedExportRows {
+		job, err := h.exportJobService.StartAttendanceExport(uint(adminIDFloat), compID, startDate, endDate, format, hub)
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to start export.")
+			return
+		}
+		helper.SendSuccess(c, http.StatusAccepted, "The export is large and is being prepared. It can be downloaded from the exports list once it is ready.", job)
 		return
 	}
 
-	// Set response headers for Excel file download
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-
-	// Write the Excel file to the response writer
-	if err := file.Write(c.Writer); err != nil {
-		log.Printf("Error writing excel file to response: %v", err)
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+	if !helper.IsStreamableReportFormat(format) {
+		report, err := h.attendanceService.ExportAllAttendances(compID, startDate, endDate)
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
+			return
+		}
+		sendReport(c, report, format)
 		return
 	}
+
+	streamReport(c, services.AllAttendancesFileName(startDate, endDate), format, func(w io.Writer) error {
+		_, err := h.attendanceService.StreamAllAttendances(compID, startDate, endDate, format, w)
+		return err
+	})
 }
 
 // GetUnaccountedEmployees handles fetching employees who are not present and not on leave/sick.
@@ -411,8 +453,8 @@ func (h *attendanceHandler) GetUnaccountedEmployees(c *gin.Context) {
 	helper.SendSuccess(c, http.StatusOK, "Unaccounted employees retrieved successfully.", paginatedData)
 }
 
-// ExportUnaccountedToExcel exports unaccounted employee records to an Excel file.
-func (h *attendanceHandler) ExportUnaccountedToExcel(c *gin.Context) {
+// ExportUnaccounted exports unaccounted employee records as xlsx, csv or pdf.
+func (h *attendanceHandler) ExportUnaccounted(c *gin.Context) {
 	companyID, exists := c.Get("companyID")
 	if !exists {
 		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
@@ -425,6 +467,11 @@ func (h *attendanceHandler) ExportUnaccountedToExcel(c *gin.Context) {
 	}
 	compID := int(compIDFloat)
 
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
@@ -448,24 +495,17 @@ func (h *attendanceHandler) ExportUnaccountedToExcel(c *gin.Context) {
 
 	search := c.Query("search")
 
-	file, fileName, err := h.attendanceService.ExportUnaccountedToExcel(compID, startDate, endDate, search)
+	report, err := h.attendanceService.ExportUnaccounted(compID, startDate, endDate, search)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
 		return
 	}
 
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-
-	if err := file.Write(c.Writer); err != nil {
-		log.Printf("Error writing excel file: %v", err)
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
-		return
-	}
+	sendReport(c, report, format)
 }
 
-// ExportOvertimeToExcel exports overtime attendance records to an Excel file.
-func (h *attendanceHandler) ExportOvertimeToExcel(c *gin.Context) {
+// ExportOvertime exports overtime attendance records with a per-employee summary as xlsx, csv or pdf.
+func (h *attendanceHandler) ExportOvertime(c *gin.Context) {
 	companyID, exists := c.Get("companyID")
 	if !exists {
 		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
@@ -478,6 +518,11 @@ func (h *attendanceHandler) ExportOvertimeToExcel(c *gin.Context) {
 	}
 	compID := int(compIDFloat)
 
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
@@ -501,20 +546,13 @@ func (h *attendanceHandler) ExportOvertimeToExcel(c *gin.Context) {
 
 	search := c.Query("search")
 
-	file, fileName, err := h.attendanceService.ExportOvertimeToExcel(compID, startDate, endDate, search)
+	report, err := h.attendanceService.ExportOvertime(compID, startDate, endDate, search)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
 		return
 	}
 
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-
-	if err := file.Write(c.Writer); err != nil {
-		log.Printf("Error writing excel file: %v", err)
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
-		return
-	}
+	sendReport(c, report, format)
 }
 
 // GetOvertimeAttendances retrieves all overtime attendance records for the company.
@@ -570,6 +608,45 @@ func (h *attendanceHandler) GetOvertimeAttendances(c *gin.Context) {
 	helper.SendSuccess(c, http.StatusOK, "Overtime attendances retrieved successfully.", paginatedData)
 }
 
+// GetAttendancePunches lists punch attempts with their location evidence, including rejected ones.
+func (h *attendanceHandler) GetAttendancePunches(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+	compID := int(compIDFloat)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+	result := c.Query("result") // "accepted" or "rejected"
+	flaggedOnly := c.Query("flagged") == "true"
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	punches, totalRecords, err := h.attendanceService.GetAttendancePunchesPaginated(compID, result, flaggedOnly, search, startDate, endDate, page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve attendance punches.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         punches,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance punches retrieved successfully.", paginatedData)
+}
+
 // CorrectAttendance handles manual attendance correction by an admin.
 func (h *attendanceHandler) CorrectAttendance(c *gin.Context) {
 	adminID, exists := c.Get("id")
@@ -597,3 +674,91 @@ func (h *attendanceHandler) CorrectAttendance(c *gin.Context) {
 
 	helper.SendSuccess(c, http.StatusOK, "Attendance corrected successfully.", attendance)
 }
+
+// CloseOvertimeSession closes an employee's forgotten overtime session with the time it really ended.
+func (h *attendanceHandler) CloseOvertimeSession(hub *websocket.Hub, c *gin.Context) {
+	attendanceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid attendance ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.CloseOvertimeSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	attendance, err := h.attendanceService.CloseOvertimeSession(uint(adminIDFloat), int(compIDFloat), attendanceID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrAttendanceNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrOvertimeSessionNotOpen) || errors.Is(err, services.ErrAttendanceLocked) {
+			helper.SendError(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, services.ErrInvalidOvertimeCheckOutTime) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	go hub.SendMessageToEmployee(int(compIDFloat), attendance.EmployeeID, "overtime_session_closed", gin.H{
+		"attendance_id":    attendance.ID,
+		"check_in_time":    attendance.CheckInTime,
+		"check_out_time":   attendance.CheckOutTime,
+		"overtime_minutes": attendance.OvertimeMinutes,
+		"notes":            attendance.Notes,
+	})
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime session closed successfully.", attendance)
+}
+
+// BackfillAbsentees re-runs absentee processing over a date range (superadmin only). With dry_run set, the
+// changes are reported without being written.
+func (h *attendanceHandler) BackfillAbsentees(c *gin.Context) {
+	var req services.AbsenteeBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	report, err := h.attendanceService.BackfillAbsentees(req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAbsenteeBackfillRange) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, services.ErrCompanyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	message := "Absentee backfill completed successfully."
+	if req.DryRun {
+		message = "Absentee backfill dry run completed successfully."
+	}
+	helper.SendSuccess(c, http.StatusOK, message, report)
+}
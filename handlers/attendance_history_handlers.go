@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceHistoryHandler defines the interface for attendance change history handlers.
+type AttendanceHistoryHandler interface {
+	GetAttendanceHistory(c *gin.Context)
+	RevertAttendance(c *gin.Context)
+}
+
+// attendanceHistoryHandler is the concrete implementation of AttendanceHistoryHandler.
+type attendanceHistoryHandler struct {
+	attendanceHistoryService services.AttendanceHistoryService
+}
+
+// NewAttendanceHistoryHandler creates a new instance of AttendanceHistoryHandler.
+func NewAttendanceHistoryHandler(attendanceHistoryService services.AttendanceHistoryService) AttendanceHistoryHandler {
+	return &attendanceHistoryHandler{
+		attendanceHistoryService: attendanceHistoryService,
+	}
+}
+
+// GetAttendanceHistory returns an attendance record with every version of its values.
+func (h *attendanceHistoryHandler) GetAttendanceHistory(c *gin.Context) {
+	attendanceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid attendance ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	history, err := h.attendanceHistoryService.GetAttendanceHistory(int(compIDFloat), attendanceID)
+	if err != nil {
+		if errors.Is(err, services.ErrAttendanceNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve attendance history.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance history retrieved successfully.", history)
+}
+
+// RevertAttendance restores an attendance record to the values of a previous version.
+func (h *attendanceHistoryHandler) RevertAttendance(c *gin.Context) {
+	attendanceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid attendance ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.RevertAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	attendance, err := h.attendanceHistoryService.RevertAttendance(int(compIDFloat), uint(adminIDFloat), attendanceID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrAttendanceNotFound) || errors.Is(err, services.ErrAttendanceVersionNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrAttendanceAlreadyAtVersion) || errors.Is(err, services.ErrInvalidAttendanceTransition) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance reverted successfully.", attendance)
+}
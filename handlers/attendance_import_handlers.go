@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceImportHandler defines the interface for importing legacy time clock logs.
+type AttendanceImportHandler interface {
+	ImportAttendanceLogs(c *gin.Context)
+}
+
+// attendanceImportHandler is the concrete implementation of AttendanceImportHandler.
+type attendanceImportHandler struct {
+	attendanceImportService services.AttendanceImportService
+}
+
+// NewAttendanceImportHandler creates a new instance of AttendanceImportHandler.
+func NewAttendanceImportHandler(attendanceImportService services.AttendanceImportService) AttendanceImportHandler {
+	return &attendanceImportHandler{
+		attendanceImportService: attendanceImportService,
+	}
+}
+
+// Admin Handlers
+
+// ImportAttendanceLogs imports a ZKTeco attlog or CSV punch log uploaded as "file". The optional "format" field
+// forces the format, and "dry_run" previews the paired records and flagged rows without saving anything.
+func (h *attendanceImportHandler) ImportAttendanceLogs(c *gin.Context) {
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	dryRun := false
+	if value := c.PostForm("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid dry_run value. Use true or false.")
+			return
+		}
+		dryRun = parsed
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "A ZKTeco attlog or CSV file is required.")
+		return
+	}
+	if fileHeader.Size > services.MaxAttendanceImportFileSize {
+		helper.SendError(c, http.StatusBadRequest, "File is too large.")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Failed to read uploaded file.")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Failed to read uploaded file.")
+		return
+	}
+
+	req := services.AttendanceImportRequest{
+		FileName: fileHeader.Filename,
+		Data:     data,
+		Format:   c.PostForm("format"),
+		DryRun:   dryRun,
+	}
+	report, err := h.attendanceImportService.ImportAttendanceLogs(uint(adminIDFloat), int(compIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrCompanyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrInvalidAttendanceImportFormat) || errors.Is(err, services.ErrInvalidAttendanceImportFile) || errors.Is(err, services.ErrAttendanceImportTooLarge) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to import attendance logs.")
+		}
+		return
+	}
+
+	if dryRun {
+		helper.SendSuccess(c, http.StatusOK, "Attendance import preview generated successfully.", report)
+		return
+	}
+	helper.SendSuccess(c, http.StatusCreated, "Attendance logs imported successfully.", report)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendancePolicyHandler defines the interface for attendance rule handlers.
+type AttendancePolicyHandler interface {
+	GetAttendancePolicies(c *gin.Context)
+	SaveAttendancePolicy(c *gin.Context)
+	DeleteAttendancePolicy(c *gin.Context)
+}
+
+// attendancePolicyHandler is the concrete implementation of AttendancePolicyHandler.
+type attendancePolicyHandler struct {
+	attendancePolicyService services.AttendancePolicyService
+}
+
+// NewAttendancePolicyHandler creates a new instance of AttendancePolicyHandler.
+func NewAttendancePolicyHandler(attendancePolicyService services.AttendancePolicyService) AttendancePolicyHandler {
+	return &attendancePolicyHandler{
+		attendancePolicyService: attendancePolicyService,
+	}
+}
+
+// GetAttendancePolicies returns the company's attendance policy and its division and shift overrides.
+func (h *attendancePolicyHandler) GetAttendancePolicies(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	policies, err := h.attendancePolicyService.GetAttendancePolicies(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve attendance policies.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance policies retrieved successfully.", policies)
+}
+
+// SaveAttendancePolicy saves the company policy, or the override of the division or shift named in the payload.
+func (h *attendancePolicyHandler) SaveAttendancePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.AttendancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	policy, err := h.attendancePolicyService.SaveAttendancePolicy(int(compIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAttendancePolicyScope) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, services.ErrAttendancePolicyScopeNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance policy saved successfully.", policy)
+}
+
+// DeleteAttendancePolicy removes a policy, so its scope falls back to the broader rules.
+func (h *attendancePolicyHandler) DeleteAttendancePolicy(c *gin.Context) {
+	policyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid attendance policy ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	if err := h.attendancePolicyService.DeleteAttendancePolicy(int(compIDFloat), uint(policyID)); err != nil {
+		if errors.Is(err, services.ErrAttendancePolicyNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Attendance policy deleted successfully.", nil)
+}
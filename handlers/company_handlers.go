@@ -41,6 +41,7 @@ type UpdateCompanyRequest struct {
 	Name    string `json:"name"`
 	Address string `json:"address"`
 	Timezone string `json:"timezone"`
+	OvertimeRequiresApproval *bool `json:"overtime_requires_approval"`
 }
 
 // RegisterCompanyRequest defines the structure for the company registration request body.
@@ -173,7 +174,7 @@ func (h *companyHandler) UpdateCompanyDetails(c *gin.Context) {
 		return
 	}
 
-	company, err := h.companyService.UpdateCompanyDetails(int(id), req.Name, req.Address, req.Timezone)
+	company, err := h.companyService.UpdateCompanyDetails(int(id), req.Name, req.Address, req.Timezone, req.OvertimeRequiresApproval)
 	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, "Failed to update company details.")
 		return
@@ -68,7 +68,7 @@ func (h *employeeWebSocketHandler) HandleEmployeeWebSocket(hub *websocket.Hub, c
 	}
 
 	// Create a new client and register it with the hub
-	client := &websocket.Client{Conn: conn, Send: make(chan []byte, 256), CompanyID: companyID, Done: make(chan struct{})}
+	client := &websocket.Client{Conn: conn, Send: make(chan []byte, 256), CompanyID: companyID, EmployeeID: employeeID, Done: make(chan struct{})}
 	hub.Register <- client
 
 	log.Printf("Employee %d (Company ID: %d) WebSocket connected.", employeeID, companyID)
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportJobHandler defines the interface for background export handlers.
+type ExportJobHandler interface {
+	GetExportJobs(c *gin.Context)
+	DownloadExportJob(c *gin.Context)
+}
+
+// exportJobHandler is the concrete implementation of ExportJobHandler.
+type exportJobHandler struct {
+	exportJobService services.ExportJobService
+}
+
+// NewExportJobHandler creates a new instance of ExportJobHandler.
+func NewExportJobHandler(exportJobService services.ExportJobService) ExportJobHandler {
+	return &exportJobHandler{
+		exportJobService: exportJobService,
+	}
+}
+
+// Admin Handlers
+
+// GetExportJobs lists the company's background exports, newest first, with download links for finished ones.
+func (h *exportJobHandler) GetExportJobs(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	jobs, totalRecords, err := h.exportJobService.GetExportJobs(int(compIDFloat), page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve exports.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         jobs,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Exports retrieved successfully.", paginatedData)
+}
+
+// DownloadExportJob sends the file of a finished background export.
+func (h *exportJobHandler) DownloadExportJob(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid export ID.")
+		return
+	}
+
+	job, err := h.exportJobService.GetExportJobFile(int(compIDFloat), uint(jobID))
+	if err != nil {
+		if errors.Is(err, services.ErrExportJobNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrExportJobNotReady) || errors.Is(err, services.ErrExportJobFailed) {
+			helper.SendError(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, services.ErrExportJobExpired) {
+			helper.SendError(c, http.StatusGone, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve export.")
+		}
+		return
+	}
+
+	c.Header("Content-Type", helper.ReportContentType(job.Format))
+	c.FileAttachment(job.FilePath, job.FileName)
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldVisitHandler defines the interface for field visit and client site handlers.
+type FieldVisitHandler interface {
+	StartFieldVisit(c *gin.Context)
+	EndFieldVisit(c *gin.Context)
+	GetMyFieldVisits(c *gin.Context)
+	GetFieldVisitTimeline(c *gin.Context)
+	GetClientSites(c *gin.Context)
+	CreateClientSite(c *gin.Context)
+	UpdateClientSite(c *gin.Context)
+	DeleteClientSite(c *gin.Context)
+}
+
+// fieldVisitHandler is the concrete implementation of FieldVisitHandler.
+type fieldVisitHandler struct {
+	fieldVisitService services.FieldVisitService
+}
+
+// NewFieldVisitHandler creates a new instance of FieldVisitHandler.
+func NewFieldVisitHandler(fieldVisitService services.FieldVisitService) FieldVisitHandler {
+	return &fieldVisitHandler{
+		fieldVisitService: fieldVisitService,
+	}
+}
+
+// Employee Handlers
+
+// StartFieldVisit starts a face-verified field visit at the employee's current coordinates.
+func (h *fieldVisitHandler) StartFieldVisit(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req services.StartFieldVisitRequest
+	if err := c.ShouldBind(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	visit, err := h.fieldVisitService.StartFieldVisit(int(empIDFloat), req)
+	if err != nil {
+		sendFieldVisitError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Field visit started successfully.", visit)
+}
+
+// EndFieldVisit ends one of the employee's field visits in progress.
+func (h *fieldVisitHandler) EndFieldVisit(c *gin.Context) {
+	visitID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid field visit ID.")
+		return
+	}
+
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req services.EndFieldVisitRequest
+	if err := c.ShouldBind(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	visit, err := h.fieldVisitService.EndFieldVisit(int(empIDFloat), uint(visitID), req)
+	if err != nil {
+		sendFieldVisitError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Field visit ended successfully.", visit)
+}
+
+// GetMyFieldVisits retrieves the field visits of the logged-in employee.
+func (h *fieldVisitHandler) GetMyFieldVisits(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	visits, err := h.fieldVisitService.GetMyFieldVisits(int(empIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve field visits.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Field visits retrieved successfully.", visits)
+}
+
+// Admin Handlers
+
+// GetFieldVisitTimeline retrieves the per-employee field visit timeline of a day, optionally for one employee.
+func (h *fieldVisitHandler) GetFieldVisitTimeline(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	date := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD.")
+			return
+		}
+		date = parsed
+	}
+
+	var employeeID *int
+	if employeeIDStr := c.Query("employee_id"); employeeIDStr != "" {
+		id, err := strconv.Atoi(employeeIDStr)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid employee ID.")
+			return
+		}
+		employeeID = &id
+	}
+
+	timeline, err := h.fieldVisitService.GetFieldVisitTimeline(int(compIDFloat), date, employeeID)
+	if err != nil {
+		sendFieldVisitError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Field visit timeline retrieved successfully.", timeline)
+}
+
+// GetClientSites lists the client sites of the company. Employees use it to pick the site of a visit.
+func (h *fieldVisitHandler) GetClientSites(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	sites, err := h.fieldVisitService.GetClientSites(uint(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve client sites.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Client sites retrieved successfully.", sites)
+}
+
+// CreateClientSite registers a client site for the admin's company.
+func (h *fieldVisitHandler) CreateClientSite(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.ClientSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	site, err := h.fieldVisitService.CreateClientSite(uint(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to create client site.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Client site created successfully.", site)
+}
+
+// UpdateClientSite updates one of the company's client sites.
+func (h *fieldVisitHandler) UpdateClientSite(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid client site ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.ClientSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	site, err := h.fieldVisitService.UpdateClientSite(uint(compIDFloat), uint(siteID), req)
+	if err != nil {
+		sendFieldVisitError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Client site updated successfully.", site)
+}
+
+// DeleteClientSite removes one of the company's client sites. Past visits keep their recorded coordinates.
+func (h *fieldVisitHandler) DeleteClientSite(c *gin.Context) {
+	siteID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid client site ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	if err := h.fieldVisitService.DeleteClientSite(uint(compIDFloat), uint(siteID)); err != nil {
+		sendFieldVisitError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Client site deleted successfully.", nil)
+}
+
+// sendFieldVisitError maps field visit service errors to HTTP responses.
+func sendFieldVisitError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrFaceNotRecognized) || errors.Is(err, services.ErrFieldVisitInProgress) {
+		helper.SendError(c, http.StatusConflict, err.Error())
+	} else if errors.Is(err, services.ErrFieldVisitNotFound) || errors.Is(err, services.ErrClientSiteNotFound) || errors.Is(err, services.ErrEmployeeNotFound) || errors.Is(err, services.ErrNoRegisteredFaceImages) || errors.Is(err, services.ErrCompanyNotFound) {
+		helper.SendError(c, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, services.ErrFieldVisitAlreadyEnded) || errors.Is(err, services.ErrCustomerNameRequired) || errors.Is(err, services.ErrMockLocationDetected) {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+	} else {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+	}
+}
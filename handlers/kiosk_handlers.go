@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+	"go-face-auth/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KioskHandler defines the interface for offline kiosk handlers.
+type KioskHandler interface {
+	RegisterKioskDevice(c *gin.Context)
+	GetKioskDevices(c *gin.Context)
+	RevokeKioskDevice(c *gin.Context)
+	UploadKioskBatch(hub *websocket.Hub) gin.HandlerFunc
+	GetKioskBatches(c *gin.Context)
+	GetKioskBatch(c *gin.Context)
+}
+
+// kioskHandler is the concrete implementation of KioskHandler.
+type kioskHandler struct {
+	kioskService        services.KioskService
+	adminCompanyService services.AdminCompanyService
+}
+
+// NewKioskHandler creates a new instance of KioskHandler.
+func NewKioskHandler(kioskService services.KioskService, adminCompanyService services.AdminCompanyService) KioskHandler {
+	return &kioskHandler{
+		kioskService:        kioskService,
+		adminCompanyService: adminCompanyService,
+	}
+}
+
+// RegisterKioskDevice registers a kiosk device with the public half of its signing key.
+func (h *kioskHandler) RegisterKioskDevice(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.RegisterKioskDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	device, err := h.kioskService.RegisterKioskDevice(int(compIDFloat), uint(adminIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidKioskPublicKey) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Kiosk device registered successfully.", device)
+}
+
+// GetKioskDevices lists the company's kiosk devices.
+func (h *kioskHandler) GetKioskDevices(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	devices, err := h.kioskService.GetKioskDevices(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve kiosk devices.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Kiosk devices retrieved successfully.", devices)
+}
+
+// RevokeKioskDevice blocks further uploads from a device.
+func (h *kioskHandler) RevokeKioskDevice(c *gin.Context) {
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid kiosk device ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	device, err := h.kioskService.RevokeKioskDevice(int(compIDFloat), uint(deviceID))
+	if err != nil {
+		if errors.Is(err, services.ErrKioskDeviceNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Kiosk device revoked successfully.", device)
+}
+
+// UploadKioskBatch receives the check-ins a kiosk captured while offline. The device is authenticated by
+// the signatures on its entries rather than a login token, which may have expired while it was offline.
+func (h *kioskHandler) UploadKioskBatch(hub *websocket.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req services.KioskBatchUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+			return
+		}
+
+		batch, err := h.kioskService.UploadKioskBatch(req)
+		if err != nil {
+			if errors.Is(err, services.ErrKioskDeviceNotFound) || errors.Is(err, services.ErrKioskDeviceRevoked) || errors.Is(err, services.ErrKioskBatchUnverified) {
+				helper.SendError(c, http.StatusForbidden, err.Error())
+			} else {
+				helper.SendError(c, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		if batch.AcceptedCount > 0 {
+			go func() {
+				summary, err := h.adminCompanyService.GetDashboardSummaryData(batch.CompanyID)
+				if err != nil {
+					log.Printf("Error fetching dashboard summary for WebSocket update: %v", err)
+					return
+				}
+				hub.SendDashboardUpdate(batch.CompanyID, summary)
+			}()
+		}
+
+		helper.SendSuccess(c, http.StatusOK, "Kiosk batch processed.", batch)
+	}
+}
+
+// GetKioskBatches lists uploaded batches; pass status=flagged to see the ones needing review.
+func (h *kioskHandler) GetKioskBatches(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	batches, totalRecords, err := h.kioskService.GetKioskBatches(int(compIDFloat), c.Query("status"), page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve kiosk batches.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         batches,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Kiosk batches retrieved successfully.", paginatedData)
+}
+
+// GetKioskBatch returns a batch with the processing result of each entry.
+func (h *kioskHandler) GetKioskBatch(c *gin.Context) {
+	batchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid kiosk batch ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	batch, err := h.kioskService.GetKioskBatch(int(compIDFloat), uint(batchID))
+	if err != nil {
+		if errors.Is(err, services.ErrKioskBatchNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Kiosk batch retrieved successfully.", batch)
+}
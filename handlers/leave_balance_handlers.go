@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaveBalanceHandler defines the interface for leave policy and leave balance handlers.
+type LeaveBalanceHandler interface {
+	GetMyLeaveBalance(c *gin.Context)
+	GetLeavePolicy(c *gin.Context)
+	UpdateLeavePolicy(c *gin.Context)
+	GetCompanyLeaveBalances(c *gin.Context)
+	GetEmployeeLeaveBalance(c *gin.Context)
+	AdjustLeaveBalance(c *gin.Context)
+}
+
+// leaveBalanceHandler is the concrete implementation of LeaveBalanceHandler.
+type leaveBalanceHandler struct {
+	leaveBalanceService services.LeaveBalanceService
+}
+
+// NewLeaveBalanceHandler creates a new instance of LeaveBalanceHandler.
+func NewLeaveBalanceHandler(leaveBalanceService services.LeaveBalanceService) LeaveBalanceHandler {
+	return &leaveBalanceHandler{
+		leaveBalanceService: leaveBalanceService,
+	}
+}
+
+// parseLeaveYear reads the optional "year" query parameter; zero means the current year.
+func parseLeaveYear(c *gin.Context) (int, bool) {
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		return 0, true
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		helper.SendError(c, http.StatusBadRequest, "Invalid year.")
+		return 0, false
+	}
+	return year, true
+}
+
+// sendLeaveBalanceError maps leave balance errors to responses.
+func sendLeaveBalanceError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrLeavePolicyNotFound) || errors.Is(err, services.ErrEmployeeNotFound) {
+		helper.SendError(c, http.StatusNotFound, err.Error())
+	} else {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Employee Handlers
+
+// GetMyLeaveBalance returns the logged-in employee's annual leave balance and ledger for a year.
+func (h *leaveBalanceHandler) GetMyLeaveBalance(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	year, ok := parseLeaveYear(c)
+	if !ok {
+		return
+	}
+
+	balance, err := h.leaveBalanceService.GetMyLeaveBalance(int(empIDFloat), year)
+	if err != nil {
+		sendLeaveBalanceError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave balance retrieved successfully.", balance)
+}
+
+// Admin Handlers
+
+// GetLeavePolicy returns the company's leave policy.
+func (h *leaveBalanceHandler) GetLeavePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	policy, err := h.leaveBalanceService.GetLeavePolicy(int(compIDFloat))
+	if err != nil {
+		sendLeaveBalanceError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave policy retrieved successfully.", policy)
+}
+
+// UpdateLeavePolicy saves the company's leave policy, which starts tracking leave balances the first time.
+func (h *leaveBalanceHandler) UpdateLeavePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.UpdateLeavePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	policy, err := h.leaveBalanceService.UpdateLeavePolicy(int(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave policy updated successfully.", policy)
+}
+
+// GetCompanyLeaveBalances lists the balances of the company's employees for a year, filtered by employee name.
+func (h *leaveBalanceHandler) GetCompanyLeaveBalances(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	year, ok := parseLeaveYear(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+
+	balances, totalRecords, err := h.leaveBalanceService.GetCompanyLeaveBalances(int(compIDFloat), year, search, page, pageSize)
+	if err != nil {
+		sendLeaveBalanceError(c, err)
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         balances,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave balances retrieved successfully.", paginatedData)
+}
+
+// GetEmployeeLeaveBalance returns an employee's balance and ledger for a year.
+func (h *leaveBalanceHandler) GetEmployeeLeaveBalance(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employeeID"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid employee ID.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	year, ok := parseLeaveYear(c)
+	if !ok {
+		return
+	}
+
+	balance, err := h.leaveBalanceService.GetEmployeeLeaveBalance(int(compIDFloat), employeeID, year)
+	if err != nil {
+		sendLeaveBalanceError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave balance retrieved successfully.", balance)
+}
+
+// AdjustLeaveBalance adds or takes away days from an employee's balance, e.g. for leave taken before the policy.
+func (h *leaveBalanceHandler) AdjustLeaveBalance(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("employeeID"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid employee ID.")
+		return
+	}
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.AdjustLeaveBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	entry, err := h.leaveBalanceService.AdjustLeaveBalance(uint(adminIDFloat), int(compIDFloat), employeeID, req)
+	if err != nil {
+		sendLeaveBalanceError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Leave balance adjusted successfully.", entry)
+}
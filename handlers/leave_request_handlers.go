@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"mime/multipart"
 	"net/http"
@@ -13,7 +13,6 @@ import (
 	"go-face-auth/websocket"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 // LeaveRequestHandler defines the interface for leave request related handlers.
@@ -24,7 +23,7 @@ type LeaveRequestHandler interface {
 	GetAllCompanyLeaveRequests(c *gin.Context)
 	ReviewLeaveRequest(hub *websocket.Hub) gin.HandlerFunc
 	AdminCancelApprovedLeaveHandler(c *gin.Context)
-	ExportCompanyLeaveRequestsToExcel(c *gin.Context)
+	ExportCompanyLeaveRequests(c *gin.Context)
 }
 
 // leaveRequestHandler is the concrete implementation of LeaveRequestHandler.
@@ -42,11 +41,11 @@ func NewLeaveRequestHandler(leaveRequestService services.LeaveRequestService, ad
 }
 
 type CreateLeaveRequestPayload struct {
-	Type      string `form:"type" binding:"required,oneof=cuti sakit"`
+	Type      string `form:"type" binding:"required,max=50"` // Code of one of the company's leave types
 	StartDate string `form:"start_date" binding:"required,datetime=2006-01-02"`
 	EndDate   string `form:"end_date" binding:"required,datetime=2006-01-02"`
 	Reason    string `form:"reason" binding:"required,min=10"`
-	SickNote  *multipart.FileHeader `form:"sick_note"` // For file upload
+	SickNote  *multipart.FileHeader `form:"sick_note"` // Attachment, e.g. a sick note, required by some leave types
 }
 
 // Employee Handlers
@@ -71,12 +70,6 @@ func (h *leaveRequestHandler) ApplyLeave(c *gin.Context) {
 		return
 	}
 
-	// Validate sick note upload for sick leave type
-	if req.Type == "sakit" && req.SickNote == nil {
-		helper.SendError(c, http.StatusBadRequest, "Surat sakit wajib diunggah untuk izin sakit.")
-		return
-	}
-
 	leaveRequest, err := h.leaveRequestService.ApplyLeave(empID, req.Type, req.StartDate, req.EndDate, req.Reason, req.SickNote)
 	if err != nil {
 		helper.SendError(c, http.StatusBadRequest, err.Error())
@@ -246,7 +239,11 @@ func (h *leaveRequestHandler) ReviewLeaveRequest(hub *websocket.Hub) gin.Handler
 
 		leaveRequest, err := h.leaveRequestService.ReviewLeaveRequest(uint(leaveRequestID), adminIDVal, req.Status)
 		if err != nil {
-			helper.SendError(c, http.StatusForbidden, err.Error())
+			if errors.Is(err, services.ErrInsufficientLeaveBalance) || errors.Is(err, services.ErrLeaveDuringProbation) {
+				helper.SendError(c, http.StatusConflict, err.Error())
+			} else {
+				helper.SendError(c, http.StatusForbidden, err.Error())
+			}
 			return
 		}
 
@@ -303,8 +300,8 @@ func (h *leaveRequestHandler) AdminCancelApprovedLeaveHandler(c *gin.Context) {
 	helper.SendSuccess(c, http.StatusOK, "Approved leave request cancelled successfully.", nil)
 }
 
-// ExportCompanyLeaveRequestsToExcel exports all leave request records for the company to an Excel file.
-func (h *leaveRequestHandler) ExportCompanyLeaveRequestsToExcel(c *gin.Context) {
+// ExportCompanyLeaveRequests exports all leave request records for the company as xlsx, csv or pdf.
+func (h *leaveRequestHandler) ExportCompanyLeaveRequests(c *gin.Context) {
 	companyID, exists := c.Get("companyID")
 	if !exists {
 		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
@@ -317,6 +314,11 @@ func (h *leaveRequestHandler) ExportCompanyLeaveRequestsToExcel(c *gin.Context)
 	}
 	compID := int(compIDFloat)
 
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
 	status := c.Query("status")
 	search := c.Query("search")
 
@@ -341,70 +343,11 @@ func (h *leaveRequestHandler) ExportCompanyLeaveRequestsToExcel(c *gin.Context)
 		endDate = &parsed
 	}
 
-	leaveRequests, err := h.leaveRequestService.ExportCompanyLeaveRequests(compID, status, search, startDate, endDate)
+	report, err := h.leaveRequestService.ExportCompanyLeaveRequests(compID, status, search, startDate, endDate)
 	if err != nil {
 		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve leave requests for export.")
 		return
 	}
 
-	f := excelize.NewFile()
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.Printf("Error closing excel file: %v", err)
-		}
-	}()
-
-	sheetName := "Leave Requests"
-	f.SetSheetName("Sheet1", sheetName)
-
-	// Set headers
-	f.SetCellValue(sheetName, "A1", "Employee Name")
-	f.SetCellValue(sheetName, "B1", "Type")
-	f.SetCellValue(sheetName, "C1", "Start Date")
-	f.SetCellValue(sheetName, "D1", "End Date")
-	f.SetCellValue(sheetName, "E1", "Reason")
-	f.SetCellValue(sheetName, "F1", "Status")
-
-	// Apply style to header row
-	style, err := f.NewStyle(&excelize.Style{
-		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
-		Font:      &excelize.Font{Bold: true},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
-	if err != nil {
-		log.Printf("Error creating style: %v", err)
-	} else {
-		f.SetCellStyle(sheetName, "A1", "F1", style)
-	}
-
-	// Populate data
-	for i, lr := range leaveRequests {
-		row := i + 2 // Start from row 2 after headers
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), lr.Employee.Name)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), lr.Type)
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), lr.StartDate.Format("2006-01-02"))
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), lr.EndDate.Format("2006-01-02"))
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), lr.Reason)
-		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), lr.Status)
-	}
-
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-
-	fileName := "leave_requests.xlsx"
-	dateRange := ""
-	if startDate != nil && endDate != nil {
-		dateRange = fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	} else if startDate != nil {
-		dateRange = fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
-	} else if endDate != nil {
-		dateRange = fmt.Sprintf("_until_%s", endDate.Format("2006-01-02"))
-	}
-	fileName = fmt.Sprintf("company_leave_requests%s.xlsx", dateRange)
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
-
-	if err := f.Write(c.Writer); err != nil {
-		log.Printf("Error writing excel file to response: %v", err)
-		helper.SendError(c, http.StatusInternalServerError, "Failed to generate Excel file.")
-		return
-	}
-}
\ No newline at end of file
+	sendReport(c, report, format)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaveTypeHandler defines the interface for leave type catalogue handlers.
+type LeaveTypeHandler interface {
+	GetLeaveTypes(c *gin.Context)
+	CreateLeaveType(c *gin.Context)
+	UpdateLeaveType(c *gin.Context)
+	GetAvailableLeaveTypes(c *gin.Context)
+}
+
+// leaveTypeHandler is the concrete implementation of LeaveTypeHandler.
+type leaveTypeHandler struct {
+	leaveTypeService services.LeaveTypeService
+}
+
+// NewLeaveTypeHandler creates a new instance of LeaveTypeHandler.
+func NewLeaveTypeHandler(leaveTypeService services.LeaveTypeService) LeaveTypeHandler {
+	return &leaveTypeHandler{
+		leaveTypeService: leaveTypeService,
+	}
+}
+
+// Admin Handlers
+
+// GetLeaveTypes lists the company's leave types, including deactivated ones.
+func (h *leaveTypeHandler) GetLeaveTypes(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	leaveTypes, err := h.leaveTypeService.GetLeaveTypes(int(compIDFloat), false)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave types retrieved successfully.", leaveTypes)
+}
+
+// CreateLeaveType adds a leave type to the company's catalogue.
+func (h *leaveTypeHandler) CreateLeaveType(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.CreateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	leaveType, err := h.leaveTypeService.CreateLeaveType(int(compIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLeaveTypeCode) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, services.ErrLeaveTypeCodeTaken) {
+			helper.SendError(c, http.StatusConflict, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Leave type created successfully.", leaveType)
+}
+
+// UpdateLeaveType changes a leave type's settings. Setting is_active to false deactivates it.
+func (h *leaveTypeHandler) UpdateLeaveType(c *gin.Context) {
+	leaveTypeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid leave type ID.")
+		return
+	}
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.LeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	leaveType, err := h.leaveTypeService.UpdateLeaveType(int(compIDFloat), uint(leaveTypeID), req)
+	if err != nil {
+		if errors.Is(err, services.ErrLeaveTypeNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave type updated successfully.", leaveType)
+}
+
+// Employee Handlers
+
+// GetAvailableLeaveTypes lists the leave types the logged-in employee can apply for.
+func (h *leaveTypeHandler) GetAvailableLeaveTypes(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	leaveTypes, err := h.leaveTypeService.GetLeaveTypes(int(compIDFloat), true)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Leave types retrieved successfully.", leaveTypes)
+}
@@ -8,16 +8,22 @@ import (
 	"net/http"
 	"strconv"
 	"errors"
+	"io"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxGeofenceFileSize limits uploaded GeoJSON/KML files to 5 MB.
+const maxGeofenceFileSize = 5 << 20
+
 // LocationHandler defines the interface for location related handlers.
 type LocationHandler interface {
 	CreateAttendanceLocation(c *gin.Context)
 	GetAttendanceLocations(c *gin.Context)
 	UpdateAttendanceLocation(c *gin.Context)
 	DeleteAttendanceLocation(c *gin.Context)
+	ImportAttendanceLocations(c *gin.Context)
+	GetLocationQRCode(c *gin.Context)
 }
 
 // locationHandler is the concrete implementation of LocationHandler.
@@ -50,6 +56,8 @@ func (h *locationHandler) CreateAttendanceLocation(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, services.ErrLocationLimitReached) {
 			helper.SendError(c, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, services.ErrInvalidLocationGeometry) || errors.Is(err, services.ErrInvalidLocationProofs) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
 		} else {
 			helper.SendError(c, http.StatusInternalServerError, err.Error())
 		}
@@ -98,7 +106,11 @@ func (h *locationHandler) UpdateAttendanceLocation(c *gin.Context) {
 
 	updatedLocation, err := h.locationService.UpdateAttendanceLocation(companyID, uint(locationID), &locationUpdates)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to update attendance location.")
+		if errors.Is(err, services.ErrInvalidLocationGeometry) || errors.Is(err, services.ErrInvalidLocationProofs) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to update attendance location.")
+		}
 		return
 	}
 
@@ -127,6 +139,84 @@ func (h *locationHandler) DeleteAttendanceLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Attendance location deleted successfully"})
 }
 
+// ImportAttendanceLocations creates polygon locations from an uploaded GeoJSON or KML file
+func (h *locationHandler) ImportAttendanceLocations(c *gin.Context) {
+	companyID, err := getCompanyIDFromContext(c)
+	if err != nil {
+		helper.SendError(c, http.StatusUnauthorized, "Unauthorized: Invalid company information.")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "A GeoJSON or KML file is required.")
+		return
+	}
+	if fileHeader.Size > maxGeofenceFileSize {
+		helper.SendError(c, http.StatusBadRequest, "File is too large.")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Failed to read uploaded file.")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Failed to read uploaded file.")
+		return
+	}
+
+	locations, err := h.locationService.ImportAttendanceLocations(companyID, fileHeader.Filename, data)
+	if err != nil {
+		if errors.Is(err, services.ErrLocationLimitReached) {
+			helper.SendError(c, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, services.ErrInvalidLocationGeometry) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Locations imported successfully", locations)
+}
+
+// GetLocationQRCode returns the rotating QR code to show on the screen at a location
+func (h *locationHandler) GetLocationQRCode(c *gin.Context) {
+	companyID, err := getCompanyIDFromContext(c)
+	if err != nil {
+		helper.SendError(c, http.StatusUnauthorized, "Unauthorized: Invalid company information.")
+		return
+	}
+
+	locationID, err := strconv.ParseUint(c.Param("location_id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid location ID.")
+		return
+	}
+
+	code, expiresAt, err := h.locationService.GetLocationQRCode(companyID, uint(locationID))
+	if err != nil {
+		if errors.Is(err, services.ErrLocationNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrLocationQRCodeDisabled) {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to generate QR code.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "QR code generated successfully", gin.H{
+		"code":       code,
+		"expires_at": expiresAt,
+	})
+}
+
 // Utility function to get companyID from context
 func getCompanyIDFromContext(c *gin.Context) (uint, error) {
 	companyIDClaim, exists := c.Get("companyID")
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OvertimePolicyHandler defines the interface for overtime compensation handlers.
+type OvertimePolicyHandler interface {
+	GetOvertimePolicy(c *gin.Context)
+	UpdateOvertimePolicy(c *gin.Context)
+	GetOvertimeCompensation(c *gin.Context)
+	GetPublicHolidays(c *gin.Context)
+	CreatePublicHoliday(c *gin.Context)
+	DeletePublicHoliday(c *gin.Context)
+}
+
+// overtimePolicyHandler is the concrete implementation of OvertimePolicyHandler.
+type overtimePolicyHandler struct {
+	overtimePolicyService services.OvertimePolicyService
+}
+
+// NewOvertimePolicyHandler creates a new instance of OvertimePolicyHandler.
+func NewOvertimePolicyHandler(overtimePolicyService services.OvertimePolicyService) OvertimePolicyHandler {
+	return &overtimePolicyHandler{
+		overtimePolicyService: overtimePolicyService,
+	}
+}
+
+// GetOvertimePolicy returns the company's overtime multipliers, falling back to the statutory defaults.
+func (h *overtimePolicyHandler) GetOvertimePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	policy, err := h.overtimePolicyService.GetOvertimePolicy(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime policy retrieved successfully.", policy)
+}
+
+// UpdateOvertimePolicy saves the company's overtime multipliers.
+func (h *overtimePolicyHandler) UpdateOvertimePolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.UpdateOvertimePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	policy, err := h.overtimePolicyService.UpdateOvertimePolicy(int(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime policy updated successfully.", policy)
+}
+
+// GetOvertimeCompensation returns weighted overtime hours per employee for the requested period.
+func (h *overtimePolicyHandler) GetOvertimeCompensation(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.overtimePolicyService.GetOvertimeCompensationSummary(int(compIDFloat), startDate, endDate, c.Query("search"))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime compensation retrieved successfully.", summary)
+}
+
+// GetPublicHolidays lists the company's public holidays.
+func (h *overtimePolicyHandler) GetPublicHolidays(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	holidays, err := h.overtimePolicyService.GetPublicHolidays(int(compIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve public holidays.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Public holidays retrieved successfully.", holidays)
+}
+
+// CreatePublicHoliday adds a public holiday for the company.
+func (h *overtimePolicyHandler) CreatePublicHoliday(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.CreatePublicHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	holiday, err := h.overtimePolicyService.CreatePublicHoliday(int(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Public holiday created successfully.", holiday)
+}
+
+// DeletePublicHoliday removes one of the company's public holidays.
+func (h *overtimePolicyHandler) DeletePublicHoliday(c *gin.Context) {
+	holidayID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid public holiday ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	if err := h.overtimePolicyService.DeletePublicHoliday(int(compIDFloat), uint(holidayID)); err != nil {
+		if errors.Is(err, services.ErrPublicHolidayNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to delete public holiday.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Public holiday deleted successfully.", nil)
+}
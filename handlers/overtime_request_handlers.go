@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OvertimeRequestHandler defines the interface for overtime request related handlers.
+type OvertimeRequestHandler interface {
+	SubmitOvertimeRequest(c *gin.Context)
+	GetMyOvertimeRequests(c *gin.Context)
+	CancelOvertimeRequest(c *gin.Context)
+	GetCompanyOvertimeRequests(c *gin.Context)
+	ReviewOvertimeRequest(c *gin.Context)
+	ReapproveOvertimeRequest(c *gin.Context)
+}
+
+// overtimeRequestHandler is the concrete implementation of OvertimeRequestHandler.
+type overtimeRequestHandler struct {
+	overtimeRequestService services.OvertimeRequestService
+}
+
+// NewOvertimeRequestHandler creates a new instance of OvertimeRequestHandler.
+func NewOvertimeRequestHandler(overtimeRequestService services.OvertimeRequestService) OvertimeRequestHandler {
+	return &overtimeRequestHandler{
+		overtimeRequestService: overtimeRequestService,
+	}
+}
+
+// Employee Handlers
+
+// SubmitOvertimeRequest submits an overtime request for the employee, or for a supervised employee.
+func (h *overtimeRequestHandler) SubmitOvertimeRequest(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req services.CreateOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	overtimeRequest, err := h.overtimeRequestService.SubmitOvertimeRequest(uint(empIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrOvertimeRequestUnauthorized) {
+			helper.SendError(c, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, services.ErrEmployeeNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Overtime request submitted successfully.", overtimeRequest)
+}
+
+// GetMyOvertimeRequests retrieves the overtime requests of the logged-in employee.
+func (h *overtimeRequestHandler) GetMyOvertimeRequests(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	overtimeRequests, err := h.overtimeRequestService.GetMyOvertimeRequests(uint(empIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve overtime requests.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime requests retrieved successfully.", overtimeRequests)
+}
+
+// CancelOvertimeRequest cancels a pending overtime request.
+func (h *overtimeRequestHandler) CancelOvertimeRequest(c *gin.Context) {
+	overtimeRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid overtime request ID.")
+		return
+	}
+
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	if _, err := h.overtimeRequestService.CancelOvertimeRequest(uint(overtimeRequestID), uint(empIDFloat)); err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime request cancelled successfully.", nil)
+}
+
+// Admin Handlers
+
+// GetCompanyOvertimeRequests retrieves paginated overtime requests for the admin's company.
+func (h *overtimeRequestHandler) GetCompanyOvertimeRequests(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+	compID := int(compIDFloat)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+	search := c.Query("search")
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	overtimeRequests, totalRecords, err := h.overtimeRequestService.GetCompanyOvertimeRequests(compID, status, search, startDate, endDate, page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve overtime requests.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         overtimeRequests,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime requests retrieved successfully.", paginatedData)
+}
+
+// ReviewOvertimeRequest approves or rejects a pending overtime request.
+func (h *overtimeRequestHandler) ReviewOvertimeRequest(c *gin.Context) {
+	overtimeRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid overtime request ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.ReviewOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	overtimeRequest, err := h.overtimeRequestService.ReviewOvertimeRequest(uint(overtimeRequestID), uint(adminIDFloat), req)
+	if err != nil {
+		sendOvertimeRequestError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime request status updated successfully.", overtimeRequest)
+}
+
+// ReapproveOvertimeRequest changes the approved hours of an already approved overtime request.
+func (h *overtimeRequestHandler) ReapproveOvertimeRequest(c *gin.Context) {
+	overtimeRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid overtime request ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.ReapproveOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	overtimeRequest, err := h.overtimeRequestService.ReapproveOvertimeRequest(uint(overtimeRequestID), uint(adminIDFloat), req)
+	if err != nil {
+		sendOvertimeRequestError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Overtime request re-approved successfully.", overtimeRequest)
+}
+
+// sendOvertimeRequestError maps overtime request service errors to HTTP responses.
+func sendOvertimeRequestError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrOvertimeRequestNotFound) {
+		helper.SendError(c, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, services.ErrOvertimeRequestUnauthorized) {
+		helper.SendError(c, http.StatusForbidden, err.Error())
+	} else if errors.Is(err, services.ErrOvertimeRequestNotPending) || errors.Is(err, services.ErrOvertimeRequestNotApproved) {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+	} else {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// parseDateRangeQuery reads the optional startDate and endDate query parameters (YYYY-MM-DD).
+// It writes the error response itself and returns false when a date is malformed.
+func parseDateRangeQuery(c *gin.Context) (*time.Time, *time.Time, bool) {
+	var startDate, endDate *time.Time
+	if startDateStr := c.Query("startDate"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid start date format. Use YYYY-MM-DD.")
+			return nil, nil, false
+		}
+		startDate = &parsed
+	}
+	if endDateStr := c.Query("endDate"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid end date format. Use YYYY-MM-DD.")
+			return nil, nil, false
+		}
+		endDate = &parsed
+	}
+	return startDate, endDate, true
+}
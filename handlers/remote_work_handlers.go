@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RemoteWorkHandler defines the interface for remote (work-from-home) handlers.
+type RemoteWorkHandler interface {
+	SubmitRemoteWorkRequest(c *gin.Context)
+	GetMyRemoteWorkRequests(c *gin.Context)
+	CancelRemoteWorkRequest(c *gin.Context)
+	RegisterHomeLocation(c *gin.Context)
+	GetMyHomeLocation(c *gin.Context)
+	GetRemoteWorkPolicy(c *gin.Context)
+	UpdateRemoteWorkPolicy(c *gin.Context)
+	GetRemoteWorkSchedules(c *gin.Context)
+	CreateRemoteWorkSchedule(c *gin.Context)
+	DeleteRemoteWorkSchedule(c *gin.Context)
+	GetCompanyRemoteWorkRequests(c *gin.Context)
+	ReviewRemoteWorkRequest(c *gin.Context)
+	GetCompanyHomeLocations(c *gin.Context)
+	ReviewHomeLocation(c *gin.Context)
+}
+
+// remoteWorkHandler is the concrete implementation of RemoteWorkHandler.
+type remoteWorkHandler struct {
+	remoteWorkService services.RemoteWorkService
+}
+
+// NewRemoteWorkHandler creates a new instance of RemoteWorkHandler.
+func NewRemoteWorkHandler(remoteWorkService services.RemoteWorkService) RemoteWorkHandler {
+	return &remoteWorkHandler{
+		remoteWorkService: remoteWorkService,
+	}
+}
+
+// Employee Handlers
+
+// SubmitRemoteWorkRequest submits a request to work remotely on a range of days.
+func (h *remoteWorkHandler) SubmitRemoteWorkRequest(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req services.CreateRemoteWorkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	remoteWorkRequest, err := h.remoteWorkService.SubmitRemoteWorkRequest(uint(empIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Remote work request submitted successfully.", remoteWorkRequest)
+}
+
+// GetMyRemoteWorkRequests retrieves the remote work requests of the logged-in employee.
+func (h *remoteWorkHandler) GetMyRemoteWorkRequests(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	remoteWorkRequests, err := h.remoteWorkService.GetMyRemoteWorkRequests(uint(empIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve remote work requests.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work requests retrieved successfully.", remoteWorkRequests)
+}
+
+// CancelRemoteWorkRequest cancels a pending remote work request.
+func (h *remoteWorkHandler) CancelRemoteWorkRequest(c *gin.Context) {
+	remoteWorkRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid remote work request ID.")
+		return
+	}
+
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	if _, err := h.remoteWorkService.CancelRemoteWorkRequest(uint(remoteWorkRequestID), uint(empIDFloat)); err != nil {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work request cancelled successfully.", nil)
+}
+
+// RegisterHomeLocation registers or moves the employee's home location for remote check-ins.
+func (h *remoteWorkHandler) RegisterHomeLocation(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	var req services.RegisterHomeLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	homeLocation, err := h.remoteWorkService.RegisterHomeLocation(uint(empIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Home location submitted for approval.", homeLocation)
+}
+
+// GetMyHomeLocation returns the logged-in employee's home location and its approval status.
+func (h *remoteWorkHandler) GetMyHomeLocation(c *gin.Context) {
+	employeeID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Employee ID not found in token")
+		return
+	}
+	empIDFloat, ok := employeeID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid employee ID type in token claims.")
+		return
+	}
+
+	homeLocation, err := h.remoteWorkService.GetMyHomeLocation(uint(empIDFloat))
+	if err != nil {
+		if errors.Is(err, services.ErrHomeLocationNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve home location.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Home location retrieved successfully.", homeLocation)
+}
+
+// Admin Handlers
+
+// GetRemoteWorkPolicy returns the company's remote work policy, falling back to the default.
+func (h *remoteWorkHandler) GetRemoteWorkPolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	policy, err := h.remoteWorkService.GetRemoteWorkPolicy(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work policy retrieved successfully.", policy)
+}
+
+// UpdateRemoteWorkPolicy saves how remote check-ins are located.
+func (h *remoteWorkHandler) UpdateRemoteWorkPolicy(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.UpdateRemoteWorkPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	policy, err := h.remoteWorkService.UpdateRemoteWorkPolicy(int(compIDFloat), req)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work policy updated successfully.", policy)
+}
+
+// GetRemoteWorkSchedules lists the company's recurring remote work schedules.
+func (h *remoteWorkHandler) GetRemoteWorkSchedules(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	schedules, err := h.remoteWorkService.GetRemoteWorkSchedules(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve remote work schedules.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work schedules retrieved successfully.", schedules)
+}
+
+// CreateRemoteWorkSchedule gives an employee or a division recurring remote work days.
+func (h *remoteWorkHandler) CreateRemoteWorkSchedule(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.CreateRemoteWorkScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	schedule, err := h.remoteWorkService.CreateRemoteWorkSchedule(int(compIDFloat), req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmployeeNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Remote work schedule created successfully.", schedule)
+}
+
+// DeleteRemoteWorkSchedule removes one of the company's remote work schedules.
+func (h *remoteWorkHandler) DeleteRemoteWorkSchedule(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid remote work schedule ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	if err := h.remoteWorkService.DeleteRemoteWorkSchedule(int(compIDFloat), uint(scheduleID)); err != nil {
+		if errors.Is(err, services.ErrRemoteWorkScheduleNotFound) {
+			helper.SendError(c, http.StatusNotFound, err.Error())
+		} else {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to delete remote work schedule.")
+		}
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work schedule deleted successfully.", nil)
+}
+
+// GetCompanyRemoteWorkRequests retrieves paginated remote work requests for the admin's company.
+func (h *remoteWorkHandler) GetCompanyRemoteWorkRequests(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+	compID := int(compIDFloat)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := c.Query("status")
+	search := c.Query("search")
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return
+	}
+
+	remoteWorkRequests, totalRecords, err := h.remoteWorkService.GetCompanyRemoteWorkRequests(compID, status, search, startDate, endDate, page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve remote work requests.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         remoteWorkRequests,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work requests retrieved successfully.", paginatedData)
+}
+
+// ReviewRemoteWorkRequest approves or rejects a pending remote work request.
+func (h *remoteWorkHandler) ReviewRemoteWorkRequest(c *gin.Context) {
+	remoteWorkRequestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid remote work request ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.ReviewRemoteWorkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	remoteWorkRequest, err := h.remoteWorkService.ReviewRemoteWorkRequest(uint(remoteWorkRequestID), uint(adminIDFloat), req)
+	if err != nil {
+		sendRemoteWorkError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Remote work request status updated successfully.", remoteWorkRequest)
+}
+
+// GetCompanyHomeLocations lists the home locations registered by the company's employees.
+func (h *remoteWorkHandler) GetCompanyHomeLocations(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	homeLocations, err := h.remoteWorkService.GetCompanyHomeLocations(int(compIDFloat), c.Query("status"))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve home locations.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Home locations retrieved successfully.", homeLocations)
+}
+
+// ReviewHomeLocation approves or rejects an employee's home location.
+func (h *remoteWorkHandler) ReviewHomeLocation(c *gin.Context) {
+	homeLocationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid home location ID.")
+		return
+	}
+
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	var req services.ReviewHomeLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	homeLocation, err := h.remoteWorkService.ReviewHomeLocation(uint(homeLocationID), uint(adminIDFloat), req)
+	if err != nil {
+		sendRemoteWorkError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Home location status updated successfully.", homeLocation)
+}
+
+// sendRemoteWorkError maps remote work service errors to HTTP responses.
+func sendRemoteWorkError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrRemoteWorkRequestNotFound) || errors.Is(err, services.ErrHomeLocationNotFound) {
+		helper.SendError(c, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, services.ErrRemoteWorkRequestUnauthorized) {
+		helper.SendError(c, http.StatusForbidden, err.Error())
+	} else if errors.Is(err, services.ErrRemoteWorkRequestNotPending) {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+	} else {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"go-face-auth/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseReportFormat reads the "format" query parameter of an export, defaulting to xlsx.
+func parseReportFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", helper.ReportFormatXLSX)
+	if !helper.IsValidReportFormat(format) {
+		helper.SendError(c, http.StatusBadRequest, "Invalid format. Use xlsx, csv or pdf.")
+		return "", false
+	}
+	return format, true
+}
+
+// sendReport renders a report in the requested format and sends it as a file download.
+func sendReport(c *gin.Context, report *helper.Report, format string) {
+	content, contentType, fileName, err := report.Render(format)
+	if err != nil {
+		log.Printf("Error rendering %s report %q: %v", format, report.FileName, err)
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+// streamReport streams a report into the response as a file download. The download headers are only sent
+// with the first bytes of the report, so an error before any output is still answered with a JSON error.
+func streamReport(c *gin.Context, fileName, format string, stream func(w io.Writer) error) {
+	w := &reportResponseWriter{c: c, contentType: helper.ReportContentType(format), fileName: fileName + "." + format}
+	if err := stream(w); err != nil {
+		log.Printf("Error streaming %s report %q: %v", format, fileName, err)
+		if !w.started {
+			helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
+		}
+		return
+	}
+	if !w.started {
+		w.start()
+	}
+}
+
+// reportResponseWriter writes a streamed report into the response, sending the download headers on the first write.
+type reportResponseWriter struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *reportResponseWriter) start() {
+	w.started = true
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", w.fileName))
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
+
+func (w *reportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.start()
+	}
+	return w.c.Writer.Write(p)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportSubscriptionHandler defines the interface for scheduled report handlers.
+type ReportSubscriptionHandler interface {
+	GetReportSubscriptions(c *gin.Context)
+	CreateReportSubscription(c *gin.Context)
+	UpdateReportSubscription(c *gin.Context)
+	DeleteReportSubscription(c *gin.Context)
+	SendReportNow(c *gin.Context)
+	GetReportDeliveries(c *gin.Context)
+}
+
+// reportSubscriptionHandler is the concrete implementation of ReportSubscriptionHandler.
+type reportSubscriptionHandler struct {
+	reportSubscriptionService services.ReportSubscriptionService
+}
+
+// NewReportSubscriptionHandler creates a new instance of ReportSubscriptionHandler.
+func NewReportSubscriptionHandler(reportSubscriptionService services.ReportSubscriptionService) ReportSubscriptionHandler {
+	return &reportSubscriptionHandler{
+		reportSubscriptionService: reportSubscriptionService,
+	}
+}
+
+// Admin Handlers
+
+// GetReportSubscriptions lists the company's scheduled reports.
+func (h *reportSubscriptionHandler) GetReportSubscriptions(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	subscriptions, err := h.reportSubscriptionService.GetReportSubscriptions(int(compIDFloat))
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve scheduled reports.")
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Scheduled reports retrieved successfully.", subscriptions)
+}
+
+// CreateReportSubscription subscribes recipients to a report delivered by email on a schedule.
+func (h *reportSubscriptionHandler) CreateReportSubscription(c *gin.Context) {
+	adminID, exists := c.Get("id")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Admin ID not found in token claims.")
+		return
+	}
+	adminIDFloat, ok := adminID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid admin ID type in token claims.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	subscription, err := h.reportSubscriptionService.CreateReportSubscription(uint(adminIDFloat), int(compIDFloat), req)
+	if err != nil {
+		sendReportSubscriptionError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusCreated, "Scheduled report created successfully.", subscription)
+}
+
+// UpdateReportSubscription replaces the settings of a scheduled report.
+func (h *reportSubscriptionHandler) UpdateReportSubscription(c *gin.Context) {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid scheduled report ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	var req services.ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helper.SendError(c, http.StatusBadRequest, helper.GetValidationError(err))
+		return
+	}
+
+	subscription, err := h.reportSubscriptionService.UpdateReportSubscription(int(compIDFloat), uint(subscriptionID), req)
+	if err != nil {
+		sendReportSubscriptionError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Scheduled report updated successfully.", subscription)
+}
+
+// DeleteReportSubscription stops a scheduled report. Its delivery history is kept.
+func (h *reportSubscriptionHandler) DeleteReportSubscription(c *gin.Context) {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid scheduled report ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	if err := h.reportSubscriptionService.DeleteReportSubscription(int(compIDFloat), uint(subscriptionID)); err != nil {
+		sendReportSubscriptionError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Scheduled report deleted successfully.", nil)
+}
+
+// SendReportNow delivers a scheduled report right away, e.g. to check its recipients and filters.
+func (h *reportSubscriptionHandler) SendReportNow(c *gin.Context) {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "Invalid scheduled report ID.")
+		return
+	}
+
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	delivery, err := h.reportSubscriptionService.SendReportNow(int(compIDFloat), uint(subscriptionID))
+	if err != nil {
+		sendReportSubscriptionError(c, err)
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Scheduled report delivered.", delivery)
+}
+
+// GetReportDeliveries lists the delivery history of the company's scheduled reports, newest first, optionally of
+// a single subscription.
+func (h *reportSubscriptionHandler) GetReportDeliveries(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token claims.")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	subscriptionID, _ := strconv.Atoi(c.Query("subscription_id"))
+
+	deliveries, totalRecords, err := h.reportSubscriptionService.GetReportDeliveries(int(compIDFloat), uint(subscriptionID), page, pageSize)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to retrieve report deliveries.")
+		return
+	}
+
+	paginatedData := gin.H{
+		"items":         deliveries,
+		"total_records": totalRecords,
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Report deliveries retrieved successfully.", paginatedData)
+}
+
+// sendReportSubscriptionError maps scheduled report errors to responses.
+func sendReportSubscriptionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrReportSubscriptionNotFound) || errors.Is(err, services.ErrCompanyNotFound) {
+		helper.SendError(c, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, services.ErrInvalidReportSendTime) || errors.Is(err, services.ErrReportScheduleDayRequired) || errors.Is(err, services.ErrInvalidTimezone) {
+		helper.SendError(c, http.StatusBadRequest, err.Error())
+	} else {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-face-auth/helper"
+	"go-face-auth/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimesheetHandler defines the interface for timesheet and payroll summary handlers.
+type TimesheetHandler interface {
+	GetTimesheet(c *gin.Context)
+	ExportTimesheet(c *gin.Context)
+}
+
+// timesheetHandler is the concrete implementation of TimesheetHandler.
+type timesheetHandler struct {
+	timesheetService services.TimesheetService
+}
+
+// NewTimesheetHandler creates a new instance of TimesheetHandler.
+func NewTimesheetHandler(timesheetService services.TimesheetService) TimesheetHandler {
+	return &timesheetHandler{
+		timesheetService: timesheetService,
+	}
+}
+
+// GetTimesheet returns the company timesheet grouped by division as JSON.
+func (h *timesheetHandler) GetTimesheet(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	startDate, endDate, ok := parseTimesheetPeriod(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.timesheetService.GetTimesheet(int(compIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helper.SendSuccess(c, http.StatusOK, "Timesheet retrieved successfully.", report)
+}
+
+// ExportTimesheet exports the company timesheet with a summary table and one table per division as xlsx, csv or pdf.
+func (h *timesheetHandler) ExportTimesheet(c *gin.Context) {
+	companyID, exists := c.Get("companyID")
+	if !exists {
+		helper.SendError(c, http.StatusUnauthorized, "Company ID not found in token")
+		return
+	}
+	compIDFloat, ok := companyID.(float64)
+	if !ok {
+		helper.SendError(c, http.StatusInternalServerError, "Invalid company ID type in token claims.")
+		return
+	}
+
+	format, ok := parseReportFormat(c)
+	if !ok {
+		return
+	}
+
+	startDate, endDate, ok := parseTimesheetPeriod(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.timesheetService.ExportTimesheet(int(compIDFloat), startDate, endDate)
+	if err != nil {
+		helper.SendError(c, http.StatusInternalServerError, "Failed to generate report file.")
+		return
+	}
+
+	sendReport(c, report, format)
+}
+
+// parseTimesheetPeriod reads either a "month" query parameter (YYYY-MM) or a startDate/endDate pair.
+// Without any of them it defaults to the current month.
+func parseTimesheetPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	if month := c.Query("month"); month != "" {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Invalid month format. Use YYYY-MM.")
+			return time.Time{}, time.Time{}, false
+		}
+		return parsed, parsed.AddDate(0, 1, -1), true
+	}
+
+	startDate, endDate, ok := parseDateRangeQuery(c)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if startDate == nil && endDate == nil {
+		now := time.Now()
+		firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return firstOfMonth, firstOfMonth.AddDate(0, 1, -1), true
+	}
+	if startDate == nil || endDate == nil {
+		helper.SendError(c, http.StatusBadRequest, "Both startDate and endDate are required.")
+		return time.Time{}, time.Time{}, false
+	}
+	return *startDate, *endDate, true
+}
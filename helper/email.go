@@ -1,13 +1,15 @@
 package helper
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"go-face-auth/config"
 	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net/smtp"
 	"net/textproto"
 	
@@ -63,47 +65,7 @@ func SendInvoiceEmail(recipientEmail, companyName, invoiceFileName string, invoi
 		<p>Hormat kami,<br>Tim Go-Face-Auth</p>
 	`, companyName)
 
-	// Create a new buffer for the email message
-	buf := new(bytes.Buffer)
-	bw := bufio.NewWriter(buf)
-	w := textproto.NewWriter(bw)
-
-	// Write email headers
-	fmt.Fprintf(w.W, "From: %s\r\n", config.SMTP_FROM)
-	fmt.Fprintf(w.W, "To: %s\r\n", recipientEmail)
-	fmt.Fprintf(w.W, "Subject: %s\r\n", subject)
-	fmt.Fprintf(w.W, "MIME-Version: 1.0\r\n")
-
-	// Generate a random boundary string
-	boundary := "GoBoundary" // A simple boundary, for more robust, use a UUID
-	fmt.Fprintf(w.W, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary)
-	fmt.Fprintf(w.W, "\r\n") // End of headers
-
-	// Write HTML part
-	fmt.Fprintf(w.W, "--%s\r\n", boundary)
-	fmt.Fprintf(w.W, "Content-Type: text/html; charset=\"UTF-8\"\r\n")
-	fmt.Fprintf(w.W, "Content-Transfer-Encoding: quoted-printable\r\n")
-	fmt.Fprintf(w.W, "\r\n")
-	fmt.Fprintf(w.W, "%s", htmlBody)
-	fmt.Fprintf(w.W, "\r\n")
-
-	// Write PDF attachment part
-	fmt.Fprintf(w.W, "--%s\r\n", boundary)
-	fmt.Fprintf(w.W, "Content-Type: application/pdf\r\n")
-	fmt.Fprintf(w.W, "Content-Transfer-Encoding: base64\r\n")
-	fmt.Fprintf(w.W, "Content-Disposition: attachment; filename=\"%s\"\r\n", invoiceFileName)
-	fmt.Fprintf(w.W, "\r\n")
-
-	encoder := base64.NewEncoder(base64.StdEncoding, w.W)
-	encoder.Write(invoicePDFData)
-	encoder.Close()
-	fmt.Fprintf(w.W, "\r\n")
-
-	// End of multipart message
-	fmt.Fprintf(w.W, "--%s--\r\n", boundary)
-	bw.Flush()
-
-	return sendMail(recipientEmail, buf.Bytes())
+	return sendMailWithAttachment(recipientEmail, subject, htmlBody, invoiceFileName, "application/pdf", invoicePDFData)
 }
 
 // SendPasswordResetEmail sends an email with a password reset link.
@@ -229,6 +191,27 @@ func SendConfirmationEmail(recipientEmail, companyName, confirmationLink string)
 	return sendMail(recipientEmail, message)
 }
 
+// SendScheduledReportEmail sends a scheduled report as an attachment.
+func SendScheduledReportEmail(recipientEmail, companyName, reportTitle, period, fileName, contentType string, reportData []byte) error {
+	// Check if SMTP configuration is loaded
+	if config.SMTP_SERVER == "" || config.SMTP_PORT == "" || config.SMTP_USER == "" || config.SMTP_PASSWORD == "" || config.SMTP_FROM == "" {
+		log.Println("Skipping email sending: SMTP configuration is incomplete.")
+		return fmt.Errorf("SMTP configuration incomplete")
+	}
+
+	subject := fmt.Sprintf("Laporan %s %s (%s)", reportTitle, companyName, period)
+
+	htmlBody := fmt.Sprintf(`
+		<h1>Laporan %s</h1>
+		<p>Halo,</p>
+		<p>Terlampir adalah laporan %s %s untuk periode %s.</p>
+		<p>Laporan ini dikirim otomatis sesuai jadwal yang diatur oleh admin perusahaan Anda.</p>
+		<p>Hormat kami,<br>Tim Go-Face-Auth</p>
+	`, reportTitle, reportTitle, companyName, period)
+
+	return sendMailWithAttachment(recipientEmail, subject, htmlBody, fileName, contentType, reportData)
+}
+
 // GetFrontendBaseURL returns the configured frontend base URL.
 func GetFrontendBaseURL() string {
 	return config.FrontendBaseURL
@@ -239,6 +222,61 @@ func GetFrontendAdminBaseURL() string {
 	return config.FrontendAdminBaseURL
 }
 
+// sendMailWithAttachment sends an HTML email with one attached file. The subject and file name are encoded so
+// that non-ASCII text, such as Indonesian company or division names, survives the trip.
+func sendMailWithAttachment(recipientEmail, subject, htmlBody, fileName, contentType string, data []byte) error {
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+
+	// Write email headers
+	fmt.Fprintf(buf, "From: %s\r\n", config.SMTP_FROM)
+	fmt.Fprintf(buf, "To: %s\r\n", recipientEmail)
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", mw.Boundary())
+	fmt.Fprintf(buf, "\r\n") // End of headers
+
+	// Write HTML part
+	htmlHeader := textproto.MIMEHeader{}
+	htmlHeader.Set("Content-Type", "text/html; charset=\"UTF-8\"")
+	htmlHeader.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := mw.CreatePart(htmlHeader)
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(htmlBody)); err != nil {
+		return err
+	}
+	if err := qp.Close(); err != nil {
+		return err
+	}
+
+	// Write attachment part
+	attachmentHeader := textproto.MIMEHeader{}
+	attachmentHeader.Set("Content-Type", contentType)
+	attachmentHeader.Set("Content-Transfer-Encoding", "base64")
+	attachmentHeader.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	part, err = mw.CreatePart(attachmentHeader)
+	if err != nil {
+		return err
+	}
+	// Wrap the base64 data at 76 characters per line, as mail servers expect
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		fmt.Fprintf(part, "%s\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(part, "%s\r\n", encoded)
+
+	// End of multipart message
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	return sendMail(recipientEmail, buf.Bytes())
+}
+
 // sendMail is a helper function to handle the actual SMTP sending logic.
 func sendMail(recipientEmail string, message []byte) error {
 	auth := smtp.PlainAuth("", config.SMTP_USER, config.SMTP_PASSWORD, config.SMTP_SERVER)
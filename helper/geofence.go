@@ -0,0 +1,374 @@
+package helper
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeoRing is a closed ring of [longitude, latitude] positions, in GeoJSON order.
+type GeoRing [][2]float64
+
+// GeoPolygon is an outer ring followed by any number of holes.
+type GeoPolygon []GeoRing
+
+// GeoMultiPolygon is one or more polygons treated as a single area.
+type GeoMultiPolygon []GeoPolygon
+
+// GeoFeature is a named area read from a GeoJSON feature or a KML placemark.
+type GeoFeature struct {
+	Name     string
+	Geometry GeoMultiPolygon
+}
+
+var ErrNoPolygonsFound = errors.New("no polygon or multipolygon geometry found")
+
+// geoJSONObject covers the GeoJSON object types we accept: geometries, features and feature collections.
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Geometries  []geoJSONObject `json:"geometries"`
+	Features    []geoJSONObject `json:"features"`
+	Properties  map[string]any  `json:"properties"`
+}
+
+// ParseGeoJSONGeometry reads a Polygon or MultiPolygon geometry (or a Feature/FeatureCollection
+// containing them) and merges every polygon into one multipolygon.
+func ParseGeoJSONGeometry(data []byte) (GeoMultiPolygon, error) {
+	features, err := ParseGeoJSONFeatures(data)
+	if err != nil {
+		return nil, err
+	}
+	var merged GeoMultiPolygon
+	for _, feature := range features {
+		merged = append(merged, feature.Geometry...)
+	}
+	return merged, nil
+}
+
+// ParseGeoJSONFeatures reads the polygon features of a GeoJSON document. Features without a
+// polygon geometry are skipped; a bare geometry is returned as a single unnamed feature.
+func ParseGeoJSONFeatures(data []byte) ([]GeoFeature, error) {
+	var object geoJSONObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	var features []GeoFeature
+	var collect func(obj geoJSONObject, name string) error
+	collect = func(obj geoJSONObject, name string) error {
+		switch obj.Type {
+		case "FeatureCollection":
+			for _, feature := range obj.Features {
+				if err := collect(feature, ""); err != nil {
+					return err
+				}
+			}
+		case "Feature":
+			if obj.Geometry == nil {
+				return nil
+			}
+			if featureName, ok := obj.Properties["name"].(string); ok {
+				name = featureName
+			}
+			return collect(*obj.Geometry, name)
+		case "GeometryCollection":
+			var merged GeoMultiPolygon
+			for _, geometry := range obj.Geometries {
+				polygons, err := decodeGeoJSONPolygons(geometry)
+				if err != nil {
+					return err
+				}
+				merged = append(merged, polygons...)
+			}
+			if len(merged) > 0 {
+				features = append(features, GeoFeature{Name: name, Geometry: merged})
+			}
+		default:
+			polygons, err := decodeGeoJSONPolygons(obj)
+			if err != nil {
+				return err
+			}
+			if len(polygons) > 0 {
+				features = append(features, GeoFeature{Name: name, Geometry: polygons})
+			}
+		}
+		return nil
+	}
+
+	if err := collect(object, ""); err != nil {
+		return nil, err
+	}
+	if len(features) == 0 {
+		return nil, ErrNoPolygonsFound
+	}
+	for i := range features {
+		if err := features[i].Geometry.normalize(); err != nil {
+			return nil, err
+		}
+	}
+	return features, nil
+}
+
+func decodeGeoJSONPolygons(obj geoJSONObject) (GeoMultiPolygon, error) {
+	switch obj.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		converted, err := toGeoPolygon(polygon)
+		if err != nil {
+			return nil, err
+		}
+		return GeoMultiPolygon{converted}, nil
+	case "MultiPolygon":
+		var multiPolygon [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &multiPolygon); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		var result GeoMultiPolygon
+		for _, polygon := range multiPolygon {
+			converted, err := toGeoPolygon(polygon)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+	return nil, nil // Points, lines and other geometry types are not areas
+}
+
+func toGeoPolygon(rings [][][]float64) (GeoPolygon, error) {
+	var polygon GeoPolygon
+	for _, ring := range rings {
+		var converted GeoRing
+		for _, position := range ring {
+			if len(position) < 2 {
+				return nil, errors.New("each position needs a longitude and a latitude")
+			}
+			converted = append(converted, [2]float64{position[0], position[1]})
+		}
+		polygon = append(polygon, converted)
+	}
+	return polygon, nil
+}
+
+// kmlDocument matches every Placemark in a KML file regardless of how deeply it is nested in folders.
+type kmlDocument struct {
+	Placemarks []kmlPlacemark `xml:",any"`
+}
+
+type kmlPlacemark struct {
+	XMLName  xml.Name
+	Name     string         `xml:"name"`
+	Polygons []kmlPolygon   `xml:"Polygon"`
+	Multi    []kmlPolygon   `xml:"MultiGeometry>Polygon"`
+	Children []kmlPlacemark `xml:",any"`
+}
+
+type kmlPolygon struct {
+	Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+}
+
+// ParseKMLFeatures reads the polygons of every Placemark in a KML document.
+func ParseKMLFeatures(data []byte) ([]GeoFeature, error) {
+	var document kmlDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid KML: %w", err)
+	}
+
+	var features []GeoFeature
+	var collect func(elements []kmlPlacemark) error
+	collect = func(elements []kmlPlacemark) error {
+		for _, element := range elements {
+			if element.XMLName.Local != "Placemark" {
+				if err := collect(element.Children); err != nil {
+					return err
+				}
+				continue
+			}
+			var geometry GeoMultiPolygon
+			for _, polygon := range append(element.Polygons, element.Multi...) {
+				outer, err := parseKMLCoordinates(polygon.Outer)
+				if err != nil {
+					return err
+				}
+				converted := GeoPolygon{outer}
+				for _, inner := range polygon.Inner {
+					hole, err := parseKMLCoordinates(inner)
+					if err != nil {
+						return err
+					}
+					converted = append(converted, hole)
+				}
+				geometry = append(geometry, converted)
+			}
+			if len(geometry) > 0 {
+				features = append(features, GeoFeature{Name: strings.TrimSpace(element.Name), Geometry: geometry})
+			}
+		}
+		return nil
+	}
+
+	if err := collect(document.Placemarks); err != nil {
+		return nil, err
+	}
+	if len(features) == 0 {
+		return nil, ErrNoPolygonsFound
+	}
+	for i := range features {
+		if err := features[i].Geometry.normalize(); err != nil {
+			return nil, err
+		}
+	}
+	return features, nil
+}
+
+// parseKMLCoordinates reads a KML coordinate list of "lon,lat[,alt]" tuples separated by whitespace.
+func parseKMLCoordinates(text string) (GeoRing, error) {
+	var ring GeoRing
+	for _, tuple := range strings.Fields(text) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid KML coordinate %q", tuple)
+		}
+		lon, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KML longitude %q", parts[0])
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KML latitude %q", parts[1])
+		}
+		ring = append(ring, [2]float64{lon, lat})
+	}
+	return ring, nil
+}
+
+// normalize closes open rings and checks that every ring is a usable area with valid coordinates.
+func (mp GeoMultiPolygon) normalize() error {
+	if len(mp) == 0 {
+		return ErrNoPolygonsFound
+	}
+	for i, polygon := range mp {
+		if len(polygon) == 0 {
+			return errors.New("polygon has no rings")
+		}
+		for j, ring := range polygon {
+			for _, position := range ring {
+				if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+					return fmt.Errorf("coordinate [%v, %v] is out of range; positions must be [longitude, latitude]", position[0], position[1])
+				}
+			}
+			if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+				ring = append(ring, ring[0])
+			}
+			if len(ring) < 4 {
+				return errors.New("each polygon ring needs at least three distinct positions")
+			}
+			mp[i][j] = ring
+		}
+	}
+	return nil
+}
+
+// MarshalGeoJSON encodes the area as a GeoJSON MultiPolygon geometry.
+func (mp GeoMultiPolygon) MarshalGeoJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type        string          `json:"type"`
+		Coordinates GeoMultiPolygon `json:"coordinates"`
+	}{Type: "MultiPolygon", Coordinates: mp})
+}
+
+// Contains reports whether the point lies inside one of the polygons and outside that polygon's holes.
+func (mp GeoMultiPolygon) Contains(latitude, longitude float64) bool {
+	for _, polygon := range mp {
+		if len(polygon) == 0 || !ringContains(polygon[0], latitude, longitude) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, latitude, longitude) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains is an even-odd ray casting test on a closed ring.
+func ringContains(ring GeoRing, latitude, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > latitude) != (yj > latitude) && longitude < (xj-xi)*(latitude-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// BoundingCircle returns the centre of the area's bounding box and the distance in meters from it to the
+// farthest vertex, so polygon locations still have a sensible centre point and radius.
+func (mp GeoMultiPolygon) BoundingCircle() (latitude, longitude, radius float64) {
+	minLon, minLat := math.Inf(1), math.Inf(1)
+	maxLon, maxLat := math.Inf(-1), math.Inf(-1)
+	for _, polygon := range mp {
+		for _, position := range polygon[0] {
+			minLon, maxLon = math.Min(minLon, position[0]), math.Max(maxLon, position[0])
+			minLat, maxLat = math.Min(minLat, position[1]), math.Max(maxLat, position[1])
+		}
+	}
+	latitude, longitude = (minLat+maxLat)/2, (minLon+maxLon)/2
+	for _, polygon := range mp {
+		for _, position := range polygon[0] {
+			radius = math.Max(radius, HaversineDistance(latitude, longitude, position[1], position[0]))
+		}
+	}
+	return latitude, longitude, radius
+}
+
+// DistanceToBoundary returns the distance in meters from the point to the nearest edge of any ring,
+// holes included. Edges are measured on a local flat projection, which is accurate at geofence scale.
+func (mp GeoMultiPolygon) DistanceToBoundary(latitude, longitude float64) float64 {
+	const metersPerDegree = 6371e3 * math.Pi / 180
+	scaleX := metersPerDegree * math.Cos(latitude*math.Pi/180)
+	project := func(position [2]float64) (float64, float64) {
+		return (position[0] - longitude) * scaleX, (position[1] - latitude) * metersPerDegree
+	}
+
+	nearest := math.Inf(1)
+	for _, polygon := range mp {
+		for _, ring := range polygon {
+			for i := 1; i < len(ring); i++ {
+				ax, ay := project(ring[i-1])
+				bx, by := project(ring[i])
+				nearest = math.Min(nearest, distanceToSegment(ax, ay, bx, by))
+			}
+		}
+	}
+	return nearest
+}
+
+// distanceToSegment returns the distance from the origin to the segment a-b.
+func distanceToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if lengthSquared := dx*dx + dy*dy; lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSquared))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"time"
+)
+
+// LocationQRCodeStep is how long each rotating location QR code stays on screen.
+const LocationQRCodeStep = 30 * time.Second
+
+// GenerateLocationQRCode returns the QR code a location screen shows at time t. Codes are derived
+// from the location's secret and the current time step, so the screen needs no network access.
+func GenerateLocationQRCode(secret string, t time.Time) string {
+	return locationQRCodeForStep(secret, t.Unix()/int64(LocationQRCodeStep/time.Second))
+}
+
+// LocationQRCodeExpiresAt returns when the code shown at time t is replaced.
+func LocationQRCodeExpiresAt(t time.Time) time.Time {
+	step := int64(LocationQRCodeStep / time.Second)
+	return time.Unix((t.Unix()/step+1)*step, 0)
+}
+
+// VerifyLocationQRCode checks a scanned code against the location's secret. The previous code is
+// accepted too, so a code scanned just before the screen rotates is not rejected.
+func VerifyLocationQRCode(secret, code string, t time.Time) bool {
+	if secret == "" || code == "" {
+		return false
+	}
+	step := t.Unix() / int64(LocationQRCodeStep/time.Second)
+	for _, s := range []int64{step, step - 1} {
+		if hmac.Equal([]byte(code), []byte(locationQRCodeForStep(secret, s))) {
+			return true
+		}
+	}
+	return false
+}
+
+func locationQRCodeForStep(secret string, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(counter[:])
+	return hex.EncodeToString(mac.Sum(nil))[:20]
+}
+
+// NormalizeBSSID converts a Wi-Fi access point MAC address to lower-case colon notation,
+// e.g. "AA-BB-CC-DD-EE-FF" becomes "aa:bb:cc:dd:ee:ff".
+func NormalizeBSSID(bssid string) (string, error) {
+	hw, err := net.ParseMAC(bssid)
+	if err != nil || len(hw) != 6 {
+		return "", &net.AddrError{Err: "invalid BSSID", Addr: bssid}
+	}
+	return hw.String(), nil
+}
@@ -0,0 +1,344 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats a report can be rendered to.
+const (
+	ReportFormatXLSX = "xlsx"
+	ReportFormatCSV  = "csv"
+	ReportFormatPDF  = "pdf"
+)
+
+// IsValidReportFormat reports whether a report can be rendered to the format.
+func IsValidReportFormat(format string) bool {
+	return format == ReportFormatXLSX || format == ReportFormatCSV || format == ReportFormatPDF
+}
+
+// Report is a format-independent export: one or more tables with a title. Services build reports and
+// handlers render them in the format the client asked for.
+type Report struct {
+	Title    string
+	Subtitle string // e.g., the period covered
+	FileName string // Without extension
+	Tables   []ReportTable
+}
+
+// ReportTable is one table of a report: a sheet in Excel, a section in CSV and PDF.
+type ReportTable struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+	Totals  []interface{} // Optional closing row, highlighted like the header
+}
+
+// ReportDateRangeSuffix describes an optional date range for file names, e.g. "_2024-01-01_to_2024-01-31".
+func ReportDateRangeSuffix(startDate, endDate *time.Time) string {
+	if startDate != nil && endDate != nil {
+		return fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	} else if startDate != nil {
+		return fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
+	} else if endDate != nil {
+		return fmt.Sprintf("_until_%s", endDate.Format("2006-01-02"))
+	}
+	return ""
+}
+
+// ReportPeriod describes an optional date range for report subtitles.
+func ReportPeriod(startDate, endDate *time.Time) string {
+	if startDate != nil && endDate != nil {
+		return fmt.Sprintf("Period: %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	} else if startDate != nil {
+		return fmt.Sprintf("Period: from %s", startDate.Format("2006-01-02"))
+	} else if endDate != nil {
+		return fmt.Sprintf("Period: until %s", endDate.Format("2006-01-02"))
+	}
+	return "Period: all dates"
+}
+
+// Render renders the report and returns its content, content type and file name.
+func (r *Report) Render(format string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case ReportFormatXLSX:
+		err = r.renderXLSX(&buf)
+	case ReportFormatCSV:
+		err = r.renderCSV(&buf)
+	case ReportFormatPDF:
+		err = r.renderPDF(&buf)
+	default:
+		return nil, "", "", fmt.Errorf("unsupported report format %q", format)
+	}
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return buf.Bytes(), ReportContentType(format), r.FileName + "." + format, nil
+}
+
+// renderXLSX writes one sheet per table with the light blue header style used across exports.
+func (r *Report) renderXLSX(buf *bytes.Buffer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	style, err := f.NewStyle(&excelize.Style{
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
+		Font:      &excelize.Font{Bold: true},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	usedNames := make(map[string]bool)
+	for i, table := range r.Tables {
+		sheetName := uniqueSheetName(table.Name, usedNames)
+		if i == 0 {
+			f.SetSheetName("Sheet1", sheetName)
+		} else {
+			f.NewSheet(sheetName)
+		}
+
+		writeRow := func(row int, values []interface{}, styled bool) {
+			for col, value := range values {
+				cell, _ := excelize.CoordinatesToCellName(col+1, row)
+				f.SetCellValue(sheetName, cell, value)
+			}
+			if styled && len(values) > 0 {
+				lastCell, _ := excelize.CoordinatesToCellName(len(values), row)
+				f.SetCellStyle(sheetName, fmt.Sprintf("A%d", row), lastCell, style)
+			}
+		}
+
+		headers := make([]interface{}, len(table.Headers))
+		for col, header := range table.Headers {
+			headers[col] = header
+		}
+		writeRow(1, headers, true)
+		for j, values := range table.Rows {
+			writeRow(j+2, values, false)
+		}
+		if table.Totals != nil {
+			writeRow(len(table.Rows)+2, table.Totals, true)
+		}
+	}
+
+	return f.Write(buf)
+}
+
+// renderCSV writes the tables one after another. Reports with several tables separate them with an empty
+// line and start each with a line holding its name, so a single-table report stays a plain CSV file.
+func (r *Report) renderCSV(buf *bytes.Buffer) error {
+	w := csv.NewWriter(buf)
+	for i, table := range r.Tables {
+		if len(r.Tables) > 1 {
+			if i > 0 {
+				w.Write([]string{})
+			}
+			w.Write([]string{table.Name})
+		}
+		w.Write(table.Headers)
+		for _, values := range table.Rows {
+			w.Write(formatReportRow(values))
+		}
+		if table.Totals != nil {
+			w.Write(formatReportRow(table.Totals))
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// renderPDF prints the tables on A4 pages, landscape when a table is too wide for portrait. Column widths
+// follow the content and the header is repeated on every page.
+func (r *Report) renderPDF(buf *bytes.Buffer) error {
+	const (
+		pageShort  = 210.0 // A4 in millimetres
+		pageLong   = 297.0
+		margin     = 10.0
+		rowHeight  = 6.0
+		fontSize   = 8.0
+		cellMargin = 2.0
+	)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // Core fonts only cover cp1252
+	generatedAt := time.Now().Format("02 January 2006 15:04")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-margin)
+		pdf.SetFont("Arial", "I", 7)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s - generated %s - page %d", r.Title, generatedAt, pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	for _, table := range r.Tables {
+		headers := table.Headers
+		rows := make([][]string, 0, len(table.Rows)+1)
+		for _, values := range table.Rows {
+			rows = append(rows, formatReportRow(values))
+		}
+		var totals []string
+		if table.Totals != nil {
+			totals = formatReportRow(table.Totals)
+		}
+
+		// Size columns to their widest value, then scale them to the page.
+		pdf.SetFont("Arial", "B", fontSize)
+		widths := make([]float64, len(headers))
+		for col, header := range headers {
+			widths[col] = pdf.GetStringWidth(tr(header)) + 2*cellMargin
+		}
+		pdf.SetFont("Arial", "", fontSize)
+		for _, values := range append(rows, totals) {
+			for col := 0; col < len(values) && col < len(widths); col++ {
+				if w := pdf.GetStringWidth(tr(values[col])) + 2*cellMargin; w > widths[col] {
+					widths[col] = w
+				}
+			}
+		}
+		totalWidth := 0.0
+		for _, w := range widths {
+			totalWidth += w
+		}
+		orientation, pageWidth, pageHeight := "P", pageShort, pageLong
+		if totalWidth > pageWidth-2*margin {
+			orientation, pageWidth, pageHeight = "L", pageLong, pageShort
+		}
+		if available := pageWidth - 2*margin; totalWidth > available {
+			for col := range widths {
+				widths[col] *= available / totalWidth
+			}
+		}
+
+		printHeader := func() {
+			pdf.SetFont("Arial", "B", fontSize)
+			pdf.SetFillColor(221, 235, 247) // #DDEBF7, as in the Excel exports
+			for col, header := range headers {
+				pdf.CellFormat(widths[col], rowHeight, fitReportCell(pdf, tr(header), widths[col]-2*cellMargin), "1", 0, "C", true, 0, "")
+			}
+			pdf.Ln(rowHeight)
+			pdf.SetFont("Arial", "", fontSize)
+		}
+		printRow := func(values []string, highlighted bool) {
+			if pdf.GetY()+rowHeight > pageHeight-2*margin {
+				pdf.AddPageFormat(orientation, pdf.GetPageSizeStr("A4"))
+				printHeader()
+			}
+			if highlighted {
+				pdf.SetFont("Arial", "B", fontSize)
+			}
+			for col := range headers {
+				value := ""
+				if col < len(values) {
+					value = values[col]
+				}
+				pdf.CellFormat(widths[col], rowHeight, fitReportCell(pdf, tr(value), widths[col]-2*cellMargin), "1", 0, "L", highlighted, 0, "")
+			}
+			pdf.Ln(rowHeight)
+			if highlighted {
+				pdf.SetFont("Arial", "", fontSize)
+			}
+		}
+
+		pdf.AddPageFormat(orientation, pdf.GetPageSizeStr("A4"))
+		pdf.SetFont("Arial", "B", 14)
+		pdf.CellFormat(0, 8, tr(r.Title), "", 1, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 10)
+		subtitle := r.Subtitle
+		if len(r.Tables) > 1 {
+			subtitle = strings.TrimPrefix(subtitle+" - "+table.Name, " - ")
+		}
+		if subtitle != "" {
+			pdf.CellFormat(0, 6, tr(subtitle), "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(2)
+
+		printHeader()
+		if len(rows) == 0 && totals == nil {
+			pdf.SetFont("Arial", "I", fontSize)
+			pdf.CellFormat(0, rowHeight, "No records.", "", 1, "L", false, 0, "")
+		}
+		for _, values := range rows {
+			printRow(values, false)
+		}
+		if totals != nil {
+			pdf.SetFillColor(221, 235, 247)
+			printRow(totals, true)
+		}
+	}
+
+	return pdf.Output(buf)
+}
+
+// formatReportRow turns the values of a row into text for CSV and PDF.
+func formatReportRow(values []interface{}) []string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			formatted[i] = ""
+		case string:
+			formatted[i] = v
+		case float64:
+			formatted[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			formatted[i] = v.Format("2006-01-02 15:04:05")
+		default:
+			formatted[i] = fmt.Sprint(v)
+		}
+	}
+	return formatted
+}
+
+// fitReportCell shortens text with an ellipsis until it fits the given width.
+func fitReportCell(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// uniqueSheetName turns a table name into a valid, unique Excel sheet name.
+func uniqueSheetName(name string, used map[string]bool) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	cleaned = strings.TrimSpace(cleaned)
+	if cleaned == "" {
+		cleaned = "Sheet"
+	}
+	if runes := []rune(cleaned); len(runes) > 31 {
+		cleaned = string(runes[:31])
+	}
+
+	candidate := cleaned
+	for i := 2; used[candidate]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		base := []rune(cleaned)
+		if len(base)+len(suffix) > 31 {
+			base = base[:31-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+	used[candidate] = true
+	return candidate
+}
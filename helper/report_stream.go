@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReportStreamWriter writes the rows of a single-table report as they are produced, so that large exports do not
+// hold the whole report in memory. Close must be called to finish the output.
+type ReportStreamWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// IsStreamableReportFormat reports whether a report can be streamed in the format. PDF reports size their
+// columns to the content and are always rendered in memory.
+func IsStreamableReportFormat(format string) bool {
+	return format == ReportFormatXLSX || format == ReportFormatCSV
+}
+
+// ReportContentType returns the content type of a report format.
+func ReportContentType(format string) string {
+	switch format {
+	case ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ReportFormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// NewReportStreamWriter starts a streamed report in w with the table's header row.
+func NewReportStreamWriter(w io.Writer, format string, table ReportTable) (ReportStreamWriter, error) {
+	switch format {
+	case ReportFormatXLSX:
+		return newXLSXStreamWriter(w, table)
+	case ReportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(table.Headers); err != nil {
+			return nil, err
+		}
+		return &csvStreamWriter{w: cw}, nil
+	}
+	return nil, fmt.Errorf("report format %q cannot be streamed", format)
+}
+
+// csvStreamWriter writes rows straight through a CSV writer, which flushes as its buffer fills.
+type csvStreamWriter struct {
+	w *csv.Writer
+}
+
+func (s *csvStreamWriter) WriteRow(values []interface{}) error {
+	return s.w.Write(formatReportRow(values))
+}
+
+func (s *csvStreamWriter) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// xlsxStreamWriter writes rows through excelize's StreamWriter, which keeps only a small buffer in memory and
+// spills the rest of the sheet to a temporary file until the workbook is written out on Close.
+type xlsxStreamWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXStreamWriter(w io.Writer, table ReportTable) (*xlsxStreamWriter, error) {
+	f := excelize.NewFile()
+	sheetName := uniqueSheetName(table.Name, map[string]bool{})
+	f.SetSheetName("Sheet1", sheetName)
+
+	style, err := f.NewStyle(&excelize.Style{
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
+		Font:      &excelize.Font{Bold: true},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	stream, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create stream writer: %w", err)
+	}
+
+	headers := make([]interface{}, len(table.Headers))
+	for col, header := range table.Headers {
+		headers[col] = excelize.Cell{StyleID: style, Value: header}
+	}
+	if err := stream.SetRow("A1", headers); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &xlsxStreamWriter{out: w, file: f, stream: stream, row: 1}, nil
+}
+
+func (s *xlsxStreamWriter) WriteRow(values []interface{}) error {
+	s.row++
+	cell, err := excelize.CoordinatesToCellName(1, s.row)
+	if err != nil {
+		return err
+	}
+	return s.stream.SetRow(cell, values)
+}
+
+func (s *xlsxStreamWriter) Close() error {
+	defer s.file.Close()
+	if err := s.stream.Flush(); err != nil {
+		return err
+	}
+	return s.file.Write(s.out)
+}
@@ -152,13 +152,34 @@ func main() {
 	faceImageRepo := repository.NewFaceImageRepository(database.DB)
 	attendanceLocationRepo := repository.NewAttendanceLocationRepository(database.DB)
 	divisionRepo := repository.NewDivisionRepository(database.DB)
+	overtimeRequestRepo := repository.NewOvertimeRequestRepository(database.DB)
+	overtimePolicyRepo := repository.NewOvertimePolicyRepository(database.DB)
+	publicHolidayRepo := repository.NewPublicHolidayRepository(database.DB)
+	attendancePunchRepo := repository.NewAttendancePunchRepository(database.DB)
+	remoteWorkRepo := repository.NewRemoteWorkRepository(database.DB)
+	absenteeRepo := repository.NewAbsenteeRepository(database.DB)
+	attendancePolicyRepo := repository.NewAttendancePolicyRepository(database.DB)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(database.DB)
+	attendanceAnomalyRepo := repository.NewAttendanceAnomalyRepository(database.DB)
+	exportJobRepo := repository.NewExportJobRepository(database.DB)
+	adminCompanyRepo := repository.NewAdminCompanyRepository(database.DB)
+	reportSubscriptionRepo := repository.NewReportSubscriptionRepository(database.DB)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepository(database.DB)
+	leaveTypeRepo := repository.NewLeaveTypeRepository(database.DB)
 	pythonClient := services.NewPythonClient()
 
 	// Create an instance of the attendance service for the cron job
-	cronAttendanceService := services.NewAttendanceService(employeeRepo, companyRepo, attendanceRepo, faceImageRepo, attendanceLocationRepo, leaveRequestRepo, shiftRepo, divisionRepo, pythonClient)
+	cronAttendanceService := services.NewAttendanceService(employeeRepo, companyRepo, attendanceRepo, faceImageRepo, attendanceLocationRepo, leaveRequestRepo, shiftRepo, divisionRepo, overtimeRequestRepo, overtimePolicyRepo, publicHolidayRepo, attendancePunchRepo, remoteWorkRepo, absenteeRepo, attendancePolicyRepo, leaveTypeRepo, pythonClient)
+	cronAttendanceAnomalyService := services.NewAttendanceAnomalyService(attendanceAnomalyRepo, attendancePunchRepo, companyRepo)
+	cronExportJobService := services.NewExportJobService(exportJobRepo, cronAttendanceService)
+	cronLeaveRequestService := services.NewLeaveRequestService(employeeRepo, leaveRequestRepo, adminCompanyRepo, companyRepo, leaveBalanceRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo)
+	cronTimesheetService := services.NewTimesheetService(companyRepo, employeeRepo, attendanceRepo, leaveRequestRepo, divisionRepo, shiftRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo, attendancePolicyRepo)
+	cronReportSubscriptionService := services.NewReportSubscriptionService(reportSubscriptionRepo, companyRepo, cronAttendanceService, cronLeaveRequestService, cronTimesheetService)
+	cronLeaveBalanceService := services.NewLeaveBalanceService(leaveBalanceRepo, employeeRepo, companyRepo, leaveRequestRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo)
 
-	// Schedule the MarkDailyAbsentees function to run at 03:00, 09:00, 15:00, 21:00 UTC
-	_, err := c.AddFunc("0 3,9,15,21 * * *", func() {
+	// Check every 15 minutes for companies whose day is due for absentee processing; each company is
+	// processed in its own timezone once its shifts end plus the post-shift close time of its attendance policy
+	_, err := c.AddFunc("*/15 * * * *", func() {
 		log.Println("Running scheduled MarkDailyAbsentees...")
 		if err := cronAttendanceService.MarkDailyAbsentees(); err != nil {
 			log.Printf("Error running MarkDailyAbsentees: %v", err)
@@ -168,6 +189,115 @@ func main() {
 		log.Fatalf("Failed to schedule MarkDailyAbsentees: %v", err)
 	}
 
+	// Close overtime sessions left open past the company's maximum session length, every 15 minutes
+	_, err = c.AddFunc("*/15 * * * *", func() {
+		closed, err := cronAttendanceService.AutoCloseOvertimeSessions()
+		if err != nil {
+			log.Printf("Error auto-closing overtime sessions: %v", err)
+			return
+		}
+		for _, session := range closed {
+			payload := gin.H{
+				"attendance_id":    session.ID,
+				"employee_id":      session.EmployeeID,
+				"employee_name":    session.Employee.Name,
+				"check_in_time":    session.CheckInTime,
+				"check_out_time":   session.CheckOutTime,
+				"overtime_minutes": session.OvertimeMinutes,
+			}
+			hub.SendMessageToEmployee(session.Employee.CompanyID, session.EmployeeID, "overtime_session_auto_closed", payload)
+			hub.SendMessageToCompanyAdmins(session.Employee.CompanyID, "overtime_session_auto_closed", payload)
+		}
+		if len(closed) > 0 {
+			log.Printf("Auto-closed %d overtime sessions", len(closed))
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule overtime auto-close: %v", err)
+	}
+
+	// Purge idempotency keys whose replay window has passed, every hour
+	_, err = c.AddFunc("0 * * * *", func() {
+		deleted, err := idempotencyKeyRepo.DeleteExpiredIdempotencyKeys(time.Now())
+		if err != nil {
+			log.Printf("Error purging expired idempotency keys: %v", err)
+			return
+		}
+		if deleted > 0 {
+			log.Printf("Purged %d expired idempotency keys", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule idempotency key purge: %v", err)
+	}
+
+	// Scan for buddy punching and other attendance anomalies once a day and tell admins about new findings
+	_, err = c.AddFunc("0 2 * * *", func() {
+		anomalies, err := cronAttendanceAnomalyService.DetectAnomalies()
+		if err != nil {
+			log.Printf("Error detecting attendance anomalies: %v", err)
+			return
+		}
+		detected := make(map[int]int)
+		for _, anomaly := range anomalies {
+			detected[anomaly.CompanyID]++
+		}
+		for companyID, count := range detected {
+			hub.SendMessageToCompanyAdmins(companyID, "attendance_anomalies_detected", gin.H{"detected": count})
+		}
+		if len(anomalies) > 0 {
+			log.Printf("Detected %d new attendance anomalies", len(anomalies))
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule attendance anomaly detection: %v", err)
+	}
+
+	// Delete the files of expired background exports and fail exports lost to a restart, every hour
+	_, err = c.AddFunc("30 * * * *", func() {
+		cleaned, err := cronExportJobService.CleanupExportJobs()
+		if err != nil {
+			log.Printf("Error cleaning up export jobs: %v", err)
+			return
+		}
+		if cleaned > 0 {
+			log.Printf("Cleaned up %d export jobs", cleaned)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule export job cleanup: %v", err)
+	}
+
+	// Email scheduled reports that are due, every 5 minutes; send times are in each company's timezone
+	_, err = c.AddFunc("*/5 * * * *", func() {
+		delivered, err := cronReportSubscriptionService.DeliverDueReports()
+		if err != nil {
+			log.Printf("Error delivering scheduled reports: %v", err)
+			return
+		}
+		if delivered > 0 {
+			log.Printf("Delivered %d scheduled reports", delivered)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule report delivery: %v", err)
+	}
+
+	// Post due leave accruals, carry-overs and expiries once a day; balances read in between include them unposted
+	_, err = c.AddFunc("15 0 * * *", func() {
+		posted, err := cronLeaveBalanceService.AccrueLeaveBalances()
+		if err != nil {
+			log.Printf("Error accruing leave balances: %v", err)
+			return
+		}
+		if posted > 0 {
+			log.Printf("Posted %d leave ledger entries", posted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule leave accrual: %v", err)
+	}
+
 	// Start the cron scheduler in a goroutine
 	c.Start()
 	log.Println("Cron scheduler started.")
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make a POST safe to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotencyKeyTTL is how long the outcome of a request is replayed for duplicate keys.
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 100
+)
+
+// idempotencyResponseWriter keeps a copy of the response body so that it can be stored for replays.
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response when a request is retried with the same
+// Idempotency-Key header within IdempotencyKeyTTL. Keys are scoped to the caller's company and the route.
+// Requests without the header are processed as before. Server errors are not stored, so they can be retried.
+func IdempotencyMiddleware(idempotencyKeyRepo repository.IdempotencyKeyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			helper.SendError(c, http.StatusBadRequest, "Idempotency key must be at most 100 characters.")
+			c.Abort()
+			return
+		}
+
+		companyID := 0
+		if compID, exists := c.Get("companyID"); exists {
+			if compIDFloat, ok := compID.(float64); ok {
+				companyID = int(compIDFloat)
+			}
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "Could not read request body.")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			CompanyID:   companyID,
+			Endpoint:    c.FullPath(),
+			Key:         key,
+			RequestHash: hex.EncodeToString(sum[:]),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(IdempotencyKeyTTL),
+		}
+		if err := idempotencyKeyRepo.CreateIdempotencyKey(record); err != nil {
+			existing, getErr := idempotencyKeyRepo.GetIdempotencyKey(companyID, record.Endpoint, key)
+			if getErr != nil || existing == nil {
+				helper.SendError(c, http.StatusInternalServerError, "Could not process idempotency key.")
+				c.Abort()
+				return
+			}
+			if !existing.ExpiresAt.After(now) {
+				// The replay window has passed, so the key starts over.
+				if idempotencyKeyRepo.DeleteIdempotencyKey(existing.ID) != nil || idempotencyKeyRepo.CreateIdempotencyKey(record) != nil {
+					helper.SendError(c, http.StatusConflict, "A request with this idempotency key is already being processed.")
+					c.Abort()
+					return
+				}
+			} else {
+				replayIdempotentResponse(c, existing, record.RequestHash)
+				return
+			}
+		}
+
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		defer func() {
+			if r := recover(); r != nil {
+				idempotencyKeyRepo.DeleteIdempotencyKey(record.ID)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			if err := idempotencyKeyRepo.DeleteIdempotencyKey(record.ID); err != nil {
+				log.Printf("Error releasing idempotency key %q after server error: %v", key, err)
+			}
+			return
+		}
+		record.StatusCode = writer.Status()
+		record.Response = writer.body.String()
+		if err := idempotencyKeyRepo.UpdateIdempotencyKey(record); err != nil {
+			log.Printf("Error storing response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a duplicate request from the stored outcome of the original one.
+func replayIdempotentResponse(c *gin.Context, existing *models.IdempotencyKey, requestHash string) {
+	if existing.RequestHash != requestHash {
+		helper.SendError(c, http.StatusUnprocessableEntity, "Idempotency key was already used for a different request.")
+		c.Abort()
+		return
+	}
+	if existing.StatusCode == 0 {
+		helper.SendError(c, http.StatusConflict, "A request with this idempotency key is already being processed.")
+		c.Abort()
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.Response))
+	c.Abort()
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// What started an absentee run.
+const (
+	AbsenteeRunTriggerSchedule = "schedule"
+	AbsenteeRunTriggerBackfill = "backfill"
+)
+
+// AbsenteeRun records one pass of absentee processing over a company's day.
+type AbsenteeRun struct {
+	gorm.Model
+	CompanyID  int            `json:"company_id" gorm:"not null;index:idx_absentee_run_company_date"`
+	Company    CompaniesTable `json:"company" gorm:"foreignKey:CompanyID"`
+	Date       time.Time      `json:"date" gorm:"type:date;not null;index:idx_absentee_run_company_date"`
+	Trigger    string         `json:"trigger" gorm:"type:varchar(20);not null"` // "schedule" or "backfill"
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Created    int            `json:"created"`
+	Updated    int            `json:"updated"`
+	Closed     int            `json:"closed"`
+	Pending    int            `json:"pending"`
+	Failed     int            `json:"failed"`
+	Error      string         `json:"error,omitempty" gorm:"type:text"`
+}
@@ -10,9 +10,16 @@ type AttendancesTable struct {
 	CheckInTime       time.Time       `json:"check_in_time"`
 	CheckOutTime      *time.Time      `json:"check_out_time"` // Use pointer for nullable DATETIME
 	OvertimeMinutes   int             `json:"overtime_minutes"`
-	Status            string          `json:"status"`
+	ApprovedOvertimeMinutes int       `json:"approved_overtime_minutes"` // Overtime minutes capped at the approved request
+	OvertimeRequestID *uint           `json:"overtime_request_id"`       // Approved overtime request this session was checked in against
+	IsOvertimeUnapproved bool         `json:"is_overtime_unapproved"`    // Overtime session started without an approved request
+	IsOvertimeIncomplete bool         `json:"is_overtime_incomplete"`    // Overtime session auto-closed because the employee never checked out
+	Status            AttendanceStatus `gorm:"type:varchar(20);not null;index" json:"status"` // See attendance_status.go for the transitions
 	IsCorrection      bool            `json:"is_correction"`
 	Notes             string          `json:"notes"`
+	LocationFlags     string          `json:"location_flags"` // Comma-separated location checks that need admin review, e.g. "impossible_travel"
+	CheckInProof      string          `json:"check_in_proof"` // How the employee proved they were on site: "gps", "wifi" or "qr"
+	WorkMode          string          `gorm:"type:varchar(10);default:'onsite'" json:"work_mode"` // "onsite" or "remote"
 	CorrectedByAdminID *uint           `json:"corrected_by_admin_id"` // Nullable admin ID
 	CorrectedByAdmin  AdminCompaniesTable `gorm:"foreignKey:CorrectedByAdminID" json:"corrected_by_admin"`
 	CreatedAt         time.Time       `json:"created_at"`
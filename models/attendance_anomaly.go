@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of attendance anomaly the detection job reports.
+const (
+	AnomalyKindBuddyPunching        = "buddy_punching"
+	AnomalyKindIdenticalCoordinates = "identical_coordinates"
+	AnomalyKindBorderlineFaceScore  = "borderline_face_score"
+	AnomalyKindNewDevice            = "new_device"
+	AnomalyKindPatternChange        = "pattern_change"
+)
+
+// Review states of an attendance anomaly.
+const (
+	AnomalyStatusOpen      = "open"
+	AnomalyStatusConfirmed = "confirmed"
+	AnomalyStatusDismissed = "dismissed"
+)
+
+// AttendanceAnomaly is a suspicious attendance pattern found by the anomaly detection job, queued for an admin
+// to confirm or dismiss. The fingerprint identifies what was found, so repeated scans do not report it twice.
+type AttendanceAnomaly struct {
+	gorm.Model
+	CompanyID         int             `json:"company_id" gorm:"not null;index;uniqueIndex:idx_attendance_anomaly_fingerprint"`
+	EmployeeID        int             `json:"employee_id" gorm:"not null;index"`
+	Employee          EmployeesTable  `json:"employee" gorm:"foreignKey:EmployeeID"`
+	RelatedEmployeeID *int            `json:"related_employee_id,omitempty"` // The other employee of a buddy-punching pair
+	RelatedEmployee   *EmployeesTable `json:"related_employee,omitempty" gorm:"foreignKey:RelatedEmployeeID"`
+	Kind              string          `json:"kind" gorm:"type:varchar(30);not null;index"`
+	Summary           string          `json:"summary" gorm:"type:text"`
+	PunchIDs          []uint          `json:"punch_ids" gorm:"type:json;serializer:json"` // Punches that make up the evidence
+	Fingerprint       string          `json:"-" gorm:"type:varchar(191);not null;uniqueIndex:idx_attendance_anomaly_fingerprint"`
+	Status            string          `json:"status" gorm:"type:varchar(20);default:'open';index"` // "open", "confirmed" or "dismissed"
+	ReviewedBy        *uint           `json:"reviewed_by"`                                         // Admin ID who reviewed it
+	ReviewedAt        *time.Time      `json:"reviewed_at"`
+	ReviewNotes       string          `json:"review_notes,omitempty"`
+}
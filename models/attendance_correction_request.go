@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceCorrectionRequest is an employee's request to fix one of their own attendance records.
+// Approved requests are applied through the admin correction flow.
+type AttendanceCorrectionRequest struct {
+	gorm.Model
+	EmployeeID          uint           `json:"employee_id" gorm:"not null;index"`
+	Employee            EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	CorrectionType      string         `json:"correction_type" gorm:"type:varchar(20);not null"` // "check_in" or "check_out"
+	ProposedTime        time.Time      `json:"proposed_time" gorm:"not null"`
+	Reason              string         `json:"reason" gorm:"type:text;not null"`
+	AttachmentPath      string         `json:"attachment_path,omitempty"`
+	Status              string         `json:"status" gorm:"type:varchar(50);default:'pending'"` // e.g., "pending", "approved", "rejected", "cancelled"
+	ReviewedBy          *uint          `json:"reviewed_by"`                                      // Admin ID who reviewed it
+	ReviewedAt          *time.Time     `json:"reviewed_at"`
+	ReviewNotes         string         `json:"review_notes,omitempty"`
+	AppliedAttendanceID *int           `json:"applied_attendance_id,omitempty"` // Attendance record created or updated on approval
+}
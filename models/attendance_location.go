@@ -1,6 +1,23 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// Attendance location shapes.
+const (
+	LocationShapeCircle  = "circle"
+	LocationShapePolygon = "polygon"
+)
+
+// Proofs an employee can give that they are at an attendance location.
+const (
+	LocationProofGPS  = "gps"
+	LocationProofWiFi = "wifi"
+	LocationProofQR   = "qr"
+)
 
 type AttendanceLocation struct {
 	gorm.Model
@@ -9,4 +26,22 @@ type AttendanceLocation struct {
 	Latitude  float64 `gorm:"not null" json:"latitude"`
 	Longitude float64 `gorm:"not null" json:"longitude"`
 	Radius    uint   `gorm:"not null" json:"radius"`
+	Shape     string          `gorm:"type:varchar(20);default:'circle'" json:"shape"` // "circle" uses the centre and radius; "polygon" uses Geometry
+	Geometry  json.RawMessage `gorm:"type:json" json:"geometry,omitempty"`            // GeoJSON MultiPolygon, holes allowed
+	AcceptedProofs []string `gorm:"type:json;serializer:json" json:"accepted_proofs"` // Any of "gps", "wifi", "qr"; defaults to GPS only
+	WifiBSSIDs     []string `gorm:"type:json;serializer:json" json:"wifi_bssids"`     // Access points that count as being on site
+	QRSecret       string   `gorm:"type:varchar(64)" json:"-"`                        // Seeds the rotating QR code shown at the location
+}
+
+// AcceptsProof reports whether the location allows check-ins with the given proof.
+func (l *AttendanceLocation) AcceptsProof(proof string) bool {
+	if len(l.AcceptedProofs) == 0 {
+		return proof == LocationProofGPS
+	}
+	for _, accepted := range l.AcceptedProofs {
+		if accepted == proof {
+			return true
+		}
+	}
+	return false
 }
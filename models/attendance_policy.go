@@ -0,0 +1,27 @@
+package models
+
+import "gorm.io/gorm"
+
+// Attendance time rounding modes.
+const (
+	AttendanceRoundingNearest = "nearest"
+	AttendanceRoundingUp      = "up"
+	AttendanceRoundingDown    = "down"
+)
+
+// AttendancePolicy holds attendance rules for a company, or overrides them for one of its divisions or shifts.
+// The company row has neither DivisionID nor ShiftID. A nil rule is inherited: a shift row takes precedence over
+// a division row, which takes precedence over the company row and the built-in defaults.
+type AttendancePolicy struct {
+	gorm.Model
+	CompanyID              int     `json:"company_id" gorm:"not null;index"`
+	DivisionID             *uint   `json:"division_id" gorm:"index"`
+	ShiftID                *int    `json:"shift_id" gorm:"index"`
+	EarlyCheckInMinutes    *int    `json:"early_check_in_minutes"`                // How long before shift start check-in opens, the shift's grace period by default
+	LateThresholdMinutes   *int    `json:"late_threshold_minutes"`                // Minutes after shift start before a check-in is late
+	CloseAfterShiftMinutes *int    `json:"close_after_shift_minutes"`             // Minutes after shift end before missing employees are marked absent
+	MinWorkedMinutes       *int    `json:"min_worked_minutes"`                    // Shorter days are closed as "incomplete" instead of "present"
+	RoundingMinutes        *int    `json:"rounding_minutes"`                      // Interval check-in and check-out times are rounded to, 0 for none
+	RoundingMode           *string `json:"rounding_mode" gorm:"type:varchar(10)"` // "nearest", "up" or "down"
+	RequireFaceOnCheckOut  *bool   `json:"require_face_on_check_out"`
+}
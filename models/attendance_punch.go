@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendancePunch is the location evidence a device submitted with a check-in or check-out attempt.
+// Rejected attempts are kept as well so admins can see who is submitting mocked or inaccurate locations.
+type AttendancePunch struct {
+	gorm.Model
+	EmployeeID      int            `json:"employee_id" gorm:"not null;index"`
+	Employee        EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	AttendanceID    *int           `json:"attendance_id" gorm:"index"`   // Nil when the attempt was rejected
+	Kind            string         `json:"kind" gorm:"type:varchar(20)"` // e.g., "regular", "overtime_in", "overtime_out"
+	PunchedAt       time.Time      `json:"punched_at" gorm:"index"`
+	DeviceID        string         `json:"device_id" gorm:"type:varchar(100);index"` // App installation or "kiosk-<id>"; empty for older clients
+	Latitude        float64        `json:"latitude"`
+	Longitude       float64        `json:"longitude"`
+	Accuracy        *float64       `json:"accuracy"` // Reported horizontal accuracy in meters
+	Provider        string         `json:"provider" gorm:"type:varchar(50)"`
+	IsMockLocation  bool           `json:"is_mock_location"`
+	DeviceTime      *time.Time     `json:"device_time"`
+	WifiBSSIDs      []string       `json:"wifi_bssids" gorm:"type:json;serializer:json"` // Access points the device could see
+	LocationID      *uint          `json:"location_id"`                                  // Attendance location the punch was matched to
+	Proof           string         `json:"proof" gorm:"type:varchar(10)"`                // e.g., "gps", "wifi", "qr"
+	Result          string         `json:"result" gorm:"type:varchar(20)"`               // e.g., "accepted", "rejected"
+	RejectionReason string         `json:"rejection_reason,omitempty"`
+	Flags           string         `json:"flags"`          // Comma-separated, e.g., "impossible_travel,device_clock_skew"
+	FaceDistance    *float64       `json:"face_distance"`  // Distance between the captured and registered face; nil when no face was checked
+	FaceThreshold   *float64       `json:"face_threshold"` // Largest distance the recognition server accepted
+}
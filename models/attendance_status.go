@@ -0,0 +1,203 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AttendanceStatus is the state of an attendance record. Records only move between states through the
+// transitions in attendanceTransitions, which the attendance repository enforces on every write.
+type AttendanceStatus string
+
+const (
+	AttendanceStatusOnTime      AttendanceStatus = "on_time"      // Checked in within the grace period
+	AttendanceStatusLate        AttendanceStatus = "late"         // Checked in after the grace period
+	AttendanceStatusPresent     AttendanceStatus = "present"      // Checked out
+	AttendanceStatusCorrected   AttendanceStatus = "corrected"    // Entered or completed by an admin
+	AttendanceStatusIncomplete  AttendanceStatus = "incomplete"   // Closed automatically without a check-out
+	AttendanceStatusOvertimeIn  AttendanceStatus = "overtime_in"  // Overtime session in progress
+	AttendanceStatusOvertimeOut AttendanceStatus = "overtime_out" // Overtime session finished
+	AttendanceStatusAbsent      AttendanceStatus = "absent"
+	AttendanceStatusOnLeave     AttendanceStatus = "on_leave"
+	AttendanceStatusOnSick      AttendanceStatus = "on_sick"
+)
+
+// AttendanceStatuses lists every valid status, in the order used by the database constraint.
+var AttendanceStatuses = []AttendanceStatus{
+	AttendanceStatusOnTime,
+	AttendanceStatusLate,
+	AttendanceStatusPresent,
+	AttendanceStatusCorrected,
+	AttendanceStatusIncomplete,
+	AttendanceStatusOvertimeIn,
+	AttendanceStatusOvertimeOut,
+	AttendanceStatusAbsent,
+	AttendanceStatusOnLeave,
+	AttendanceStatusOnSick,
+}
+
+// LegacyAttendanceStatuses maps statuses written before the state machine existed to their current value.
+var LegacyAttendanceStatuses = map[string]AttendanceStatus{
+	"present (corrected)": AttendanceStatusCorrected,
+}
+
+// OvertimeAttendanceStatuses are the statuses of overtime sessions.
+var OvertimeAttendanceStatuses = []AttendanceStatus{AttendanceStatusOvertimeIn, AttendanceStatusOvertimeOut}
+
+// OpenAttendanceStatuses are the statuses of regular records that are still waiting for a check-out.
+var OpenAttendanceStatuses = []AttendanceStatus{AttendanceStatusOnTime, AttendanceStatusLate, AttendanceStatusCorrected}
+
+// AttendedAttendanceStatuses are the regular statuses of a day the employee showed up for.
+var AttendedAttendanceStatuses = []AttendanceStatus{AttendanceStatusOnTime, AttendanceStatusLate, AttendanceStatusPresent, AttendanceStatusCorrected, AttendanceStatusIncomplete}
+
+// IsValid reports whether the status is one of the known statuses.
+func (s AttendanceStatus) IsValid() bool {
+	for _, status := range AttendanceStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOvertime reports whether the status belongs to an overtime session.
+func (s AttendanceStatus) IsOvertime() bool {
+	return s == AttendanceStatusOvertimeIn || s == AttendanceStatusOvertimeOut
+}
+
+// IsOpen reports whether a regular record with the status is still waiting for a check-out.
+func (s AttendanceStatus) IsOpen() bool {
+	return containsAttendanceStatus(OpenAttendanceStatuses, s)
+}
+
+// NormalizeAttendanceStatus returns the current value of a status, translating legacy values.
+func NormalizeAttendanceStatus(status string) AttendanceStatus {
+	if current, ok := LegacyAttendanceStatuses[status]; ok {
+		return current
+	}
+	return AttendanceStatus(status)
+}
+
+// AttendanceEvent is what causes an attendance record to change status.
+type AttendanceEvent string
+
+const (
+	AttendanceEventCheckIn          AttendanceEvent = "check_in"
+	AttendanceEventCheckOut         AttendanceEvent = "check_out"
+	AttendanceEventOvertimeCheckIn  AttendanceEvent = "overtime_check_in"
+	AttendanceEventOvertimeCheckOut AttendanceEvent = "overtime_check_out"
+	AttendanceEventCorrection       AttendanceEvent = "correction"     // An admin enters or completes a record
+	AttendanceEventAutoClose        AttendanceEvent = "auto_close"     // A scheduled job closes a forgotten record
+	AttendanceEventMarkAbsent       AttendanceEvent = "mark_absent"    // A scheduled job records a missing check-in
+	AttendanceEventLeaveOverlay     AttendanceEvent = "leave_overlay"  // Approved leave covers the day
+	AttendanceEventRevert           AttendanceEvent = "revert"         // An admin restores an earlier version
+	AttendanceEventOvertimeClose    AttendanceEvent = "overtime_close" // A scheduled job or an admin closes a forgotten overtime session
+	AttendanceEventImport           AttendanceEvent = "import"         // An admin imports punch logs from a legacy time clock
+)
+
+// ErrInvalidAttendanceTransition is returned when a write would move a record to a status its current
+// status cannot reach through the event.
+var ErrInvalidAttendanceTransition = errors.New("invalid attendance status transition")
+
+// attendanceTransition lists the statuses an event may start from and end in. An empty "from" status
+// stands for a record that does not exist yet.
+type attendanceTransition struct {
+	from []AttendanceStatus
+	to   []AttendanceStatus
+}
+
+var attendanceTransitions = map[AttendanceEvent]attendanceTransition{
+	AttendanceEventCheckIn: {
+		// An absent record written before a delayed offline upload arrived is replaced by the check-in.
+		from: []AttendanceStatus{"", AttendanceStatusAbsent},
+		to:   []AttendanceStatus{AttendanceStatusOnTime, AttendanceStatusLate},
+	},
+	AttendanceEventCheckOut: {
+		// Days shorter than the attendance policy's minimum worked time are closed as incomplete.
+		from: OpenAttendanceStatuses,
+		to:   []AttendanceStatus{AttendanceStatusPresent, AttendanceStatusIncomplete},
+	},
+	AttendanceEventOvertimeCheckIn: {
+		from: []AttendanceStatus{""},
+		to:   []AttendanceStatus{AttendanceStatusOvertimeIn},
+	},
+	AttendanceEventOvertimeCheckOut: {
+		from: []AttendanceStatus{AttendanceStatusOvertimeIn},
+		to:   []AttendanceStatus{AttendanceStatusOvertimeOut},
+	},
+	AttendanceEventCorrection: {
+		from: []AttendanceStatus{"", AttendanceStatusOnTime, AttendanceStatusLate, AttendanceStatusCorrected, AttendanceStatusIncomplete},
+		to:   []AttendanceStatus{AttendanceStatusCorrected},
+	},
+	AttendanceEventAutoClose: {
+		from: OpenAttendanceStatuses,
+		to:   []AttendanceStatus{AttendanceStatusIncomplete},
+	},
+	AttendanceEventMarkAbsent: {
+		// Re-running absentee processing after leave was cancelled turns the leave record back into an absence.
+		from: []AttendanceStatus{"", AttendanceStatusOnLeave, AttendanceStatusOnSick},
+		to:   []AttendanceStatus{AttendanceStatusAbsent},
+	},
+	AttendanceEventLeaveOverlay: {
+		from: []AttendanceStatus{"", AttendanceStatusAbsent, AttendanceStatusOnLeave, AttendanceStatusOnSick},
+		to:   []AttendanceStatus{AttendanceStatusOnLeave, AttendanceStatusOnSick},
+	},
+	AttendanceEventOvertimeClose: {
+		from: []AttendanceStatus{AttendanceStatusOvertimeIn},
+		to:   []AttendanceStatus{AttendanceStatusOvertimeOut},
+	},
+	AttendanceEventImport: {
+		// Imported days replace the absent records the daily job wrote before the logs were imported.
+		from: []AttendanceStatus{"", AttendanceStatusAbsent},
+		to:   []AttendanceStatus{AttendanceStatusOnTime, AttendanceStatusLate, AttendanceStatusPresent, AttendanceStatusIncomplete},
+	},
+	AttendanceEventRevert: {
+		from: AttendanceStatuses,
+		to:   AttendanceStatuses,
+	},
+}
+
+// ValidateAttendanceTransition checks that the event may move a record from one status to another. Pass an
+// empty "from" status for a new record. Writes that keep the status, such as recalculated overtime minutes,
+// are always allowed for a valid status.
+func ValidateAttendanceTransition(event AttendanceEvent, from, to AttendanceStatus) error {
+	if !to.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidAttendanceTransition, to)
+	}
+	if from != "" && from == to {
+		return nil
+	}
+
+	transition, ok := attendanceTransitions[event]
+	if !ok {
+		return fmt.Errorf("%w: %q to %q needs a known event, got %q", ErrInvalidAttendanceTransition, from, to, event)
+	}
+	if !containsAttendanceStatus(transition.from, from) || !containsAttendanceStatus(transition.to, to) {
+		if from == "" {
+			return fmt.Errorf("%w: %s cannot create a %q record", ErrInvalidAttendanceTransition, event, to)
+		}
+		return fmt.Errorf("%w: %s cannot move a record from %q to %q", ErrInvalidAttendanceTransition, event, from, to)
+	}
+	return nil
+}
+
+// AttendanceStatusCheck is the SQL check constraint that limits the status column to the known statuses.
+func AttendanceStatusCheck() string {
+	check := "status IN ("
+	for i, status := range AttendanceStatuses {
+		if i > 0 {
+			check += ", "
+		}
+		check += "'" + string(status) + "'"
+	}
+	return check + ")"
+}
+
+func containsAttendanceStatus(statuses []AttendanceStatus, status AttendanceStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
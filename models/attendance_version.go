@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Actors that can change an attendance record.
+const (
+	AttendanceActorEmployee = "employee"
+	AttendanceActorAdmin    = "admin"
+	AttendanceActorSystem   = "system"
+)
+
+// AttendanceChange describes who is writing an attendance record and why. It is stored with the resulting version.
+type AttendanceChange struct {
+	ActorType string // One of the AttendanceActor* constants
+	ActorID   *uint  // Employee or admin ID; nil for system jobs
+	Reason    string
+	Action    string          // Overrides the version action recorded by the repository, e.g. "revert"
+	Event     AttendanceEvent // What moves the record to its new status; checked by the repository
+}
+
+// AttendanceSnapshot holds the stored values of an attendance record at one point in its history.
+type AttendanceSnapshot struct {
+	EmployeeID              int        `json:"employee_id"`
+	CheckInTime             time.Time  `json:"check_in_time"`
+	CheckOutTime            *time.Time `json:"check_out_time"`
+	OvertimeMinutes         int        `json:"overtime_minutes"`
+	ApprovedOvertimeMinutes int        `json:"approved_overtime_minutes"`
+	OvertimeRequestID       *uint      `json:"overtime_request_id"`
+	IsOvertimeUnapproved    bool       `json:"is_overtime_unapproved"`
+	Status                  string     `json:"status"` // Kept as written; legacy values are translated on restore
+	IsCorrection            bool       `json:"is_correction"`
+	Notes                   string     `json:"notes"`
+	LocationFlags           string     `json:"location_flags"`
+	CheckInProof            string     `json:"check_in_proof"`
+	WorkMode                string     `json:"work_mode"`
+	CorrectedByAdminID      *uint      `json:"corrected_by_admin_id"`
+}
+
+// AttendanceVersion is one entry in the change history of an attendance record.
+type AttendanceVersion struct {
+	gorm.Model
+	AttendanceID int                 `json:"attendance_id" gorm:"not null;uniqueIndex:idx_attendance_version"`
+	Version      int                 `json:"version" gorm:"not null;uniqueIndex:idx_attendance_version"`
+	Action       string              `json:"action" gorm:"type:varchar(20);not null"`     // e.g., "create", "update", "revert", "baseline"
+	ActorType    string              `json:"actor_type" gorm:"type:varchar(20);not null"` // e.g., "employee", "admin", "system"
+	ActorID      *uint               `json:"actor_id"`
+	Reason       string              `json:"reason" gorm:"type:text"`
+	Before       *AttendanceSnapshot `json:"before" gorm:"type:json;serializer:json"` // Nil for the first version
+	After        *AttendanceSnapshot `json:"after" gorm:"type:json;serializer:json"`
+}
+
+// NewAttendanceSnapshot copies the stored values of an attendance record.
+func NewAttendanceSnapshot(attendance *AttendancesTable) *AttendanceSnapshot {
+	return &AttendanceSnapshot{
+		EmployeeID:              attendance.EmployeeID,
+		CheckInTime:             attendance.CheckInTime,
+		CheckOutTime:            attendance.CheckOutTime,
+		OvertimeMinutes:         attendance.OvertimeMinutes,
+		ApprovedOvertimeMinutes: attendance.ApprovedOvertimeMinutes,
+		OvertimeRequestID:       attendance.OvertimeRequestID,
+		IsOvertimeUnapproved:    attendance.IsOvertimeUnapproved,
+		Status:                  string(attendance.Status),
+		IsCorrection:            attendance.IsCorrection,
+		Notes:                   attendance.Notes,
+		LocationFlags:           attendance.LocationFlags,
+		CheckInProof:            attendance.CheckInProof,
+		WorkMode:                attendance.WorkMode,
+		CorrectedByAdminID:      attendance.CorrectedByAdminID,
+	}
+}
+
+// ApplyTo restores the snapshot's values onto an attendance record.
+func (s *AttendanceSnapshot) ApplyTo(attendance *AttendancesTable) {
+	attendance.EmployeeID = s.EmployeeID
+	attendance.CheckInTime = s.CheckInTime
+	attendance.CheckOutTime = s.CheckOutTime
+	attendance.OvertimeMinutes = s.OvertimeMinutes
+	attendance.ApprovedOvertimeMinutes = s.ApprovedOvertimeMinutes
+	attendance.OvertimeRequestID = s.OvertimeRequestID
+	attendance.IsOvertimeUnapproved = s.IsOvertimeUnapproved
+	attendance.Status = NormalizeAttendanceStatus(s.Status)
+	attendance.IsCorrection = s.IsCorrection
+	attendance.Notes = s.Notes
+	attendance.LocationFlags = s.LocationFlags
+	attendance.CheckInProof = s.CheckInProof
+	attendance.WorkMode = s.WorkMode
+	attendance.CorrectedByAdminID = s.CorrectedByAdminID
+	attendance.CorrectedByAdmin = AdminCompaniesTable{} // Keep a preloaded admin from overriding the restored ID on save
+}
@@ -21,6 +21,7 @@ type CompaniesTable struct {
 	TrialStartDate       *time.Time    `json:"trial_start_date,omitempty"`
 	TrialEndDate         *time.Time    `json:"trial_end_date,omitempty"`
 	BillingCycle         string        `json:"billing_cycle" gorm:"default:'monthly'"` // e.g., 'monthly', 'yearly'
+	OvertimeRequiresApproval bool      `json:"overtime_requires_approval" gorm:"default:false"` // Block overtime check-in without an approved request instead of flagging it
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
 	AdminCompaniesTable []AdminCompaniesTable `gorm:"foreignKey:CompanyID"` // Has many AdminCompaniesTable
@@ -14,6 +14,7 @@ type EmployeesTable struct {
 	Role               string     `json:"role"`
 	ShiftID            *int       `json:"shift_id"` // Pointer to allow null
 	DivisionID         *int       `json:"division_id"` // Pointer to allow null
+	JoinDate           *time.Time `gorm:"type:date" json:"join_date"` // Day the employee was hired; when the row was created if unset
 	Shift            ShiftsTable    `gorm:"foreignKey:ShiftID" json:"shift"`
 	Division           DivisionTable   `gorm:"foreignKey:DivisionID" json:"division"`
 	IsPasswordSet    bool           `gorm:"default:false" json:"is_password_set"` // New field
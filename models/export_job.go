@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reports that can be exported in the background.
+const (
+	ExportReportAttendances = "attendances"
+)
+
+// States of a background export.
+const (
+	ExportJobStatusPending   = "pending"
+	ExportJobStatusRunning   = "running"
+	ExportJobStatusCompleted = "completed"
+	ExportJobStatusFailed    = "failed"
+	ExportJobStatusExpired   = "expired"
+)
+
+// ExportJob is an export too large to stream in a request. It is written to a file in the background, which the
+// admins of the company can download until it expires.
+type ExportJob struct {
+	gorm.Model
+	CompanyID   int        `json:"company_id" gorm:"not null;index"`
+	RequestedBy uint       `json:"requested_by"` // Admin ID who requested the export
+	Report      string     `json:"report" gorm:"type:varchar(30);not null"`
+	Format      string     `json:"format" gorm:"type:varchar(10);not null"`
+	StartDate   *time.Time `json:"start_date" gorm:"type:date"`
+	EndDate     *time.Time `json:"end_date" gorm:"type:date"`
+	Status      string     `json:"status" gorm:"type:varchar(20);default:'pending';index"` // "pending", "running", "completed", "failed" or "expired"
+	RowCount    int        `json:"row_count"`
+	FileName    string     `json:"file_name,omitempty"`
+	FilePath    string     `json:"-"` // Location of the file on the server
+	FileSize    int64      `json:"file_size"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	DownloadURL string     `json:"download_url,omitempty" gorm:"-"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClientSite is a customer location registered by a company. Field visits are checked against it as a soft
+// geofence: visits outside the radius are accepted but flagged.
+type ClientSite struct {
+	gorm.Model
+	CompanyID uint    `json:"company_id" gorm:"not null;index"`
+	Name      string  `json:"name" gorm:"not null"`
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude" gorm:"not null"`
+	Longitude float64 `json:"longitude" gorm:"not null"`
+	Radius    uint    `json:"radius" gorm:"not null;default:200"` // Meters
+}
+
+// FieldVisit is a face-verified visit of a mobile employee to a customer, recorded at arbitrary coordinates.
+type FieldVisit struct {
+	gorm.Model
+	EmployeeID     int            `json:"employee_id" gorm:"not null;index"`
+	Employee       EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	ClientSiteID   *uint          `json:"client_site_id" gorm:"index"`
+	ClientSite     *ClientSite    `json:"client_site,omitempty" gorm:"foreignKey:ClientSiteID"`
+	CustomerName   string         `json:"customer_name" gorm:"not null"`
+	Notes          string         `json:"notes" gorm:"type:text"`
+	Status         string         `json:"status" gorm:"type:varchar(20);default:'in_progress'"` // e.g., "in_progress", "completed"
+	StartedAt      time.Time      `json:"started_at" gorm:"index"`
+	StartLatitude  float64        `json:"start_latitude"`
+	StartLongitude float64        `json:"start_longitude"`
+	StartAccuracy  *float64       `json:"start_accuracy"`
+	StartPhotoPath string         `json:"start_photo_path,omitempty"`
+	EndedAt        *time.Time     `json:"ended_at"`
+	EndLatitude    *float64       `json:"end_latitude"`
+	EndLongitude   *float64       `json:"end_longitude"`
+	EndAccuracy    *float64       `json:"end_accuracy"`
+	EndPhotoPath   string         `json:"end_photo_path,omitempty"`
+	OutsideSite    bool           `json:"outside_site"` // Start or end was outside the client site's radius
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key header, so that a retried
+// request is answered with the same response instead of being processed again. Rows are hard-deleted,
+// which keeps the unique index free for keys that have expired.
+type IdempotencyKey struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CompanyID   int       `json:"company_id" gorm:"not null;uniqueIndex:idx_idempotency_scope"`
+	Endpoint    string    `json:"endpoint" gorm:"type:varchar(100);not null;uniqueIndex:idx_idempotency_scope"`
+	Key         string    `json:"key" gorm:"type:varchar(100);not null;uniqueIndex:idx_idempotency_scope"`
+	RequestHash string    `json:"request_hash" gorm:"type:varchar(64);not null"` // SHA-256 of the request body
+	StatusCode  int       `json:"status_code"`                                   // Zero while the request is still being processed
+	Response    string    `json:"response" gorm:"type:mediumtext"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"index"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KioskBatch is one upload of offline check-ins from a kiosk device.
+type KioskBatch struct {
+	gorm.Model
+	KioskDeviceID uint         `json:"kiosk_device_id" gorm:"not null;index"`
+	KioskDevice   KioskDevice  `json:"kiosk_device" gorm:"foreignKey:KioskDeviceID"`
+	CompanyID     int          `json:"company_id" gorm:"not null;index"`
+	Status        string       `json:"status" gorm:"type:varchar(50);not null"` // e.g., "processed", "flagged"
+	Flags         string       `json:"flags" gorm:"type:text"`                  // Comma-separated reasons the batch was flagged for review
+	EntryCount    int          `json:"entry_count"`
+	AcceptedCount int          `json:"accepted_count"`
+	RejectedCount int          `json:"rejected_count"`
+	Entries       []KioskEntry `json:"entries,omitempty" gorm:"foreignKey:KioskBatchID"`
+}
+
+// KioskEntry is a single signed check-in captured by a kiosk and the result of processing it.
+type KioskEntry struct {
+	gorm.Model
+	KioskBatchID     uint   `json:"kiosk_batch_id" gorm:"not null;index"`
+	KioskDeviceID    uint   `json:"kiosk_device_id" gorm:"not null;index;uniqueIndex:idx_kiosk_entry_accepted_sequence"`
+	Sequence         int64  `json:"sequence"`
+	AcceptedSequence *int64 `json:"-" gorm:"uniqueIndex:idx_kiosk_entry_accepted_sequence"` // The sequence while the entry is pending or accepted, so it is applied once
+
+	EmployeeID   int       `json:"employee_id"`
+	CapturedAt   time.Time `json:"captured_at"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	ImageHash    string    `json:"image_hash" gorm:"type:varchar(64)"` // SHA-256 of the uploaded frame
+	Result       string    `json:"result" gorm:"type:varchar(50)"`     // e.g., "accepted", "rejected"
+	Message      string    `json:"message" gorm:"type:text"`
+	AttendanceID *int      `json:"attendance_id"`
+}
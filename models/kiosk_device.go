@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KioskDevice is a registered attendance kiosk that can capture check-ins offline and upload them later.
+type KioskDevice struct {
+	gorm.Model
+	CompanyID      int        `json:"company_id" gorm:"not null;index"`
+	Name           string     `json:"name" gorm:"type:varchar(255);not null"`
+	PublicKey      string     `json:"public_key" gorm:"type:varchar(255);not null"`    // Base64 Ed25519 public key; the private key never leaves the device
+	Status         string     `json:"status" gorm:"type:varchar(50);default:'active'"` // e.g., "active", "revoked"
+	LastSequence   int64      `json:"last_sequence" gorm:"default:0"`                  // Highest entry sequence accepted from this device
+	LastSyncedAt   *time.Time `json:"last_synced_at"`
+	RegisteredByID uint       `json:"registered_by_id"` // Admin ID who registered the device
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// How a leave policy grants the annual quota.
+const (
+	LeaveAccrualAnnual  = "annual"  // The whole quota on the first of January, prorated in the year probation ends
+	LeaveAccrualMonthly = "monthly" // A twelfth of the quota on the first of every month
+)
+
+// What applying for more leave than the balance holds does.
+const (
+	InsufficientLeaveReject = "reject"
+	InsufficientLeaveWarn   = "warn"
+)
+
+// Kinds of leave ledger entries.
+const (
+	LeaveEntryAccrual    = "accrual"
+	LeaveEntryCarryOver  = "carry_over"
+	LeaveEntryExpiry     = "expiry"     // Carried-over days not taken before they expire
+	LeaveEntryDebit      = "debit"      // Approved leave
+	LeaveEntryCredit     = "credit"     // Approved leave that was cancelled
+	LeaveEntryAdjustment = "adjustment" // Manual correction by an admin
+)
+
+// LeavePolicy is a company's entitlement to annual leave, which leave types that count against the balance are
+// taken from. Companies without one keep unlimited leave.
+type LeavePolicy struct {
+	gorm.Model
+	CompanyID             int     `json:"company_id" gorm:"not null;uniqueIndex"`
+	AnnualQuota           float64 `json:"annual_quota"`                                                  // Days per year
+	AccrualMethod         string  `json:"accrual_method" gorm:"type:varchar(20);default:'annual'"`       // "annual" or "monthly"
+	ProbationMonths       int     `json:"probation_months" gorm:"default:0"`                             // Months after joining before leave accrues
+	CarryOverLimit        float64 `json:"carry_over_limit" gorm:"default:0"`                             // Unused days moved into the next year
+	CarryOverExpiryMonths int     `json:"carry_over_expiry_months" gorm:"default:0"`                     // Carried-over days expire this many months into the year, 0 = never
+	InsufficientBalance   string  `json:"insufficient_balance" gorm:"type:varchar(20);default:'reject'"` // "reject" or "warn"
+	StartYear             int     `json:"start_year" gorm:"not null;default:0"`                          // First year balances are kept for
+}
+
+// LeaveLedgerEntry is one change to an employee's leave balance for a year. The balance is the sum of the entries.
+type LeaveLedgerEntry struct {
+	gorm.Model
+	CompanyID      int       `json:"company_id" gorm:"not null;index"`
+	EmployeeID     int       `json:"employee_id" gorm:"not null;index:idx_leave_ledger_employee_year"`
+	Year           int       `json:"year" gorm:"not null;index:idx_leave_ledger_employee_year"`
+	Kind           string    `json:"kind" gorm:"type:varchar(20);not null"`
+	Days           float64   `json:"days"` // Positive adds to the balance, negative takes from it
+	EffectiveDate  time.Time `json:"effective_date" gorm:"type:date;not null"`
+	LeaveRequestID *uint     `json:"leave_request_id,omitempty" gorm:"index"`
+	Reference      *string   `json:"-" gorm:"type:varchar(100);uniqueIndex"`       // Stops an entry from being posted twice
+	ActorType      string    `json:"actor_type,omitempty" gorm:"type:varchar(20)"` // "system" or "admin"
+	ActorID        *uint     `json:"actor_id,omitempty"`
+	Note           string    `json:"note,omitempty" gorm:"type:text"`
+}
@@ -6,18 +6,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// Codes of the leave types every company starts with. Companies can add their own.
+const (
+	LeaveTypeAnnual = "cuti"  // Annual leave, debited from the leave balance when the company has a leave policy
+	LeaveTypeSick   = "sakit" // Sick leave, needs a sick note
+)
+
 type LeaveRequest struct {
 	gorm.Model
 	EmployeeID  uint      `json:"employee_id" gorm:"not null"`
 	Employee    EmployeesTable  `json:"employee" gorm:"foreignKey:EmployeeID"` // BelongsTo Employee
-	Type        string    `json:"type" gorm:"type:varchar(50);not null"` // e.g., "cuti", "sakit"
+	Type        string    `json:"type" gorm:"type:varchar(50);not null"` // Code of a LeaveType, e.g., "cuti", "sakit"
 	StartDate   time.Time `json:"start_date" gorm:"not null"`
 	EndDate     time.Time `json:"end_date" gorm:"not null"`
+	Days        float64   `json:"days"` // Working days taken, excluding rest days and public holidays
 	Reason      string    `json:"reason" gorm:"type:text;not null"`
 	Status      string    `json:"status" gorm:"type:varchar(50);default:'pending'"` // e.g., "pending", "approved", "rejected"
 	ReviewedBy  *uint     `json:"reviewed_by"` // Admin ID who reviewed it
 	ReviewedAt  *time.Time `json:"reviewed_at"`
 	CancelledByActorType string `json:"cancelled_by_actor_type,omitempty"` // e.g., "employee", "admin"
 	CancelledByActorID *uint `json:"cancelled_by_actor_id,omitempty"` // ID of the employee or admin who cancelled it
-	SickNotePath string `json:"sick_note_path,omitempty"` // Path to the uploaded sick note file
+	SickNotePath string `json:"sick_note_path,omitempty"` // Path to the uploaded attachment, e.g. a sick note
+	BalanceWarning string `json:"balance_warning,omitempty" gorm:"-"` // Set when the leave policy lets the request exceed the balance
 }
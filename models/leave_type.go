@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// LeaveType is a kind of leave a company's employees can apply for, such as maternity, marriage or unpaid leave.
+// Leave requests refer to it by code. Types are deactivated rather than deleted, as past requests keep their code.
+type LeaveType struct {
+	gorm.Model
+	CompanyID            int              `json:"company_id" gorm:"not null;uniqueIndex:idx_leave_type_company_code"`
+	Code                 string           `json:"code" gorm:"type:varchar(50);not null;uniqueIndex:idx_leave_type_company_code"` // e.g. "cuti", "sakit", "melahirkan"
+	Name                 string           `json:"name" gorm:"type:varchar(100);not null"`
+	IsPaid               bool             `json:"is_paid"`
+	RequiresAttachment   bool             `json:"requires_attachment" gorm:"default:false"` // e.g. a sick note or a marriage certificate
+	MaxDaysPerRequest    int              `json:"max_days_per_request" gorm:"default:0"`    // Working days, 0 = no limit
+	CountsAgainstBalance bool             `json:"counts_against_balance" gorm:"default:false"`
+	AttendanceStatus     AttendanceStatus `json:"attendance_status" gorm:"type:varchar(20);default:'on_leave'"` // Written for the days of approved leave: "on_leave" or "on_sick"
+	IsActive             bool             `json:"is_active"`
+}
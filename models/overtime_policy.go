@@ -0,0 +1,23 @@
+package models
+
+import "gorm.io/gorm"
+
+// OvertimePolicy holds a company's overtime compensation multipliers.
+// Rest days and public holidays share the same tier layout: regular hours, the next hour, and every hour after.
+type OvertimePolicy struct {
+	gorm.Model
+	CompanyID                  int     `json:"company_id" gorm:"not null;uniqueIndex"`
+	RestDays                   string  `json:"rest_days" gorm:"type:varchar(20);default:'0,6'"` // Comma-separated weekdays, 0 = Sunday
+	WeekdayFirstHourMultiplier float64 `json:"weekday_first_hour_multiplier" gorm:"default:1.5"`
+	WeekdayNextHoursMultiplier float64 `json:"weekday_next_hours_multiplier" gorm:"default:2"`
+	RestDayRegularHours        int     `json:"rest_day_regular_hours" gorm:"default:8"`
+	RestDayRegularMultiplier   float64 `json:"rest_day_regular_multiplier" gorm:"default:2"`
+	RestDayNextHourMultiplier  float64 `json:"rest_day_next_hour_multiplier" gorm:"default:3"`
+	RestDayAfterMultiplier     float64 `json:"rest_day_after_multiplier" gorm:"default:4"`
+	HolidayRegularHours        int     `json:"holiday_regular_hours" gorm:"default:8"`
+	HolidayRegularMultiplier   float64 `json:"holiday_regular_multiplier" gorm:"default:2"`
+	HolidayNextHourMultiplier  float64 `json:"holiday_next_hour_multiplier" gorm:"default:3"`
+	HolidayAfterMultiplier     float64 `json:"holiday_after_multiplier" gorm:"default:4"`
+	MaxSessionMinutes          int     `json:"max_session_minutes" gorm:"default:720"`           // Open sessions are auto-closed after this long
+	CreditAutoClosedSessions   bool    `json:"credit_auto_closed_sessions" gorm:"default:false"` // Auto-closed sessions count the maximum instead of zero minutes
+}
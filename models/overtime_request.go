@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OvertimeRequest is a planned overtime session that must be approved by an admin before it is paid.
+type OvertimeRequest struct {
+	gorm.Model
+	EmployeeID      uint           `json:"employee_id" gorm:"not null;index"`
+	Employee        EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	Date            time.Time      `json:"date" gorm:"type:date;not null"`
+	PlannedMinutes  int            `json:"planned_minutes" gorm:"not null"`
+	ApprovedMinutes int            `json:"approved_minutes" gorm:"default:0"` // Cap applied to overtime sessions linked to this request
+	Reason          string         `json:"reason" gorm:"type:text;not null"`
+	Status          string         `json:"status" gorm:"type:varchar(50);default:'pending'"` // e.g., "pending", "approved", "rejected", "cancelled"
+	SubmittedByID   uint           `json:"submitted_by_id"`                                  // Employee (self or supervisor) who submitted the request
+	ReviewedBy      *uint          `json:"reviewed_by"`                                      // Admin ID who reviewed it
+	ReviewedAt      *time.Time     `json:"reviewed_at"`
+	ReviewNotes     string         `json:"review_notes,omitempty"`
+}
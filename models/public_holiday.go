@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PublicHoliday is a company-observed public holiday used to pick holiday overtime rates.
+type PublicHoliday struct {
+	gorm.Model
+	CompanyID int       `json:"company_id" gorm:"not null;uniqueIndex:idx_company_holiday_date"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_company_holiday_date"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+}
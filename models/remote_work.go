@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Work modes recorded on attendance records.
+const (
+	WorkModeOnsite = "onsite"
+	WorkModeRemote = "remote"
+)
+
+// How check-ins on remote work days are located.
+const (
+	RemoteGeofenceHomeLocation = "home_location" // Must be within the employee's approved home location
+	RemoteGeofenceNone         = "none"          // Only face verification and the location sanity checks apply
+)
+
+// RemoteWorkPolicy holds a company's rules for remote (work-from-home) check-ins.
+type RemoteWorkPolicy struct {
+	gorm.Model
+	CompanyID    int    `json:"company_id" gorm:"not null;uniqueIndex"`
+	GeofenceMode string `json:"geofence_mode" gorm:"type:varchar(20);default:'home_location'"` // "home_location" or "none"
+	HomeRadius   uint   `json:"home_radius" gorm:"default:200"`                                // Meters around the home location
+}
+
+// RemoteWorkSchedule gives an employee or a whole division recurring remote work days.
+// Exactly one of EmployeeID and DivisionID is set.
+type RemoteWorkSchedule struct {
+	gorm.Model
+	CompanyID  int            `json:"company_id" gorm:"not null;index"`
+	EmployeeID *int           `json:"employee_id" gorm:"index"`
+	Employee   EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	DivisionID *int           `json:"division_id" gorm:"index"`
+	Weekdays   string         `json:"weekdays" gorm:"type:varchar(20);not null"` // Comma-separated weekdays, 0 = Sunday
+	StartDate  *time.Time     `json:"start_date" gorm:"type:date"`               // Nil means the schedule is already active
+	EndDate    *time.Time     `json:"end_date" gorm:"type:date"`                 // Nil means the schedule does not end
+}
+
+// RemoteWorkRequest is an employee's request to work remotely on a range of days.
+type RemoteWorkRequest struct {
+	gorm.Model
+	EmployeeID  uint           `json:"employee_id" gorm:"not null;index"`
+	Employee    EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	StartDate   time.Time      `json:"start_date" gorm:"type:date;not null"`
+	EndDate     time.Time      `json:"end_date" gorm:"type:date;not null"`
+	Reason      string         `json:"reason" gorm:"type:text;not null"`
+	Status      string         `json:"status" gorm:"type:varchar(50);default:'pending'"` // e.g., "pending", "approved", "rejected", "cancelled"
+	ReviewedBy  *uint          `json:"reviewed_by"`                                      // Admin ID who reviewed it
+	ReviewedAt  *time.Time     `json:"reviewed_at"`
+	ReviewNotes string         `json:"review_notes,omitempty"`
+}
+
+// EmployeeHomeLocation is the place an employee checks in from on remote work days. A changed location
+// must be approved again before it can be used.
+type EmployeeHomeLocation struct {
+	gorm.Model
+	EmployeeID uint           `json:"employee_id" gorm:"not null;uniqueIndex"`
+	Employee   EmployeesTable `json:"employee" gorm:"foreignKey:EmployeeID"`
+	Latitude   float64        `json:"latitude" gorm:"not null"`
+	Longitude  float64        `json:"longitude" gorm:"not null"`
+	Address    string         `json:"address"`
+	Status     string         `json:"status" gorm:"type:varchar(50);default:'pending'"` // e.g., "pending", "approved", "rejected"
+	ReviewedBy *uint          `json:"reviewed_by"`
+	ReviewedAt *time.Time     `json:"reviewed_at"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reports that can be delivered on a schedule.
+const (
+	ScheduledReportAttendances   = "attendances"
+	ScheduledReportOvertime      = "overtime"
+	ScheduledReportUnaccounted   = "unaccounted"
+	ScheduledReportLeaveRequests = "leave_requests"
+	ScheduledReportTimesheet     = "timesheet"
+)
+
+// How often a scheduled report is delivered.
+const (
+	ReportFrequencyDaily   = "daily"
+	ReportFrequencyWeekly  = "weekly"
+	ReportFrequencyMonthly = "monthly"
+)
+
+// Outcomes of a scheduled report delivery.
+const (
+	ReportDeliveryStatusSent          = "sent"
+	ReportDeliveryStatusPartiallySent = "partially_sent"
+	ReportDeliveryStatusFailed        = "failed"
+)
+
+// ReportFilters narrows down the records of a scheduled report, as the filters of the export endpoints do.
+type ReportFilters struct {
+	Search string `json:"search,omitempty"`
+	Status string `json:"status,omitempty"` // Leave request status, for leave request reports
+}
+
+// ReportSubscription emails a report to a list of recipients on a daily, weekly or monthly schedule in the
+// company's timezone. Each delivery covers the period since the previous one: the day before, the seven days
+// before or the previous calendar month.
+type ReportSubscription struct {
+	gorm.Model
+	CompanyID  int           `json:"company_id" gorm:"not null;index"`
+	CreatedBy  uint          `json:"created_by"` // Admin ID who set up the subscription
+	Name       string        `json:"name" gorm:"type:varchar(100);not null"`
+	ReportType string        `json:"report_type" gorm:"type:varchar(30);not null"`
+	Format     string        `json:"format" gorm:"type:varchar(10);not null"`
+	Filters    ReportFilters `json:"filters" gorm:"type:json;serializer:json"`
+	Recipients []string      `json:"recipients" gorm:"type:json;serializer:json"`
+	Frequency  string        `json:"frequency" gorm:"type:varchar(10);not null"` // "daily", "weekly" or "monthly"
+	DayOfWeek  *int          `json:"day_of_week"`                                // Weekly: 0 = Sunday
+	DayOfMonth *int          `json:"day_of_month"`                               // Monthly: 1 to 28
+	SendTime   string        `json:"send_time" gorm:"type:varchar(5);not null"`  // HH:MM in the company's timezone
+	IsActive   bool          `json:"is_active"`
+	NextRunAt  time.Time     `json:"next_run_at" gorm:"index"`
+	LastRunAt  *time.Time    `json:"last_run_at"`
+}
+
+// ReportDelivery records one delivery of a scheduled report.
+type ReportDelivery struct {
+	gorm.Model
+	SubscriptionID uint       `json:"subscription_id" gorm:"not null;index"`
+	CompanyID      int        `json:"company_id" gorm:"not null;index"`
+	ReportType     string     `json:"report_type" gorm:"type:varchar(30)"`
+	PeriodStart    time.Time  `json:"period_start" gorm:"type:date"`
+	PeriodEnd      time.Time  `json:"period_end" gorm:"type:date"`
+	Recipients     []string   `json:"recipients" gorm:"type:json;serializer:json"`
+	FileName       string     `json:"file_name"`
+	Status         string     `json:"status" gorm:"type:varchar(20);index"` // "sent", "partially_sent" or "failed"
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt         *time.Time `json:"sent_at"`
+}
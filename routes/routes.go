@@ -45,8 +45,14 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	db := database.DB // Your gorm.DB instance
 
 	// Repositories
+	absenteeRepo := repository.NewAbsenteeRepository(db)
 	adminCompanyRepo := repository.NewAdminCompanyRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	attendanceAnomalyRepo := repository.NewAttendanceAnomalyRepository(db)
+	attendanceCorrectionRequestRepo := repository.NewAttendanceCorrectionRequestRepository(db)
 	attendanceLocationRepo := repository.NewAttendanceLocationRepository(db)
+	attendancePolicyRepo := repository.NewAttendancePolicyRepository(db)
+	attendancePunchRepo := repository.NewAttendancePunchRepository(db)
 	attendanceRepo := repository.NewAttendanceRepository(db)
 	broadcastRepo := repository.NewBroadcastRepository(db)
 	companyRepo := repository.NewCompanyRepository(db)
@@ -54,10 +60,21 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	customPackageRequestRepo := repository.NewCustomPackageRequestRepository(db)
 	divisionRepo := repository.NewDivisionRepository(db)
 	employeeRepo := repository.NewEmployeeRepository(db)
+	exportJobRepo := repository.NewExportJobRepository(db)
 	faceImageRepo := repository.NewFaceImageRepository(db)
+	fieldVisitRepo := repository.NewFieldVisitRepository(db)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(db)
 	invoiceRepo := repository.NewInvoiceRepository(db)
+	kioskRepo := repository.NewKioskRepository(db)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepository(db)
 	leaveRequestRepo := repository.NewLeaveRequestRepository(db)
+	leaveTypeRepo := repository.NewLeaveTypeRepository(db)
+	overtimePolicyRepo := repository.NewOvertimePolicyRepository(db)
+	overtimeRequestRepo := repository.NewOvertimeRequestRepository(db)
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	publicHolidayRepo := repository.NewPublicHolidayRepository(db)
+	remoteWorkRepo := repository.NewRemoteWorkRepository(db)
+	reportSubscriptionRepo := repository.NewReportSubscriptionRepository(db)
 	shiftRepo := repository.NewShiftRepository(db)
 	subscriptionPackageRepo := repository.NewSubscriptionPackageRepository(db)
 	superAdminRepo := repository.NewSuperAdminRepository(db)
@@ -65,22 +82,39 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	pythonClient := services.NewPythonClient()
 
 	// Services
+	absenteeService := services.NewAbsenteeService(absenteeRepo, attendancePolicyRepo)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, companyRepo)
 	authService := services.NewAuthService(superAdminRepo, adminCompanyRepo, employeeRepo, attendanceLocationRepo)
 	adminCompanyService := services.NewAdminCompanyService(adminCompanyRepo, companyRepo, employeeRepo, attendanceRepo, leaveRequestRepo)
-	attendanceService := services.NewAttendanceService(employeeRepo, companyRepo, attendanceRepo, faceImageRepo, attendanceLocationRepo, leaveRequestRepo, shiftRepo, divisionRepo, pythonClient)
+	attendanceService := services.NewAttendanceService(employeeRepo, companyRepo, attendanceRepo, faceImageRepo, attendanceLocationRepo, leaveRequestRepo, shiftRepo, divisionRepo, overtimeRequestRepo, overtimePolicyRepo, publicHolidayRepo, attendancePunchRepo, remoteWorkRepo, absenteeRepo, attendancePolicyRepo, leaveTypeRepo, pythonClient)
+	attendanceAnomalyService := services.NewAttendanceAnomalyService(attendanceAnomalyRepo, attendancePunchRepo, companyRepo)
+	attendanceCorrectionRequestService := services.NewAttendanceCorrectionRequestService(attendanceCorrectionRequestRepo, employeeRepo, companyRepo, adminCompanyRepo, attendanceRepo, attendanceService)
+	attendanceHistoryService := services.NewAttendanceHistoryService(attendanceRepo)
+	attendanceImportService := services.NewAttendanceImportService(employeeRepo, companyRepo, attendanceRepo, divisionRepo, shiftRepo, attendancePolicyRepo)
+	attendancePolicyService := services.NewAttendancePolicyService(attendancePolicyRepo, divisionRepo, shiftRepo)
 	broadcastService := services.NewBroadcastService(broadcastRepo)
 	companyService := services.NewCompanyService(companyRepo, adminCompanyRepo, subscriptionPackageRepo, shiftRepo)
 	customOfferService := services.NewCustomOfferService(customOfferRepo)
 	customPackageRequestService := services.NewCustomPackageRequestService(companyRepo, adminCompanyRepo, customPackageRequestRepo)
 	divisionService := services.NewDivisionService(divisionRepo, shiftRepo, attendanceLocationRepo)
-	employeeService := services.NewEmployeeService(employeeRepo, companyRepo, shiftRepo, passwordResetRepo, faceImageRepo, attendanceRepo, leaveRequestRepo, attendanceLocationRepo)
+	leaveBalanceService := services.NewLeaveBalanceService(leaveBalanceRepo, employeeRepo, companyRepo, leaveRequestRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo)
+	employeeService := services.NewEmployeeService(employeeRepo, companyRepo, shiftRepo, passwordResetRepo, faceImageRepo, attendanceRepo, leaveRequestRepo, attendanceLocationRepo, leaveBalanceService)
+	exportJobService := services.NewExportJobService(exportJobRepo, attendanceService)
+	fieldVisitService := services.NewFieldVisitService(fieldVisitRepo, employeeRepo, companyRepo, faceImageRepo, pythonClient)
 	initialPasswordSetupService := services.NewInitialPasswordSetupService(passwordResetRepo, employeeRepo)
-	leaveRequestService := services.NewLeaveRequestService(employeeRepo, leaveRequestRepo, adminCompanyRepo)
+	kioskService := services.NewKioskService(kioskRepo, employeeRepo, attendanceRepo, attendanceService)
+	leaveRequestService := services.NewLeaveRequestService(employeeRepo, leaveRequestRepo, adminCompanyRepo, companyRepo, leaveBalanceRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo)
+	leaveTypeService := services.NewLeaveTypeService(leaveTypeRepo)
 	locationService := services.NewLocationService(companyRepo, attendanceLocationRepo)
+	overtimePolicyService := services.NewOvertimePolicyService(overtimePolicyRepo, publicHolidayRepo, companyRepo, attendanceRepo)
+	overtimeRequestService := services.NewOvertimeRequestService(overtimeRequestRepo, employeeRepo, companyRepo, adminCompanyRepo, attendanceRepo)
 	passwordResetService := services.NewPasswordResetService(adminCompanyRepo, employeeRepo, passwordResetRepo)
 	paymentService := services.NewPaymentService(invoiceRepo, companyRepo, subscriptionPackageRepo, customOfferRepo, adminCompanyRepo, helper.NewPDFGenerator())
+	remoteWorkService := services.NewRemoteWorkService(remoteWorkRepo, employeeRepo, companyRepo, adminCompanyRepo, divisionRepo)
 	shiftService := services.NewShiftService(shiftRepo, companyRepo)
 	subscriptionPackageService := services.NewSubscriptionPackageService(subscriptionPackageRepo)
+	timesheetService := services.NewTimesheetService(companyRepo, employeeRepo, attendanceRepo, leaveRequestRepo, divisionRepo, shiftRepo, overtimePolicyRepo, publicHolidayRepo, leaveTypeRepo, attendancePolicyRepo)
+	reportSubscriptionService := services.NewReportSubscriptionService(reportSubscriptionRepo, companyRepo, attendanceService, leaveRequestService, timesheetService)
 	superAdminService := services.NewSuperAdminService(companyRepo, invoiceRepo, customPackageRequestRepo, superAdminRepo)
 
 	// Background worker for Superadmin Dashboard Updates
@@ -96,9 +130,16 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	}()
 
 	// Handlers
+	absenteeHandler := handlers.NewAbsenteeHandler(absenteeService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
 	adminCompanyHandler := handlers.NewAdminCompanyHandler(adminCompanyService)
 	// adminHandler is removed
-	attendanceHandler := handlers.NewAttendanceHandler(attendanceService, adminCompanyService) // Use adminCompanyService for dashboard summary
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService, adminCompanyService, exportJobService) // Use adminCompanyService for dashboard summary
+	attendanceAnomalyHandler := handlers.NewAttendanceAnomalyHandler(attendanceAnomalyService)
+	attendanceCorrectionRequestHandler := handlers.NewAttendanceCorrectionRequestHandler(attendanceCorrectionRequestService)
+	attendanceHistoryHandler := handlers.NewAttendanceHistoryHandler(attendanceHistoryService)
+	attendanceImportHandler := handlers.NewAttendanceImportHandler(attendanceImportService)
+	attendancePolicyHandler := handlers.NewAttendancePolicyHandler(attendancePolicyService)
 	authHandler := handlers.NewAuthHandler(authService)
 	broadcastHandler := handlers.NewBroadcastHandler(broadcastService)
 	companyHandler := handlers.NewCompanyHandler(companyService)
@@ -106,14 +147,24 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	customPackageRequestHandler := handlers.NewCustomPackageRequestHandler(customPackageRequestService)
 	divisionHandler := handlers.NewDivisionHandler(divisionService)
 	employeeHandler := handlers.NewEmployeeHandler(employeeService, shiftService)
+	exportJobHandler := handlers.NewExportJobHandler(exportJobService)
+	fieldVisitHandler := handlers.NewFieldVisitHandler(fieldVisitService)
 	initialPasswordSetupHandler := handlers.NewInitialPasswordSetupHandler(initialPasswordSetupService)
+	kioskHandler := handlers.NewKioskHandler(kioskService, adminCompanyService)
+	leaveBalanceHandler := handlers.NewLeaveBalanceHandler(leaveBalanceService)
 	leaveRequestHandler := handlers.NewLeaveRequestHandler(leaveRequestService, adminCompanyService) // Use adminCompanyService for dashboard summary
+	leaveTypeHandler := handlers.NewLeaveTypeHandler(leaveTypeService)
 	locationHandler := handlers.NewLocationHandler(locationService)
+	overtimePolicyHandler := handlers.NewOvertimePolicyHandler(overtimePolicyService)
+	overtimeRequestHandler := handlers.NewOvertimeRequestHandler(overtimeRequestService)
 	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	remoteWorkHandler := handlers.NewRemoteWorkHandler(remoteWorkService)
+	reportSubscriptionHandler := handlers.NewReportSubscriptionHandler(reportSubscriptionService)
 	shiftHandler := handlers.NewShiftHandler(shiftService)
 	subscriptionPackageHandler := handlers.NewSubscriptionPackageHandler(subscriptionPackageService)
 	superAdminHandler := handlers.NewSuperAdminHandler(superAdminService)
+	timesheetHandler := handlers.NewTimesheetHandler(timesheetService)
 	// Employee WebSocket Handler (no service dependencies for now)
 	employeeWebSocketHandler := handlers.NewEmployeeWebSocketHandler()
 
@@ -157,6 +208,7 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		apiPublic.POST("/initial-password-setup", initialPasswordSetupHandler.InitialPasswordSetup) // New route for initial password setup
 		apiPublic.GET("/confirm-email", companyHandler.ConfirmEmail)
 		apiPublic.GET("/offer/:token", customOfferHandler.HandleGetCustomOfferByToken)
+		apiPublic.POST("/kiosk/batches", kioskHandler.UploadKioskBatch(hub)) // Authenticated by per-entry device signatures
 
 		apiPublic.GET("/check-subscriptions", adminCompanyHandler.CheckAndNotifySubscriptions)
 	}
@@ -190,8 +242,10 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		// Attendance Location routes (Admin)
 		adminRoutes.GET("/company/locations", locationHandler.GetAttendanceLocations)
 		adminRoutes.POST("/company/locations", locationHandler.CreateAttendanceLocation)
+		adminRoutes.POST("/company/locations/import", locationHandler.ImportAttendanceLocations)
 		adminRoutes.PUT("/company/locations/:location_id", locationHandler.UpdateAttendanceLocation)
 		adminRoutes.DELETE("/company/locations/:location_id", locationHandler.DeleteAttendanceLocation)
+		adminRoutes.GET("/company/locations/:location_id/qr-code", locationHandler.GetLocationQRCode)
 
 		// Employee management routes
 		adminRoutes.POST("/employees", employeeHandler.CreateEmployee)
@@ -226,33 +280,122 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		adminRoutes.DELETE("/admin/divisions/:id", divisionHandler.DeleteDivision)
 
 		// Attendance routes
-		adminRoutes.POST("/attendance", func(c *gin.Context) {
+		adminRoutes.POST("/attendance", middleware.IdempotencyMiddleware(idempotencyKeyRepo), func(c *gin.Context) {
 			attendanceHandler.HandleAttendance(hub, c)
 		})
 		adminRoutes.GET("/attendances", attendanceHandler.GetAttendances)
 		adminRoutes.GET("/employees/:employeeID/attendances", attendanceHandler.GetEmployeeAttendanceHistory)
-		adminRoutes.GET("/employees/:employeeID/attendances/export", attendanceHandler.ExportEmployeeAttendanceToExcel)
-		adminRoutes.GET("/attendances/export", attendanceHandler.ExportAllAttendancesToExcel)
+		adminRoutes.GET("/employees/:employeeID/attendances/export", attendanceHandler.ExportEmployeeAttendance)
+		adminRoutes.GET("/attendances/export", func(c *gin.Context) {
+			attendanceHandler.ExportAllAttendances(hub, c)
+		})
 		adminRoutes.GET("/attendances/unaccounted", attendanceHandler.GetUnaccountedEmployees)
-		adminRoutes.GET("/attendances/unaccounted/export", attendanceHandler.ExportUnaccountedToExcel)
+		adminRoutes.GET("/attendances/unaccounted/export", attendanceHandler.ExportUnaccounted)
 		adminRoutes.GET("/attendances/overtime", attendanceHandler.GetOvertimeAttendances)
-		adminRoutes.GET("/attendances/overtime/export", attendanceHandler.ExportOvertimeToExcel)
+		adminRoutes.GET("/attendances/overtime/export", attendanceHandler.ExportOvertime)
+		adminRoutes.GET("/attendances/punches", attendanceHandler.GetAttendancePunches)
+		adminRoutes.GET("/attendances/anomalies", attendanceAnomalyHandler.GetAttendanceAnomalies)
+		adminRoutes.POST("/attendances/anomalies/scan", attendanceAnomalyHandler.ScanAttendanceAnomalies)
+		adminRoutes.PUT("/attendances/anomalies/:id/review", attendanceAnomalyHandler.ReviewAttendanceAnomaly)
+		adminRoutes.POST("/attendances/import", attendanceImportHandler.ImportAttendanceLogs)
 		adminRoutes.POST("/attendances/correction", attendanceHandler.CorrectAttendance)
+		adminRoutes.PUT("/attendances/overtime/:id/close", func(c *gin.Context) {
+			attendanceHandler.CloseOvertimeSession(hub, c)
+		})
+		adminRoutes.GET("/attendances/:id/history", attendanceHistoryHandler.GetAttendanceHistory)
+		adminRoutes.POST("/attendances/:id/revert", attendanceHistoryHandler.RevertAttendance)
+		adminRoutes.GET("/attendance-policies", attendancePolicyHandler.GetAttendancePolicies)
+		adminRoutes.PUT("/attendance-policies", attendancePolicyHandler.SaveAttendancePolicy)
+		adminRoutes.DELETE("/attendance-policies/:id", attendancePolicyHandler.DeleteAttendancePolicy)
+
+		// Offline kiosk routes
+		adminRoutes.POST("/kiosk/devices", kioskHandler.RegisterKioskDevice)
+		adminRoutes.GET("/kiosk/devices", kioskHandler.GetKioskDevices)
+		adminRoutes.PUT("/kiosk/devices/:id/revoke", kioskHandler.RevokeKioskDevice)
+		adminRoutes.GET("/kiosk/batches", kioskHandler.GetKioskBatches)
+		adminRoutes.GET("/kiosk/batches/:id", kioskHandler.GetKioskBatch)
+
+		// Attendance correction request routes (Admin)
+		adminRoutes.GET("/attendance-corrections", attendanceCorrectionRequestHandler.GetCompanyCorrectionRequests)
+		adminRoutes.PUT("/attendance-corrections/:id/review", attendanceCorrectionRequestHandler.ReviewCorrectionRequest(hub))
+
+		// Timesheet / payroll summary routes
+		adminRoutes.GET("/timesheets", timesheetHandler.GetTimesheet)
+		adminRoutes.GET("/timesheets/export", timesheetHandler.ExportTimesheet)
 
 		// Leave Request routes (Admin)
 		adminRoutes.GET("/company-leave-requests", leaveRequestHandler.GetAllCompanyLeaveRequests)
-		adminRoutes.GET("/company-leave-requests/export", leaveRequestHandler.ExportCompanyLeaveRequestsToExcel)
+		adminRoutes.GET("/company-leave-requests/export", leaveRequestHandler.ExportCompanyLeaveRequests)
 		adminRoutes.PUT("/leave-requests/:id/review", leaveRequestHandler.ReviewLeaveRequest(hub))
 		adminRoutes.PUT("/leave-requests/:id/admin-cancel", leaveRequestHandler.AdminCancelApprovedLeaveHandler)
 
+		// Leave policy and balances
+		adminRoutes.GET("/leave-policy", leaveBalanceHandler.GetLeavePolicy)
+		adminRoutes.PUT("/leave-policy", leaveBalanceHandler.UpdateLeavePolicy)
+		adminRoutes.GET("/leave-balances", leaveBalanceHandler.GetCompanyLeaveBalances)
+		adminRoutes.GET("/employees/:employeeID/leave-balance", leaveBalanceHandler.GetEmployeeLeaveBalance)
+		adminRoutes.POST("/employees/:employeeID/leave-balance/adjustments", leaveBalanceHandler.AdjustLeaveBalance)
+
+		// Leave types
+		adminRoutes.GET("/leave-types", leaveTypeHandler.GetLeaveTypes)
+		adminRoutes.POST("/leave-types", leaveTypeHandler.CreateLeaveType)
+		adminRoutes.PUT("/leave-types/:id", leaveTypeHandler.UpdateLeaveType)
+
 		// Overtime Attendance routes
-		adminRoutes.POST("/overtime/check-in", func(c *gin.Context) {
+		adminRoutes.POST("/overtime/check-in", middleware.IdempotencyMiddleware(idempotencyKeyRepo), func(c *gin.Context) {
 			attendanceHandler.HandleOvertimeCheckIn(hub, c)
 		})
-		adminRoutes.POST("/overtime/check-out", func(c *gin.Context) {
+		adminRoutes.POST("/overtime/check-out", middleware.IdempotencyMiddleware(idempotencyKeyRepo), func(c *gin.Context) {
 			attendanceHandler.HandleOvertimeCheckOut(hub, c)
 		})
 
+		// Overtime Request routes (Admin)
+		adminRoutes.GET("/overtime-requests", overtimeRequestHandler.GetCompanyOvertimeRequests)
+		adminRoutes.PUT("/overtime-requests/:id/review", overtimeRequestHandler.ReviewOvertimeRequest)
+		adminRoutes.PUT("/overtime-requests/:id/reapprove", overtimeRequestHandler.ReapproveOvertimeRequest)
+
+		// Overtime compensation routes
+		adminRoutes.GET("/overtime/policy", overtimePolicyHandler.GetOvertimePolicy)
+		adminRoutes.PUT("/overtime/policy", overtimePolicyHandler.UpdateOvertimePolicy)
+		adminRoutes.GET("/overtime/compensation", overtimePolicyHandler.GetOvertimeCompensation)
+		adminRoutes.GET("/public-holidays", overtimePolicyHandler.GetPublicHolidays)
+		adminRoutes.POST("/public-holidays", overtimePolicyHandler.CreatePublicHoliday)
+		adminRoutes.DELETE("/public-holidays/:id", overtimePolicyHandler.DeletePublicHoliday)
+		adminRoutes.GET("/absentees/policy", absenteeHandler.GetAbsenteePolicy)
+		adminRoutes.PUT("/absentees/policy", absenteeHandler.UpdateAbsenteePolicy)
+		adminRoutes.GET("/absentees/runs", absenteeHandler.GetAbsenteeRuns)
+		adminRoutes.GET("/analytics/attendance", analyticsHandler.GetAttendanceAnalytics)
+
+		// Background export routes (Admin)
+		adminRoutes.GET("/exports", exportJobHandler.GetExportJobs)
+		adminRoutes.GET("/exports/:id/download", exportJobHandler.DownloadExportJob)
+
+		// Scheduled report routes (Admin)
+		adminRoutes.GET("/report-subscriptions", reportSubscriptionHandler.GetReportSubscriptions)
+		adminRoutes.POST("/report-subscriptions", reportSubscriptionHandler.CreateReportSubscription)
+		adminRoutes.PUT("/report-subscriptions/:id", reportSubscriptionHandler.UpdateReportSubscription)
+		adminRoutes.DELETE("/report-subscriptions/:id", reportSubscriptionHandler.DeleteReportSubscription)
+		adminRoutes.POST("/report-subscriptions/:id/send", reportSubscriptionHandler.SendReportNow)
+		adminRoutes.GET("/report-deliveries", reportSubscriptionHandler.GetReportDeliveries)
+
+		// Remote work routes (Admin)
+		adminRoutes.GET("/remote-work/policy", remoteWorkHandler.GetRemoteWorkPolicy)
+		adminRoutes.PUT("/remote-work/policy", remoteWorkHandler.UpdateRemoteWorkPolicy)
+		adminRoutes.GET("/remote-work/schedules", remoteWorkHandler.GetRemoteWorkSchedules)
+		adminRoutes.POST("/remote-work/schedules", remoteWorkHandler.CreateRemoteWorkSchedule)
+		adminRoutes.DELETE("/remote-work/schedules/:id", remoteWorkHandler.DeleteRemoteWorkSchedule)
+		adminRoutes.GET("/remote-work/requests", remoteWorkHandler.GetCompanyRemoteWorkRequests)
+		adminRoutes.PUT("/remote-work/requests/:id/review", remoteWorkHandler.ReviewRemoteWorkRequest)
+		adminRoutes.GET("/remote-work/home-locations", remoteWorkHandler.GetCompanyHomeLocations)
+		adminRoutes.PUT("/remote-work/home-locations/:id/review", remoteWorkHandler.ReviewHomeLocation)
+
+		// Field visit routes (Admin)
+		adminRoutes.GET("/field-visits/timeline", fieldVisitHandler.GetFieldVisitTimeline)
+		adminRoutes.GET("/client-sites", fieldVisitHandler.GetClientSites)
+		adminRoutes.POST("/client-sites", fieldVisitHandler.CreateClientSite)
+		adminRoutes.PUT("/client-sites/:id", fieldVisitHandler.UpdateClientSite)
+		adminRoutes.DELETE("/client-sites/:id", fieldVisitHandler.DeleteClientSite)
+
 		// Broadcast routes
 		adminRoutes.POST("/broadcasts", func(c *gin.Context) {
 			broadcastHandler.BroadcastMessage(hub, c)
@@ -277,6 +420,8 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		superAdminRoutes.POST("/custom-offers", customOfferHandler.HandleCreateCustomOffer)
 		superAdminRoutes.GET("/custom-package-requests", superAdminHandler.GetCustomPackageRequests)
 		superAdminRoutes.PUT("/custom-package-requests/:id/:status", superAdminHandler.UpdateCustomPackageRequestStatus)
+		superAdminRoutes.POST("/absentees/backfill", attendanceHandler.BackfillAbsentees)
+		superAdminRoutes.GET("/absentees/runs/latest", absenteeHandler.GetLatestAbsenteeRuns)
 	}
 
 	// Employee-specific routes (also accessible by superadmin/admin if desired via role middleware)
@@ -287,8 +432,29 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		employeeRoutes.PUT("/profile", employeeHandler.UpdateEmployeeProfile)
 		employeeRoutes.PUT("/change-password", employeeHandler.ChangeEmployeePassword)
 		employeeRoutes.GET("/dashboard-summary", employeeHandler.GetEmployeeDashboardSummary)
+		employeeRoutes.GET("/leave-balance", leaveBalanceHandler.GetMyLeaveBalance)
+		employeeRoutes.GET("/leave-types", leaveTypeHandler.GetAvailableLeaveTypes)
 		// Allow employees to register their own face image
 		employeeRoutes.POST("/register-face", employeeHandler.UploadFaceImage)
+		// Attendance correction requests
+		employeeRoutes.POST("/attendance-corrections", attendanceCorrectionRequestHandler.SubmitCorrectionRequest)
+		employeeRoutes.GET("/attendance-corrections", attendanceCorrectionRequestHandler.GetMyCorrectionRequests)
+		employeeRoutes.PUT("/attendance-corrections/:id/cancel", attendanceCorrectionRequestHandler.CancelCorrectionRequest)
+		// Overtime pre-approval requests
+		employeeRoutes.POST("/overtime-requests", overtimeRequestHandler.SubmitOvertimeRequest)
+		employeeRoutes.GET("/overtime-requests", overtimeRequestHandler.GetMyOvertimeRequests)
+		employeeRoutes.PUT("/overtime-requests/:id/cancel", overtimeRequestHandler.CancelOvertimeRequest)
+		// Remote work (WFH) requests and home location
+		employeeRoutes.POST("/remote-work/requests", remoteWorkHandler.SubmitRemoteWorkRequest)
+		employeeRoutes.GET("/remote-work/requests", remoteWorkHandler.GetMyRemoteWorkRequests)
+		employeeRoutes.PUT("/remote-work/requests/:id/cancel", remoteWorkHandler.CancelRemoteWorkRequest)
+		employeeRoutes.GET("/remote-work/home-location", remoteWorkHandler.GetMyHomeLocation)
+		employeeRoutes.PUT("/remote-work/home-location", remoteWorkHandler.RegisterHomeLocation)
+		// Field visits of mobile employees
+		employeeRoutes.POST("/field-visits", fieldVisitHandler.StartFieldVisit)
+		employeeRoutes.PUT("/field-visits/:id/end", fieldVisitHandler.EndFieldVisit)
+		employeeRoutes.GET("/field-visits", fieldVisitHandler.GetMyFieldVisits)
+		employeeRoutes.GET("/client-sites", fieldVisitHandler.GetClientSites)
 	}
 
 	// WebSocket Dashboard Update route
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+)
+
+// AbsenteeService defines the interface for absentee job settings and run history.
+type AbsenteeService interface {
+	GetAbsenteePolicy(companyID int) (*AbsenteePolicy, error)
+	UpdateAbsenteePolicy(companyID int, req UpdateAbsenteePolicyRequest) (*AbsenteePolicy, error)
+	GetAbsenteeRuns(companyID int, page, pageSize int) ([]models.AbsenteeRun, int64, error)
+	GetLatestAbsenteeRuns() ([]models.AbsenteeRun, error)
+}
+
+// absenteeService is the concrete implementation of AbsenteeService.
+type absenteeService struct {
+	absenteeRepo         repository.AbsenteeRepository
+	attendancePolicyRepo repository.AttendancePolicyRepository
+}
+
+// NewAbsenteeService creates a new instance of AbsenteeService.
+func NewAbsenteeService(absenteeRepo repository.AbsenteeRepository, attendancePolicyRepo repository.AttendancePolicyRepository) AbsenteeService {
+	return &absenteeService{
+		absenteeRepo:         absenteeRepo,
+		attendancePolicyRepo: attendancePolicyRepo,
+	}
+}
+
+// AbsenteePolicy is the company's absentee job setting. It is a view of the post-shift close time of the company
+// attendance policy, which division and shift overrides can still change.
+type AbsenteePolicy struct {
+	CompanyID    int `json:"company_id"`
+	GraceMinutes int `json:"grace_minutes"` // Minutes after a shift ends before its employees are judged
+}
+
+// UpdateAbsenteePolicyRequest defines the payload for updating a company's absentee policy.
+type UpdateAbsenteePolicyRequest struct {
+	GraceMinutes int `json:"grace_minutes" binding:"required,min=30,max=1440"`
+}
+
+// GetAbsenteePolicy returns the company's post-shift close time, falling back to the default.
+func (s *absenteeService) GetAbsenteePolicy(companyID int) (*AbsenteePolicy, error) {
+	policy, err := s.attendancePolicyRepo.GetAttendancePolicyForScope(companyID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance policy: %w", err)
+	}
+	absenteePolicy := &AbsenteePolicy{CompanyID: companyID, GraceMinutes: int(GracePeriodAfterShift.Minutes())}
+	if policy != nil && policy.CloseAfterShiftMinutes != nil {
+		absenteePolicy.GraceMinutes = *policy.CloseAfterShiftMinutes
+	}
+	return absenteePolicy, nil
+}
+
+// UpdateAbsenteePolicy sets the post-shift close time of the company attendance policy, leaving its other
+// rules as they are.
+func (s *absenteeService) UpdateAbsenteePolicy(companyID int, req UpdateAbsenteePolicyRequest) (*AbsenteePolicy, error) {
+	policy, err := s.attendancePolicyRepo.GetAttendancePolicyForScope(companyID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance policy: %w", err)
+	}
+	if policy == nil {
+		policy = &models.AttendancePolicy{CompanyID: companyID}
+	}
+
+	graceMinutes := req.GraceMinutes
+	policy.CloseAfterShiftMinutes = &graceMinutes
+
+	if err := s.attendancePolicyRepo.SaveAttendancePolicy(policy); err != nil {
+		return nil, fmt.Errorf("failed to save attendance policy: %w", err)
+	}
+
+	return &AbsenteePolicy{CompanyID: companyID, GraceMinutes: graceMinutes}, nil
+}
+
+func (s *absenteeService) GetAbsenteeRuns(companyID int, page, pageSize int) ([]models.AbsenteeRun, int64, error) {
+	return s.absenteeRepo.GetAbsenteeRunsPaginated(companyID, page, pageSize)
+}
+
+// GetLatestAbsenteeRuns returns the last run of every company, so operators can spot companies the job skipped.
+func (s *absenteeService) GetLatestAbsenteeRuns() ([]models.AbsenteeRun, error) {
+	return s.absenteeRepo.GetLatestAbsenteeRunPerCompany()
+}
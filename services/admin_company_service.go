@@ -212,9 +212,9 @@ func (s *adminCompanyService) GetDashboardSummaryData(companyID int) (map[string
 		for _, att := range overtimeAttendances {
 			if att.Employee.Name != "" {
 				description := ""
-				if att.Status == "overtime_in" {
+				if att.Status == models.AttendanceStatusOvertimeIn {
 					description = att.Employee.Name + " mulai lembur pada " + att.CheckInTime.Format("15:04")
-				} else if att.Status == "overtime_out" && att.CheckOutTime != nil {
+				} else if att.Status == models.AttendanceStatusOvertimeOut && att.CheckOutTime != nil {
 					description = att.Employee.Name + " selesai lembur pada " + att.CheckOutTime.Format("15:04")
 				}
 				if description != "" {
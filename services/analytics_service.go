@@ -0,0 +1,276 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Analytics limits and defaults.
+const (
+	MaxAnalyticsRangeDays     = 366
+	DefaultAnalyticsRangeDays = 30
+	DefaultAnalyticsTopN      = 5
+	MaxAnalyticsTopN          = 50
+)
+
+// AnalyticsService defines the interface for attendance analytics.
+type AnalyticsService interface {
+	GetAttendanceAnalytics(companyID int, req AttendanceAnalyticsRequest) (*AttendanceAnalytics, error)
+}
+
+// analyticsService is the concrete implementation of AnalyticsService.
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	companyRepo   repository.CompanyRepository
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsService.
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, companyRepo repository.CompanyRepository) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		companyRepo:   companyRepo,
+	}
+}
+
+// AttendanceAnalyticsRequest selects the range and granularity of the analytics. Dates are company-local and
+// inclusive; without them the last DefaultAnalyticsRangeDays days are returned.
+type AttendanceAnalyticsRequest struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Interval  string // "day", "week" (starting Monday) or "month"
+	TopN      int
+}
+
+// AttendanceMetrics are the attendance figures of one period or group. Rates are fractions between 0 and 1:
+// attendance and absence rates are shares of the days employees were expected, leave excluded, and the late
+// rate is the share of attended days with a late check-in.
+type AttendanceMetrics struct {
+	Key                string  `json:"key,omitempty"`
+	Label              string  `json:"label,omitempty"`
+	Attended           int     `json:"attended"`
+	Absent             int     `json:"absent"`
+	OnLeave            int     `json:"on_leave"`
+	Late               int     `json:"late"`
+	AttendanceRate     float64 `json:"attendance_rate"`
+	AbsenceRate        float64 `json:"absence_rate"`
+	LateRate           float64 `json:"late_rate"`
+	AverageLateMinutes float64 `json:"average_late_minutes"`
+	OvertimeHours      float64 `json:"overtime_hours"`
+}
+
+// EmployeeAttendanceMetrics are the attendance figures of one employee in a top-N list.
+type EmployeeAttendanceMetrics struct {
+	EmployeeID   int    `json:"employee_id"`
+	EmployeeName string `json:"employee_name"`
+	AttendanceMetrics
+}
+
+// AttendanceAnalytics is the attendance analytics of a company over a range.
+type AttendanceAnalytics struct {
+	StartDate    string                      `json:"start_date"`
+	EndDate      string                      `json:"end_date"`
+	Interval     string                      `json:"interval"`
+	Totals       AttendanceMetrics           `json:"totals"`
+	Series       []AttendanceMetrics         `json:"series"`
+	ByDivision   []AttendanceMetrics         `json:"by_division"`
+	ByShift      []AttendanceMetrics         `json:"by_shift"`
+	ByLocation   []AttendanceMetrics         `json:"by_location"` // Location of the accepted check-in; remote and unmatched check-ins are unassigned
+	ByWeekday    []AttendanceMetrics         `json:"by_weekday"`  // Keys are weekdays, 0 = Sunday
+	MostLate     []EmployeeAttendanceMetrics `json:"most_late"`
+	MostAbsent   []EmployeeAttendanceMetrics `json:"most_absent"`
+	MostOvertime []EmployeeAttendanceMetrics `json:"most_overtime"`
+}
+
+// GetAttendanceAnalytics aggregates a company's attendance over a range in the database and derives the rates.
+func (s *analyticsService) GetAttendanceAnalytics(companyID int, req AttendanceAnalyticsRequest) (*AttendanceAnalytics, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	companyLocation, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = repository.AnalyticsGroupDay
+	}
+	if interval != repository.AnalyticsGroupDay && interval != repository.AnalyticsGroupWeek && interval != repository.AnalyticsGroupMonth {
+		return nil, ErrInvalidAnalyticsInterval
+	}
+	topN := req.TopN
+	if topN <= 0 {
+		topN = DefaultAnalyticsTopN
+	}
+	if topN > MaxAnalyticsTopN {
+		topN = MaxAnalyticsTopN
+	}
+
+	today := time.Now().In(companyLocation)
+	endDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, companyLocation)
+	if req.EndDate != nil {
+		endDate = time.Date(req.EndDate.Year(), req.EndDate.Month(), req.EndDate.Day(), 0, 0, 0, 0, companyLocation)
+	}
+	startDate := endDate.AddDate(0, 0, -(DefaultAnalyticsRangeDays - 1))
+	if req.StartDate != nil {
+		startDate = time.Date(req.StartDate.Year(), req.StartDate.Month(), req.StartDate.Day(), 0, 0, 0, 0, companyLocation)
+	}
+	if endDate.Before(startDate) || endDate.Sub(startDate) >= MaxAnalyticsRangeDays*24*time.Hour {
+		return nil, ErrInvalidAnalyticsRange
+	}
+	rangeEnd := endDate.AddDate(0, 0, 1)
+
+	// Times are stored in the server's local time; shift them into the company's before deriving days.
+	_, companyOffset := startDate.Zone()
+	_, serverOffset := startDate.In(time.Local).Zone()
+	tzOffset := companyOffset - serverOffset
+
+	analytics := &AttendanceAnalytics{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Interval:  interval,
+	}
+
+	totals, err := s.analyticsRepo.GetAttendanceAggregates(companyID, startDate, rangeEnd, tzOffset, repository.AnalyticsGroupTotal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate attendance: %w", err)
+	}
+	if len(totals) > 0 {
+		analytics.Totals = newAttendanceMetrics(totals[0])
+		analytics.Totals.Key, analytics.Totals.Label = "", ""
+	}
+
+	series, err := s.analyticsRepo.GetAttendanceAggregates(companyID, startDate, rangeEnd, tzOffset, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate attendance by %s: %w", interval, err)
+	}
+	analytics.Series = fillAnalyticsSeries(series, startDate, endDate, interval)
+
+	breakdowns := []struct {
+		groupBy string
+		target  *[]AttendanceMetrics
+	}{
+		{repository.AnalyticsGroupDivision, &analytics.ByDivision},
+		{repository.AnalyticsGroupShift, &analytics.ByShift},
+		{repository.AnalyticsGroupLocation, &analytics.ByLocation},
+		{repository.AnalyticsGroupWeekday, &analytics.ByWeekday},
+	}
+	for _, breakdown := range breakdowns {
+		aggregates, err := s.analyticsRepo.GetAttendanceAggregates(companyID, startDate, rangeEnd, tzOffset, breakdown.groupBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate attendance by %s: %w", breakdown.groupBy, err)
+		}
+		metrics := make([]AttendanceMetrics, 0, len(aggregates))
+		for _, aggregate := range aggregates {
+			m := newAttendanceMetrics(aggregate)
+			if breakdown.groupBy == repository.AnalyticsGroupWeekday {
+				if weekday, err := strconv.Atoi(m.Key); err == nil {
+					m.Label = time.Weekday(weekday).String()
+				}
+			}
+			metrics = append(metrics, m)
+		}
+		*breakdown.target = metrics
+	}
+
+	rankings := []struct {
+		rankBy string
+		target *[]EmployeeAttendanceMetrics
+	}{
+		{repository.AnalyticsRankLate, &analytics.MostLate},
+		{repository.AnalyticsRankAbsent, &analytics.MostAbsent},
+		{repository.AnalyticsRankOvertime, &analytics.MostOvertime},
+	}
+	for _, ranking := range rankings {
+		aggregates, err := s.analyticsRepo.GetEmployeeAttendanceRanking(companyID, startDate, rangeEnd, tzOffset, ranking.rankBy, topN)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rank employees by %s: %w", ranking.rankBy, err)
+		}
+		employees := make([]EmployeeAttendanceMetrics, 0, len(aggregates))
+		for _, aggregate := range aggregates {
+			m := newAttendanceMetrics(aggregate.AttendanceAggregate)
+			m.Key, m.Label = "", ""
+			employees = append(employees, EmployeeAttendanceMetrics{EmployeeID: aggregate.EmployeeID, EmployeeName: aggregate.EmployeeName, AttendanceMetrics: m})
+		}
+		*ranking.target = employees
+	}
+
+	return analytics, nil
+}
+
+// newAttendanceMetrics derives the rates of an aggregate. Groups without a key are labelled "Unassigned".
+func newAttendanceMetrics(aggregate repository.AttendanceAggregate) AttendanceMetrics {
+	m := AttendanceMetrics{
+		Label:         "Unassigned",
+		Attended:      aggregate.Attended,
+		Absent:        aggregate.Absent,
+		OnLeave:       aggregate.OnLeave,
+		Late:          aggregate.Late,
+		OvertimeHours: roundTo(float64(aggregate.OvertimeMinutes)/60, 2),
+	}
+	if aggregate.Key != nil {
+		m.Key = *aggregate.Key
+	}
+	if aggregate.Label != nil {
+		m.Label = *aggregate.Label
+	}
+	if expected := aggregate.Attended + aggregate.Absent; expected > 0 {
+		m.AttendanceRate = roundTo(float64(aggregate.Attended)/float64(expected), 4)
+		m.AbsenceRate = roundTo(float64(aggregate.Absent)/float64(expected), 4)
+	}
+	if aggregate.Attended > 0 {
+		m.LateRate = roundTo(float64(aggregate.Late)/float64(aggregate.Attended), 4)
+	}
+	if aggregate.TimedLate > 0 {
+		m.AverageLateMinutes = roundTo(float64(aggregate.LateMinutes)/float64(aggregate.TimedLate), 2)
+	}
+	return m
+}
+
+// fillAnalyticsSeries returns one entry per period of the range, so that periods without any attendance show up
+// as zeros. Period keys follow the formats of the analytics repository.
+func fillAnalyticsSeries(aggregates []repository.AttendanceAggregate, startDate, endDate time.Time, interval string) []AttendanceMetrics {
+	byKey := make(map[string]repository.AttendanceAggregate, len(aggregates))
+	for _, aggregate := range aggregates {
+		if aggregate.Key != nil {
+			byKey[*aggregate.Key] = aggregate
+		}
+	}
+
+	var period time.Time
+	var key func(time.Time) string
+	var next func(time.Time) time.Time
+	switch interval {
+	case repository.AnalyticsGroupWeek:
+		period = startDate.AddDate(0, 0, -((int(startDate.Weekday()) + 6) % 7)) // Monday of the first week
+		key = func(t time.Time) string { return t.Format("2006-01-02") }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case repository.AnalyticsGroupMonth:
+		period = time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+		key = func(t time.Time) string { return t.Format("2006-01") }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		period = startDate
+		key = func(t time.Time) string { return t.Format("2006-01-02") }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	}
+
+	var series []AttendanceMetrics
+	for ; !period.After(endDate); period = next(period) {
+		periodKey := key(period)
+		aggregate := byKey[periodKey]
+		aggregate.Key, aggregate.Label = &periodKey, &periodKey
+		series = append(series, newAttendanceMetrics(aggregate))
+	}
+	return series
+}
+
+// roundTo rounds a value to the given number of decimals.
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Thresholds of the attendance anomaly detectors.
+const (
+	// AnomalyScanWindow is how much punch history each scan looks at.
+	AnomalyScanWindow = 28 * 24 * time.Hour
+
+	// BuddyPunchWindow is how close two employees' punches on one device must be to count as punched together.
+	BuddyPunchWindow = 60 * time.Second
+	// MinBuddyPunchDays is on how many days a pair must have punched together before it is reported.
+	MinBuddyPunchDays = 3
+	// MinBuddyPunchShare is the share of the less frequent employee's days at the device spent punching together.
+	MinBuddyPunchShare = 0.8
+
+	// MinIdenticalCoordinatePunches is how many GPS punches must report exactly the same position. Real
+	// receivers jitter, so repeated identical readings point to a replayed or spoofed location.
+	MinIdenticalCoordinatePunches = 3
+
+	// BorderlineFaceMargin is how close to the recognition threshold an accepted face distance is borderline.
+	BorderlineFaceMargin = 0.05
+
+	// PatternRecentWindow is the end of the scan window compared against the rest of it.
+	PatternRecentWindow = 7 * 24 * time.Hour
+	// MinPatternRecentDays and MinPatternBaselineDays are the check-in days needed on each side of the comparison.
+	MinPatternRecentDays   = 3
+	MinPatternBaselineDays = 8
+	// PatternCheckInShift is how far the usual check-in time must move to be reported.
+	PatternCheckInShift = 90 * time.Minute
+
+	// maxAnomalyEvidence caps the punches attached to one anomaly.
+	maxAnomalyEvidence = 50
+)
+
+// AttendanceAnomalyService defines the interface for detecting and reviewing suspicious attendance patterns.
+type AttendanceAnomalyService interface {
+	DetectAnomalies() ([]models.AttendanceAnomaly, error)
+	DetectCompanyAnomalies(companyID int, asOf time.Time) ([]models.AttendanceAnomaly, error)
+	GetAttendanceAnomalies(companyID int, status, kind, search string, page, pageSize int) ([]models.AttendanceAnomaly, int64, error)
+	ReviewAttendanceAnomaly(adminID uint, companyID int, anomalyID uint, req ReviewAttendanceAnomalyRequest) (*models.AttendanceAnomaly, error)
+}
+
+// attendanceAnomalyService is the concrete implementation of AttendanceAnomalyService.
+type attendanceAnomalyService struct {
+	attendanceAnomalyRepo repository.AttendanceAnomalyRepository
+	attendancePunchRepo   repository.AttendancePunchRepository
+	companyRepo           repository.CompanyRepository
+}
+
+// NewAttendanceAnomalyService creates a new instance of AttendanceAnomalyService.
+func NewAttendanceAnomalyService(attendanceAnomalyRepo repository.AttendanceAnomalyRepository, attendancePunchRepo repository.AttendancePunchRepository, companyRepo repository.CompanyRepository) AttendanceAnomalyService {
+	return &attendanceAnomalyService{
+		attendanceAnomalyRepo: attendanceAnomalyRepo,
+		attendancePunchRepo:   attendancePunchRepo,
+		companyRepo:           companyRepo,
+	}
+}
+
+// ReviewAttendanceAnomalyRequest defines the payload for confirming or dismissing an anomaly.
+type ReviewAttendanceAnomalyRequest struct {
+	Status string `json:"status" binding:"required,oneof=confirmed dismissed"`
+	Notes  string `json:"notes"`
+}
+
+// DetectAnomalies scans every active company and returns the anomalies that were new.
+func (s *attendanceAnomalyService) DetectAnomalies() ([]models.AttendanceAnomaly, error) {
+	companies, err := s.companyRepo.GetAllActiveCompanies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active companies: %w", err)
+	}
+
+	var detected []models.AttendanceAnomaly
+	now := time.Now()
+	for _, company := range companies {
+		anomalies, err := s.DetectCompanyAnomalies(company.ID, now)
+		if err != nil {
+			log.Printf("Error detecting attendance anomalies for company %d: %v", company.ID, err)
+			continue
+		}
+		detected = append(detected, anomalies...)
+	}
+	return detected, nil
+}
+
+// DetectCompanyAnomalies scans the company's punches of the AnomalyScanWindow before asOf and queues what it
+// finds for review. Findings already queued by an earlier scan are skipped: per-punch findings are reported
+// once, pattern findings at most once per ISO week.
+func (s *attendanceAnomalyService) DetectCompanyAnomalies(companyID int, asOf time.Time) ([]models.AttendanceAnomaly, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	companyLocation, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	asOf = asOf.In(companyLocation)
+	start := asOf.Add(-AnomalyScanWindow)
+
+	punches, err := s.attendancePunchRepo.GetAcceptedPunchesByCompany(companyID, start, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve punches: %w", err)
+	}
+	newDevicePunches, err := s.attendancePunchRepo.GetFirstPunchesFromNewDevices(companyID, start, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve new-device punches: %w", err)
+	}
+	for i := range punches {
+		punches[i].PunchedAt = punches[i].PunchedAt.In(companyLocation)
+	}
+
+	year, week := asOf.ISOWeek()
+	scanWeek := fmt.Sprintf("%d-W%02d", year, week)
+
+	var findings []models.AttendanceAnomaly
+	findings = append(findings, detectBuddyPunching(punches, scanWeek)...)
+	findings = append(findings, detectIdenticalCoordinates(punches, scanWeek)...)
+	findings = append(findings, detectBorderlineFaceScores(punches)...)
+	findings = append(findings, detectNewDevices(newDevicePunches)...)
+	findings = append(findings, detectPatternChanges(punches, asOf, scanWeek)...)
+
+	var created []models.AttendanceAnomaly
+	for _, anomaly := range findings {
+		anomaly.CompanyID = companyID
+		anomaly.Status = models.AnomalyStatusOpen
+		isNew, err := s.attendanceAnomalyRepo.CreateAttendanceAnomaly(&anomaly)
+		if err != nil {
+			return created, fmt.Errorf("failed to queue attendance anomaly: %w", err)
+		}
+		if isNew {
+			created = append(created, anomaly)
+		}
+	}
+	return created, nil
+}
+
+func (s *attendanceAnomalyService) GetAttendanceAnomalies(companyID int, status, kind, search string, page, pageSize int) ([]models.AttendanceAnomaly, int64, error) {
+	return s.attendanceAnomalyRepo.GetAttendanceAnomaliesPaginated(companyID, status, kind, search, page, pageSize)
+}
+
+// ReviewAttendanceAnomaly confirms or dismisses an open anomaly.
+func (s *attendanceAnomalyService) ReviewAttendanceAnomaly(adminID uint, companyID int, anomalyID uint, req ReviewAttendanceAnomalyRequest) (*models.AttendanceAnomaly, error) {
+	anomaly, err := s.attendanceAnomalyRepo.GetAttendanceAnomalyByID(anomalyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance anomaly: %w", err)
+	}
+	if anomaly == nil || anomaly.CompanyID != companyID {
+		return nil, ErrAttendanceAnomalyNotFound
+	}
+	if anomaly.Status != models.AnomalyStatusOpen {
+		return nil, ErrAttendanceAnomalyAlreadyReviewed
+	}
+
+	now := time.Now()
+	anomaly.Status = req.Status
+	anomaly.ReviewedBy = &adminID
+	anomaly.ReviewedAt = &now
+	anomaly.ReviewNotes = req.Notes
+
+	if err := s.attendanceAnomalyRepo.UpdateAttendanceAnomaly(anomaly); err != nil {
+		return nil, fmt.Errorf("failed to update attendance anomaly: %w", err)
+	}
+	return anomaly, nil
+}
+
+// detectBuddyPunching reports pairs of employees whose punches on the same kiosk or phone keep landing within
+// BuddyPunchWindow of each other, on most of the days either of them used that device.
+func detectBuddyPunching(punches []models.AttendancePunch, scanWeek string) []models.AttendanceAnomaly {
+	type pairKey struct {
+		device string
+		a, b   int
+	}
+	byDevice := make(map[string][]models.AttendancePunch)
+	for _, punch := range punches {
+		if punch.DeviceID != "" {
+			byDevice[punch.DeviceID] = append(byDevice[punch.DeviceID], punch)
+		}
+	}
+
+	pairDays := make(map[pairKey]map[string]bool)
+	pairPunches := make(map[pairKey]map[uint]bool)
+	employeeDays := make(map[string]map[int]map[string]bool)
+	for device, devicePunches := range byDevice {
+		employeeDays[device] = make(map[int]map[string]bool)
+		for i, punch := range devicePunches {
+			day := punch.PunchedAt.Format("2006-01-02")
+			if employeeDays[device][punch.EmployeeID] == nil {
+				employeeDays[device][punch.EmployeeID] = make(map[string]bool)
+			}
+			employeeDays[device][punch.EmployeeID][day] = true
+
+			for _, other := range devicePunches[i+1:] {
+				if other.PunchedAt.Sub(punch.PunchedAt) > BuddyPunchWindow {
+					break
+				}
+				if other.EmployeeID == punch.EmployeeID || other.Kind != punch.Kind {
+					continue
+				}
+				key := pairKey{device, punch.EmployeeID, other.EmployeeID}
+				if key.a > key.b {
+					key.a, key.b = key.b, key.a
+				}
+				if pairDays[key] == nil {
+					pairDays[key] = make(map[string]bool)
+					pairPunches[key] = make(map[uint]bool)
+				}
+				pairDays[key][day] = true
+				pairPunches[key][punch.ID] = true
+				pairPunches[key][other.ID] = true
+			}
+		}
+	}
+
+	var anomalies []models.AttendanceAnomaly
+	for key, days := range pairDays {
+		fewestDays := len(employeeDays[key.device][key.a])
+		if n := len(employeeDays[key.device][key.b]); n < fewestDays {
+			fewestDays = n
+		}
+		if len(days) < MinBuddyPunchDays || float64(len(days)) < MinBuddyPunchShare*float64(fewestDays) {
+			continue
+		}
+		related := key.b
+		anomalies = append(anomalies, models.AttendanceAnomaly{
+			EmployeeID:        key.a,
+			RelatedEmployeeID: &related,
+			Kind:              models.AnomalyKindBuddyPunching,
+			Summary: fmt.Sprintf("Punched within %s of each other on device %q on %d of %d days",
+				BuddyPunchWindow, key.device, len(days), fewestDays),
+			PunchIDs:    sortedPunchIDs(pairPunches[key]),
+			Fingerprint: fmt.Sprintf("%s:%s:%d:%d:%s", models.AnomalyKindBuddyPunching, key.device, key.a, key.b, scanWeek),
+		})
+	}
+	return anomalies
+}
+
+// detectIdenticalCoordinates reports employees whose GPS punches share exactly the same position with at least
+// MinIdenticalCoordinatePunches punches. Kiosk punches are skipped because kiosks report a fixed position.
+func detectIdenticalCoordinates(punches []models.AttendancePunch, scanWeek string) []models.AttendanceAnomaly {
+	byPosition := make(map[string][]models.AttendancePunch)
+	for _, punch := range punches {
+		if !isGPSProven(&punch) || strings.HasPrefix(punch.DeviceID, KioskPunchDevicePrefix) {
+			continue
+		}
+		position := strconv.FormatFloat(punch.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(punch.Longitude, 'f', -1, 64)
+		byPosition[position] = append(byPosition[position], punch)
+	}
+
+	var anomalies []models.AttendanceAnomaly
+	for position, positionPunches := range byPosition {
+		if len(positionPunches) < MinIdenticalCoordinatePunches {
+			continue
+		}
+		byEmployee := make(map[int]map[uint]bool)
+		for _, punch := range positionPunches {
+			if byEmployee[punch.EmployeeID] == nil {
+				byEmployee[punch.EmployeeID] = make(map[uint]bool)
+			}
+			byEmployee[punch.EmployeeID][punch.ID] = true
+		}
+		for employeeID, ids := range byEmployee {
+			summary := fmt.Sprintf("%d punches reported exactly the same position (%s)", len(ids), position)
+			if others := len(byEmployee) - 1; others > 0 {
+				summary += fmt.Sprintf(", also reported by %d other employee(s)", others)
+			}
+			anomalies = append(anomalies, models.AttendanceAnomaly{
+				EmployeeID:  employeeID,
+				Kind:        models.AnomalyKindIdenticalCoordinates,
+				Summary:     summary,
+				PunchIDs:    sortedPunchIDs(ids),
+				Fingerprint: fmt.Sprintf("%s:%d:%s:%s", models.AnomalyKindIdenticalCoordinates, employeeID, position, scanWeek),
+			})
+		}
+	}
+	return anomalies
+}
+
+// detectBorderlineFaceScores reports punches whose face was accepted within BorderlineFaceMargin of the threshold.
+func detectBorderlineFaceScores(punches []models.AttendancePunch) []models.AttendanceAnomaly {
+	var anomalies []models.AttendanceAnomaly
+	for _, punch := range punches {
+		if punch.FaceDistance == nil || punch.FaceThreshold == nil || *punch.FaceDistance < *punch.FaceThreshold-BorderlineFaceMargin {
+			continue
+		}
+		anomalies = append(anomalies, models.AttendanceAnomaly{
+			EmployeeID:  punch.EmployeeID,
+			Kind:        models.AnomalyKindBorderlineFaceScore,
+			Summary:     fmt.Sprintf("Face matched at distance %.3f against a threshold of %.3f", *punch.FaceDistance, *punch.FaceThreshold),
+			PunchIDs:    []uint{punch.ID},
+			Fingerprint: fmt.Sprintf("%s:%d", models.AnomalyKindBorderlineFaceScore, punch.ID),
+		})
+	}
+	return anomalies
+}
+
+// detectNewDevices reports the first punch of an employee from a phone they had not used before. Kiosks are
+// shared by design and skipped.
+func detectNewDevices(punches []models.AttendancePunch) []models.AttendanceAnomaly {
+	var anomalies []models.AttendanceAnomaly
+	for _, punch := range punches {
+		if strings.HasPrefix(punch.DeviceID, KioskPunchDevicePrefix) {
+			continue
+		}
+		anomalies = append(anomalies, models.AttendanceAnomaly{
+			EmployeeID:  punch.EmployeeID,
+			Kind:        models.AnomalyKindNewDevice,
+			Summary:     fmt.Sprintf("First punch from device %q", punch.DeviceID),
+			PunchIDs:    []uint{punch.ID},
+			Fingerprint: fmt.Sprintf("%s:%d", models.AnomalyKindNewDevice, punch.ID),
+		})
+	}
+	return anomalies
+}
+
+// detectPatternChanges compares each employee's check-ins of the last PatternRecentWindow with the rest of the
+// scan window and reports a usual check-in time that moved by PatternCheckInShift or more, or check-ins that
+// moved to locations the employee had not used.
+func detectPatternChanges(punches []models.AttendancePunch, asOf time.Time, scanWeek string) []models.AttendanceAnomaly {
+	type checkIn struct {
+		punch  models.AttendancePunch
+		minute float64
+	}
+	// The first regular punch of a day is the check-in
+	firstOfDay := make(map[int]map[string]checkIn)
+	for _, punch := range punches {
+		if punch.Kind != "regular" {
+			continue
+		}
+		day := punch.PunchedAt.Format("2006-01-02")
+		if firstOfDay[punch.EmployeeID] == nil {
+			firstOfDay[punch.EmployeeID] = make(map[string]checkIn)
+		}
+		if _, seen := firstOfDay[punch.EmployeeID][day]; !seen {
+			minute := float64(punch.PunchedAt.Hour()*60+punch.PunchedAt.Minute()) + float64(punch.PunchedAt.Second())/60
+			firstOfDay[punch.EmployeeID][day] = checkIn{punch, minute}
+		}
+	}
+
+	recentFrom := asOf.Add(-PatternRecentWindow)
+	var anomalies []models.AttendanceAnomaly
+	for employeeID, days := range firstOfDay {
+		var recent, baseline []checkIn
+		for _, c := range days {
+			if c.punch.PunchedAt.Before(recentFrom) {
+				baseline = append(baseline, c)
+			} else {
+				recent = append(recent, c)
+			}
+		}
+		if len(recent) < MinPatternRecentDays || len(baseline) < MinPatternBaselineDays {
+			continue
+		}
+
+		var changes []string
+		var recentMinutes, baselineMinutes []float64
+		for _, c := range recent {
+			recentMinutes = append(recentMinutes, c.minute)
+		}
+		for _, c := range baseline {
+			baselineMinutes = append(baselineMinutes, c.minute)
+		}
+		recentMean, baselineMean := mean(recentMinutes), mean(baselineMinutes)
+		if math.Abs(recentMean-baselineMean) >= PatternCheckInShift.Minutes() {
+			changes = append(changes, fmt.Sprintf("usual check-in moved from %s to %s", formatMinuteOfDay(baselineMean), formatMinuteOfDay(recentMean)))
+		}
+
+		usedLocations := make(map[uint]bool)
+		for _, c := range baseline {
+			if c.punch.LocationID != nil {
+				usedLocations[*c.punch.LocationID] = true
+			}
+		}
+		newLocationDays := 0
+		for _, c := range recent {
+			if c.punch.LocationID != nil && !usedLocations[*c.punch.LocationID] {
+				newLocationDays++
+			}
+		}
+		if len(usedLocations) > 0 && newLocationDays >= MinPatternRecentDays {
+			changes = append(changes, fmt.Sprintf("checked in at a location not used before on %d days", newLocationDays))
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+		ids := make(map[uint]bool, len(recent))
+		for _, c := range recent {
+			ids[c.punch.ID] = true
+		}
+		anomalies = append(anomalies, models.AttendanceAnomaly{
+			EmployeeID:  employeeID,
+			Kind:        models.AnomalyKindPatternChange,
+			Summary:     "In the last week, " + strings.Join(changes, "; "),
+			PunchIDs:    sortedPunchIDs(ids),
+			Fingerprint: fmt.Sprintf("%s:%d:%s", models.AnomalyKindPatternChange, employeeID, scanWeek),
+		})
+	}
+	return anomalies
+}
+
+// mean returns the average of values, which must not be empty.
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+// formatMinuteOfDay formats minutes since midnight as "15:04".
+func formatMinuteOfDay(minutes float64) string {
+	m := int(math.Round(minutes))
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+// sortedPunchIDs returns a set of punch IDs in ascending order, capped at maxAnomalyEvidence.
+func sortedPunchIDs(ids map[uint]bool) []uint {
+	sorted := make([]uint, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if len(sorted) > maxAnomalyEvidence {
+		sorted = sorted[:maxAnomalyEvidence]
+	}
+	return sorted
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// AttendanceCorrectionRequestService defines the interface for employee-initiated attendance corrections.
+type AttendanceCorrectionRequestService interface {
+	SubmitCorrectionRequest(employeeID uint, correctionType, proposedTimeStr, reason string, attachment *multipart.FileHeader) (*models.AttendanceCorrectionRequest, error)
+	GetMyCorrectionRequests(employeeID uint, startDate, endDate *time.Time) ([]models.AttendanceCorrectionRequest, error)
+	GetCompanyCorrectionRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendanceCorrectionRequest, int64, error)
+	ReviewCorrectionRequest(correctionRequestID, adminID uint, status, notes string) (*models.AttendanceCorrectionRequest, error)
+	CancelCorrectionRequest(correctionRequestID, employeeID uint) (*models.AttendanceCorrectionRequest, error)
+}
+
+// attendanceCorrectionRequestService is the concrete implementation of AttendanceCorrectionRequestService.
+type attendanceCorrectionRequestService struct {
+	correctionRequestRepo repository.AttendanceCorrectionRequestRepository
+	employeeRepo          repository.EmployeeRepository
+	companyRepo           repository.CompanyRepository
+	adminCompanyRepo      repository.AdminCompanyRepository
+	attendanceRepo        repository.AttendanceRepository
+	attendanceService     AttendanceService // Applies approved requests through the admin correction logic
+}
+
+// NewAttendanceCorrectionRequestService creates a new instance of AttendanceCorrectionRequestService.
+func NewAttendanceCorrectionRequestService(correctionRequestRepo repository.AttendanceCorrectionRequestRepository, employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, adminCompanyRepo repository.AdminCompanyRepository, attendanceRepo repository.AttendanceRepository, attendanceService AttendanceService) AttendanceCorrectionRequestService {
+	return &attendanceCorrectionRequestService{
+		correctionRequestRepo: correctionRequestRepo,
+		employeeRepo:          employeeRepo,
+		companyRepo:           companyRepo,
+		adminCompanyRepo:      adminCompanyRepo,
+		attendanceRepo:        attendanceRepo,
+		attendanceService:     attendanceService,
+	}
+}
+
+// SubmitCorrectionRequest records a correction request. The proposed time is given in the company's local time.
+func (s *attendanceCorrectionRequestService) SubmitCorrectionRequest(employeeID uint, correctionType, proposedTimeStr, reason string, attachment *multipart.FileHeader) (*models.AttendanceCorrectionRequest, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(int(employeeID))
+	if err != nil || employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(employee.CompanyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	proposedTime, err := time.ParseInLocation("2006-01-02 15:04", proposedTimeStr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposed time format. Use YYYY-MM-DD HH:MM")
+	}
+	if proposedTime.After(time.Now()) {
+		return nil, fmt.Errorf("proposed time cannot be in the future")
+	}
+
+	// A check-out correction needs a check-in on that day that was never closed.
+	if correctionType == "check_out" {
+		attendance, err := s.attendanceRepo.GetLatestAttendanceForDate(int(employeeID), proposedTime)
+		if err != nil {
+			return nil, ErrAttendanceRetrieval
+		}
+		if attendance == nil || attendance.CheckOutTime != nil {
+			return nil, ErrNoOpenAttendanceForCorrection
+		}
+		if !proposedTime.After(attendance.CheckInTime) {
+			return nil, fmt.Errorf("proposed check-out time must be after the check-in time")
+		}
+	}
+
+	var attachmentPath string
+	if attachment != nil {
+		subDir := filepath.Join("attendance_corrections", strconv.Itoa(employee.CompanyID), strconv.Itoa(int(employeeID)))
+		attachmentPath, err = helper.SaveUploadedFile(attachment, subDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save attachment: %w", err)
+		}
+	}
+
+	correctionRequest := &models.AttendanceCorrectionRequest{
+		EmployeeID:     employeeID,
+		CorrectionType: correctionType,
+		ProposedTime:   proposedTime,
+		Reason:         reason,
+		AttachmentPath: attachmentPath,
+		Status:         "pending",
+	}
+
+	if err := s.correctionRequestRepo.CreateCorrectionRequest(correctionRequest); err != nil {
+		return nil, fmt.Errorf("failed to submit correction request: %w", err)
+	}
+
+	return correctionRequest, nil
+}
+
+func (s *attendanceCorrectionRequestService) GetMyCorrectionRequests(employeeID uint, startDate, endDate *time.Time) ([]models.AttendanceCorrectionRequest, error) {
+	return s.correctionRequestRepo.GetCorrectionRequestsByEmployeeID(employeeID, startDate, endDate)
+}
+
+func (s *attendanceCorrectionRequestService) GetCompanyCorrectionRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.AttendanceCorrectionRequest, int64, error) {
+	return s.correctionRequestRepo.GetCompanyCorrectionRequestsPaginated(companyID, status, search, startDate, endDate, page, pageSize)
+}
+
+// ReviewCorrectionRequest approves or rejects a pending request. Approval applies the correction first,
+// so a correction that cannot be applied leaves the request pending.
+func (s *attendanceCorrectionRequestService) ReviewCorrectionRequest(correctionRequestID, adminID uint, status, notes string) (*models.AttendanceCorrectionRequest, error) {
+	correctionRequest, err := s.correctionRequestRepo.GetCorrectionRequestByID(correctionRequestID)
+	if err != nil || correctionRequest == nil {
+		return nil, ErrCorrectionRequestNotFound
+	}
+
+	adminCompany, err := s.adminCompanyRepo.GetAdminCompanyByID(int(adminID))
+	if err != nil || adminCompany == nil || adminCompany.CompanyID != correctionRequest.Employee.CompanyID {
+		return nil, ErrCorrectionRequestUnauthorized
+	}
+
+	if correctionRequest.Status != "pending" {
+		return nil, ErrCorrectionRequestNotPending
+	}
+
+	if status == "approved" {
+		attendance, err := s.attendanceService.CorrectAttendance(adminID, CorrectionRequest{
+			EmployeeID:     int(correctionRequest.EmployeeID),
+			CorrectionTime: correctionRequest.ProposedTime,
+			CorrectionType: correctionRequest.CorrectionType,
+			Notes:          fmt.Sprintf("Correction request #%d: %s", correctionRequest.ID, correctionRequest.Reason),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply correction: %w", err)
+		}
+		correctionRequest.AppliedAttendanceID = &attendance.ID
+	}
+
+	correctionRequest.Status = status
+	correctionRequest.ReviewNotes = notes
+	correctionRequest.ReviewedBy = &adminID
+	now := time.Now()
+	correctionRequest.ReviewedAt = &now
+
+	if err := s.correctionRequestRepo.UpdateCorrectionRequest(correctionRequest); err != nil {
+		return nil, fmt.Errorf("failed to update correction request status: %w", err)
+	}
+
+	return correctionRequest, nil
+}
+
+func (s *attendanceCorrectionRequestService) CancelCorrectionRequest(correctionRequestID, employeeID uint) (*models.AttendanceCorrectionRequest, error) {
+	correctionRequest, err := s.correctionRequestRepo.GetCorrectionRequestByID(correctionRequestID)
+	if err != nil || correctionRequest == nil {
+		return nil, ErrCorrectionRequestNotFound
+	}
+
+	if correctionRequest.EmployeeID != employeeID {
+		return nil, ErrCorrectionRequestUnauthorized
+	}
+
+	if correctionRequest.Status != "pending" {
+		return nil, fmt.Errorf("only pending correction requests can be cancelled")
+	}
+
+	correctionRequest.Status = "cancelled"
+	if err := s.correctionRequestRepo.UpdateCorrectionRequest(correctionRequest); err != nil {
+		return nil, fmt.Errorf("failed to cancel correction request: %w", err)
+	}
+
+	return correctionRequest, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"reflect"
+	"time"
+)
+
+// AttendanceHistoryService defines the interface for viewing and reverting the change history of attendance records.
+type AttendanceHistoryService interface {
+	GetAttendanceHistory(companyID, attendanceID int) (*AttendanceHistory, error)
+	RevertAttendance(companyID int, adminID uint, attendanceID int, req RevertAttendanceRequest) (*models.AttendancesTable, error)
+}
+
+// AttendanceHistory is an attendance record together with its versions, oldest first.
+type AttendanceHistory struct {
+	Attendance *models.AttendancesTable   `json:"attendance"`
+	Versions   []models.AttendanceVersion `json:"versions"`
+}
+
+// RevertAttendanceRequest defines the payload for restoring an attendance record to a previous version.
+type RevertAttendanceRequest struct {
+	Version int    `json:"version" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"required,min=10"`
+}
+
+// attendanceHistoryService is the concrete implementation of AttendanceHistoryService.
+type attendanceHistoryService struct {
+	attendanceRepo repository.AttendanceRepository
+}
+
+// NewAttendanceHistoryService creates a new instance of AttendanceHistoryService.
+func NewAttendanceHistoryService(attendanceRepo repository.AttendanceRepository) AttendanceHistoryService {
+	return &attendanceHistoryService{
+		attendanceRepo: attendanceRepo,
+	}
+}
+
+// GetAttendanceHistory returns the timeline of an attendance record belonging to the company.
+func (s *attendanceHistoryService) GetAttendanceHistory(companyID, attendanceID int) (*AttendanceHistory, error) {
+	attendance, err := s.getCompanyAttendance(companyID, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.attendanceRepo.GetAttendanceVersions(attendanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance history: %w", err)
+	}
+
+	return &AttendanceHistory{Attendance: attendance, Versions: versions}, nil
+}
+
+// RevertAttendance restores the values an attendance record had after the given version. The revert is
+// itself recorded as a new version, so it can be undone the same way.
+func (s *attendanceHistoryService) RevertAttendance(companyID int, adminID uint, attendanceID int, req RevertAttendanceRequest) (*models.AttendancesTable, error) {
+	attendance, err := s.getCompanyAttendance(companyID, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.attendanceRepo.GetAttendanceVersion(attendanceID, req.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance version: %w", err)
+	}
+	if version == nil || version.After == nil {
+		return nil, ErrAttendanceVersionNotFound
+	}
+
+	if reflect.DeepEqual(normalizeSnapshot(models.NewAttendanceSnapshot(attendance)), normalizeSnapshot(version.After)) {
+		return nil, ErrAttendanceAlreadyAtVersion
+	}
+
+	version.After.ApplyTo(attendance)
+	change := models.AttendanceChange{
+		ActorType: models.AttendanceActorAdmin,
+		ActorID:   &adminID,
+		Reason:    fmt.Sprintf("Reverted to version %d: %s", version.Version, req.Reason),
+		Action:    "revert",
+		Event:     models.AttendanceEventRevert,
+	}
+	if err := s.attendanceRepo.UpdateAttendance(attendance, change); err != nil {
+		return nil, fmt.Errorf("failed to revert attendance: %w", err)
+	}
+
+	return attendance, nil
+}
+
+// getCompanyAttendance loads an attendance record and makes sure it belongs to one of the company's employees.
+func (s *attendanceHistoryService) getCompanyAttendance(companyID, attendanceID int) (*models.AttendancesTable, error) {
+	attendance, err := s.attendanceRepo.GetAttendanceByID(attendanceID)
+	if err != nil {
+		return nil, ErrAttendanceRetrieval
+	}
+	if attendance == nil || attendance.Employee.CompanyID != companyID {
+		return nil, ErrAttendanceNotFound
+	}
+	return attendance, nil
+}
+
+// normalizeSnapshot reduces the snapshot's times to whole UTC seconds so values read back from the
+// database compare equal to the same values held in memory.
+func normalizeSnapshot(snapshot *models.AttendanceSnapshot) models.AttendanceSnapshot {
+	normalized := *snapshot
+	normalized.CheckInTime = snapshot.CheckInTime.UTC().Truncate(time.Second)
+	if snapshot.CheckOutTime != nil {
+		checkOut := snapshot.CheckOutTime.UTC().Truncate(time.Second)
+		normalized.CheckOutTime = &checkOut
+	}
+	return normalized
+}
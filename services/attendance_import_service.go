@@ -0,0 +1,581 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formats of legacy time clock logs the attendance import understands.
+const (
+	AttendanceImportFormatZKTeco = "zkteco" // attlog.dat exports: tab-separated device user ID, timestamp and device codes
+	AttendanceImportFormatCSV    = "csv"    // A header row naming a user ID column and a timestamp column, or date and time columns
+)
+
+// Attendance import limits.
+const (
+	MaxAttendanceImportFileSize = 20 << 20 // 20 MB
+	MaxAttendanceImportRows     = 200000
+
+	// DuplicatePunchWindow merges punches of one employee this close together, which devices register when a
+	// finger is held on the reader.
+	DuplicatePunchWindow = time.Minute
+)
+
+// Problems the attendance import reports for rows it leaves out.
+const (
+	ImportIssueInvalidRow      = "invalid_row"
+	ImportIssueUnknownEmployee = "unknown_employee"
+	ImportIssueNoShift         = "no_shift"
+	ImportIssueOutsideShift    = "outside_shift"
+	ImportIssueConflict        = "conflict"
+)
+
+// legacyTimestampLayouts are the timestamp formats accepted in CSV logs, tried in order.
+var legacyTimestampLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+}
+
+// AttendanceImportService defines the interface for importing punch logs from legacy time clocks.
+type AttendanceImportService interface {
+	ImportAttendanceLogs(adminID uint, companyID int, req AttendanceImportRequest) (*AttendanceImportReport, error)
+}
+
+// attendanceImportService is the concrete implementation of AttendanceImportService.
+type attendanceImportService struct {
+	employeeRepo         repository.EmployeeRepository
+	companyRepo          repository.CompanyRepository
+	attendanceRepo       repository.AttendanceRepository
+	divisionRepo         repository.DivisionRepository
+	shiftRepo            repository.ShiftRepository
+	attendancePolicyRepo repository.AttendancePolicyRepository
+}
+
+// NewAttendanceImportService creates a new instance of AttendanceImportService.
+func NewAttendanceImportService(employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, attendanceRepo repository.AttendanceRepository, divisionRepo repository.DivisionRepository, shiftRepo repository.ShiftRepository, attendancePolicyRepo repository.AttendancePolicyRepository) AttendanceImportService {
+	return &attendanceImportService{
+		employeeRepo:         employeeRepo,
+		companyRepo:          companyRepo,
+		attendanceRepo:       attendanceRepo,
+		divisionRepo:         divisionRepo,
+		shiftRepo:            shiftRepo,
+		attendancePolicyRepo: attendancePolicyRepo,
+	}
+}
+
+// AttendanceImportRequest is an uploaded punch log. Without a format, files ending in .dat or with
+// tab-separated lines are read as ZKTeco attlog exports and anything else as CSV.
+type AttendanceImportRequest struct {
+	FileName string
+	Data     []byte
+	Format   string // "zkteco", "csv" or empty to detect
+	DryRun   bool
+}
+
+// AttendanceImportIssue is a row the import leaves out, and why.
+type AttendanceImportIssue struct {
+	Row          int    `json:"row"`
+	DeviceUserID string `json:"device_user_id,omitempty"`
+	Timestamp    string `json:"timestamp,omitempty"`
+	Kind         string `json:"kind"` // One of the ImportIssue* constants
+	Message      string `json:"message"`
+}
+
+// ImportedAttendance is an attendance record paired from the punches of one employee on one shift day.
+type ImportedAttendance struct {
+	EmployeeID       int                     `json:"employee_id"`
+	EmployeeName     string                  `json:"employee_name"`
+	EmployeeIDNumber string                  `json:"employee_id_number"`
+	Date             string                  `json:"date"`
+	CheckInTime      time.Time               `json:"check_in_time"`
+	CheckOutTime     *time.Time              `json:"check_out_time"`
+	Status           models.AttendanceStatus `json:"status"`
+	Rows             []int                   `json:"rows"`
+	ReplacesAbsence  bool                    `json:"replaces_absence"`        // An absent record for the day is overwritten
+	AttendanceID     int                     `json:"attendance_id,omitempty"` // Zero in a dry run
+	Error            string                  `json:"error,omitempty"`
+}
+
+// AttendanceImportReport is the outcome of an import, or its preview in a dry run.
+type AttendanceImportReport struct {
+	FileName         string                  `json:"file_name"`
+	Format           string                  `json:"format"`
+	DryRun           bool                    `json:"dry_run"`
+	TotalRows        int                     `json:"total_rows"`
+	DuplicatePunches int                     `json:"duplicate_punches"`
+	Imported         int                     `json:"imported"` // Records created, or that would be created in a dry run
+	Flagged          int                     `json:"flagged"`  // Rows left out because of an issue
+	Failed           int                     `json:"failed"`
+	Records          []ImportedAttendance    `json:"records"`
+	Issues           []AttendanceImportIssue `json:"issues"`
+}
+
+// legacyPunch is one punch read from a time clock log.
+type legacyPunch struct {
+	Row    int
+	UserID string
+	Time   time.Time
+	Raw    string
+}
+
+// ImportAttendanceLogs reads a legacy time clock log, maps device user IDs to employee ID numbers and pairs each
+// employee's punches into one attendance record per day of their effective shift: the first punch is the
+// check-in and the last the check-out. Rows that cannot be matched to an employee or a shift day, and days that
+// already have attendance, are reported as issues and left out. A dry run only previews the result.
+func (s *attendanceImportService) ImportAttendanceLogs(adminID uint, companyID int, req AttendanceImportRequest) (*AttendanceImportReport, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	companyLocation, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	format := req.Format
+	if format == "" {
+		format = detectAttendanceImportFormat(req.FileName, req.Data)
+	}
+	report := &AttendanceImportReport{FileName: req.FileName, Format: format, DryRun: req.DryRun, Records: []ImportedAttendance{}, Issues: []AttendanceImportIssue{}}
+
+	var punches []legacyPunch
+	switch format {
+	case AttendanceImportFormatZKTeco:
+		punches, report.Issues, report.TotalRows = parseZKTecoAttlog(req.Data, companyLocation)
+	case AttendanceImportFormatCSV:
+		punches, report.Issues, report.TotalRows, err = parseLegacyCSV(req.Data, companyLocation)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidAttendanceImportFormat
+	}
+	if report.TotalRows > MaxAttendanceImportRows {
+		return nil, ErrAttendanceImportTooLarge
+	}
+
+	employees, err := s.employeeRepo.GetEmployeesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve employees: %w", err)
+	}
+	employeesByNumber := make(map[string]*models.EmployeesTable, len(employees)*2)
+	for i := range employees {
+		employee := &employees[i]
+		employeesByNumber[employee.EmployeeIDNumber] = employee
+		// Devices usually drop leading zeros, so "00123" is registered as user 123.
+		if trimmed := strings.TrimLeft(employee.EmployeeIDNumber, "0"); trimmed != "" {
+			if _, taken := employeesByNumber[trimmed]; !taken {
+				employeesByNumber[trimmed] = employee
+			}
+		}
+	}
+
+	now := time.Now()
+	punchesByEmployee := make(map[int][]legacyPunch)
+	var employeeIDs []int
+	for _, punch := range punches {
+		employee := employeesByNumber[punch.UserID]
+		if employee == nil {
+			employee = employeesByNumber[strings.TrimLeft(punch.UserID, "0")]
+		}
+		if employee == nil {
+			report.Issues = append(report.Issues, punch.issue(ImportIssueUnknownEmployee, "No employee has this employee ID number."))
+			continue
+		}
+		if punch.Time.After(now) {
+			report.Issues = append(report.Issues, punch.issue(ImportIssueInvalidRow, "The punch is in the future."))
+			continue
+		}
+		if _, seen := punchesByEmployee[employee.ID]; !seen {
+			employeeIDs = append(employeeIDs, employee.ID)
+		}
+		punchesByEmployee[employee.ID] = append(punchesByEmployee[employee.ID], punch)
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shifts: %w", err)
+	}
+	divisions, err := s.divisionRepo.GetDivisionsByCompanyID(uint(companyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve divisions: %w", err)
+	}
+	policies, err := loadAttendancePolicySet(s.attendancePolicyRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Ints(employeeIDs)
+	for _, employeeID := range employeeIDs {
+		var employee *models.EmployeesTable
+		for i := range employees {
+			if employees[i].ID == employeeID {
+				employee = &employees[i]
+				break
+			}
+		}
+		employeePunches := punchesByEmployee[employeeID]
+
+		shift := importEffectiveShift(employee, shifts, divisions)
+		if shift.ID == 0 {
+			for _, punch := range employeePunches {
+				report.Issues = append(report.Issues, punch.issue(ImportIssueNoShift, fmt.Sprintf("%s has no shift to pair punches with.", employee.Name)))
+			}
+			continue
+		}
+		rules := policies.rules(employee.DivisionID, shift)
+
+		records, issues, duplicates := pairLegacyPunches(employee, employeePunches, shift, rules, companyLocation)
+		report.Issues = append(report.Issues, issues...)
+		report.DuplicatePunches += duplicates
+
+		for _, record := range records {
+			existing, err := s.attendanceRepo.GetAttendancesForDate(employee.ID, record.CheckInTime)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check existing attendance: %w", err)
+			}
+			var absentRecord *models.AttendancesTable
+			conflict := false
+			for i := range existing {
+				switch {
+				case existing[i].Status.IsOvertime():
+				case existing[i].Status == models.AttendanceStatusAbsent:
+					absentRecord = &existing[i]
+				default:
+					conflict = true
+				}
+			}
+			if conflict {
+				for _, row := range record.Rows {
+					report.Issues = append(report.Issues, AttendanceImportIssue{Row: row, DeviceUserID: employee.EmployeeIDNumber, Kind: ImportIssueConflict, Message: fmt.Sprintf("%s already has attendance on %s.", employee.Name, record.Date)})
+				}
+				continue
+			}
+			record.ReplacesAbsence = absentRecord != nil
+
+			if !req.DryRun {
+				attendanceID, err := s.saveImportedAttendance(adminID, employee.ID, record, absentRecord, req.FileName)
+				if err != nil {
+					log.Printf("Failed to import attendance of employee %d on %s: %v", employee.ID, record.Date, err)
+					record.Error = err.Error()
+					report.Failed++
+					report.Records = append(report.Records, record)
+					continue
+				}
+				record.AttendanceID = attendanceID
+			}
+			report.Imported++
+			report.Records = append(report.Records, record)
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool { return report.Issues[i].Row < report.Issues[j].Row })
+	report.Flagged = len(report.Issues)
+	return report, nil
+}
+
+// saveImportedAttendance writes an imported record, overwriting the day's absent record if there is one.
+func (s *attendanceImportService) saveImportedAttendance(adminID uint, employeeID int, record ImportedAttendance, absentRecord *models.AttendancesTable, fileName string) (int, error) {
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employeeID)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	notes := fmt.Sprintf("Imported from %s.", fileName)
+	if record.CheckOutTime == nil {
+		notes = fmt.Sprintf("Imported from %s without a check-out.", fileName)
+	}
+	change := models.AttendanceChange{
+		ActorType: models.AttendanceActorAdmin,
+		ActorID:   &adminID,
+		Reason:    notes,
+		Event:     models.AttendanceEventImport,
+	}
+
+	if absentRecord != nil {
+		absentRecord.CheckInTime = record.CheckInTime
+		absentRecord.CheckOutTime = record.CheckOutTime
+		absentRecord.Status = record.Status
+		absentRecord.IsCorrection = false
+		absentRecord.Notes = notes
+		if err := s.attendanceRepo.UpdateAttendance(absentRecord, change); err != nil {
+			return 0, err
+		}
+		return absentRecord.ID, nil
+	}
+
+	attendance := &models.AttendancesTable{
+		EmployeeID:   employeeID,
+		CheckInTime:  record.CheckInTime,
+		CheckOutTime: record.CheckOutTime,
+		Status:       record.Status,
+		Notes:        notes,
+	}
+	if err := s.attendanceRepo.CreateAttendance(attendance, change); err != nil {
+		return 0, err
+	}
+	return attendance.ID, nil
+}
+
+// pairLegacyPunches groups an employee's punches by the shift day they fall in and turns each day into a
+// record. Punches within DuplicatePunchWindow of the previous one are dropped as duplicates.
+func pairLegacyPunches(employee *models.EmployeesTable, punches []legacyPunch, shift models.ShiftsTable, rules attendanceRules, companyLocation *time.Location) ([]ImportedAttendance, []AttendanceImportIssue, int) {
+	sort.SliceStable(punches, func(i, j int) bool { return punches[i].Time.Before(punches[j].Time) })
+
+	var issues []AttendanceImportIssue
+	duplicates := 0
+	type shiftDay struct {
+		shiftStart time.Time
+		punches    []legacyPunch
+	}
+	days := make(map[string]*shiftDay)
+	var dayKeys []string
+	var previous *legacyPunch
+	for i := range punches {
+		punch := punches[i]
+		if previous != nil && punch.Time.Sub(previous.Time) < DuplicatePunchWindow {
+			duplicates++
+			continue
+		}
+		previous = &punches[i]
+
+		startOfDay, shiftStart, ok := importShiftDay(punch.Time, shift, rules, companyLocation)
+		if !ok {
+			issues = append(issues, punch.issue(ImportIssueOutsideShift, fmt.Sprintf("The punch is outside %s's shift %s (%s-%s).", employee.Name, shift.Name, shift.StartTime, shift.EndTime)))
+			continue
+		}
+		key := startOfDay.Format("2006-01-02")
+		if days[key] == nil {
+			days[key] = &shiftDay{shiftStart: shiftStart}
+			dayKeys = append(dayKeys, key)
+		}
+		days[key].punches = append(days[key].punches, punch)
+	}
+
+	records := make([]ImportedAttendance, 0, len(dayKeys))
+	for _, key := range dayKeys {
+		day := days[key]
+		first, last := day.punches[0], day.punches[len(day.punches)-1]
+		record := ImportedAttendance{
+			EmployeeID:       employee.ID,
+			EmployeeName:     employee.Name,
+			EmployeeIDNumber: employee.EmployeeIDNumber,
+			Date:             key,
+			CheckInTime:      first.Time,
+		}
+		for _, punch := range day.punches {
+			record.Rows = append(record.Rows, punch.Row)
+		}
+		if len(day.punches) > 1 {
+			checkOut := last.Time
+			record.CheckOutTime = &checkOut
+		}
+		// A day without a check-out or shorter than the minimum worked time is incomplete. Otherwise a late
+		// check-in stays late, so imported days keep their lateness.
+		switch {
+		case record.CheckOutTime == nil:
+			record.Status = models.AttendanceStatusIncomplete
+		case rules.round(last.Time).Sub(rules.round(first.Time)) < rules.MinWorked:
+			record.Status = models.AttendanceStatusIncomplete
+		case rules.round(first.Time).After(day.shiftStart.Add(rules.LateThreshold)):
+			record.Status = models.AttendanceStatusLate
+		default:
+			record.Status = models.AttendanceStatusPresent
+		}
+		records = append(records, record)
+	}
+	return records, issues, duplicates
+}
+
+// importShiftDay finds the shift day a punch belongs to: from the earliest allowed check-in before the shift, at
+// least EarlyCheckInWindow as time clocks accept early punches, to the attendance policy's close time after it.
+// A punch in the morning after a night shift belongs to the previous day.
+func importShiftDay(t time.Time, shift models.ShiftsTable, rules attendanceRules, companyLocation *time.Location) (time.Time, time.Time, bool) {
+	t = t.In(companyLocation)
+	earlyCheckIn := rules.EarlyCheckIn
+	if earlyCheckIn < EarlyCheckInWindow {
+		earlyCheckIn = EarlyCheckInWindow
+	}
+	for _, offset := range []int{-1, 0} {
+		startOfDay := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, companyLocation)
+		shiftStart, shiftEnd, err := shiftBounds(startOfDay, shift)
+		if err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		if !t.Before(shiftStart.Add(-earlyCheckIn)) && t.Before(shiftEnd.Add(rules.CloseAfterShift)) {
+			return startOfDay, shiftStart, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// importEffectiveShift resolves the shift an employee works as check-ins do: the first shift of their division,
+// or their own shift when the division has none. A zero shift means none is assigned.
+func importEffectiveShift(employee *models.EmployeesTable, shifts []models.ShiftsTable, divisions []models.DivisionTable) models.ShiftsTable {
+	if employee.DivisionID != nil {
+		for _, division := range divisions {
+			if division.ID == uint(*employee.DivisionID) && len(division.Shifts) > 0 {
+				return division.Shifts[0]
+			}
+		}
+	}
+	if employee.ShiftID != nil {
+		for _, shift := range shifts {
+			if shift.ID == *employee.ShiftID {
+				return shift
+			}
+		}
+	}
+	return models.ShiftsTable{}
+}
+
+// issue reports a problem with the row of a punch.
+func (p legacyPunch) issue(kind, message string) AttendanceImportIssue {
+	return AttendanceImportIssue{Row: p.Row, DeviceUserID: p.UserID, Timestamp: p.Raw, Kind: kind, Message: message}
+}
+
+// detectAttendanceImportFormat guesses the format of a log from its file name and first line.
+func detectAttendanceImportFormat(fileName string, data []byte) string {
+	if strings.EqualFold(filepath.Ext(fileName), ".dat") {
+		return AttendanceImportFormatZKTeco
+	}
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	if strings.Contains(firstLine, "\t") && !strings.Contains(firstLine, ",") {
+		return AttendanceImportFormatZKTeco
+	}
+	return AttendanceImportFormatCSV
+}
+
+// parseZKTecoAttlog reads a ZKTeco attlog export. Each line holds the device user ID, the punch time and device
+// codes for the punch state and verification mode, which are ignored: devices are often left in the wrong state,
+// so punches are paired by time instead.
+func parseZKTecoAttlog(data []byte, companyLocation *time.Location) ([]legacyPunch, []AttendanceImportIssue, int) {
+	var punches []legacyPunch
+	var issues []AttendanceImportIssue
+	rows := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rows++
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			issues = append(issues, AttendanceImportIssue{Row: i + 1, Kind: ImportIssueInvalidRow, Message: "Expected a user ID and a timestamp separated by a tab."})
+			continue
+		}
+		punch := legacyPunch{Row: i + 1, UserID: strings.TrimSpace(fields[0]), Raw: strings.TrimSpace(fields[1])}
+		punchTime, err := time.ParseInLocation("2006-01-02 15:04:05", punch.Raw, companyLocation)
+		if err != nil || punch.UserID == "" {
+			issues = append(issues, punch.issue(ImportIssueInvalidRow, "Expected a user ID and a timestamp in YYYY-MM-DD HH:MM:SS format."))
+			continue
+		}
+		punch.Time = punchTime
+		punches = append(punches, punch)
+	}
+	return punches, issues, rows
+}
+
+// parseLegacyCSV reads a CSV log with a header row. The user ID column may be named employee_id_number,
+// employee_id, user_id, pin or id, and the punch time either comes from a timestamp (or datetime, punch_time)
+// column or from separate date and time columns. Comma and semicolon separators are accepted.
+func parseLegacyCSV(data []byte, companyLocation *time.Location) ([]legacyPunch, []AttendanceImportIssue, int, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Byte order mark written by Excel
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, 0, ErrInvalidAttendanceImportFile
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_", ".", "").Replace(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	findColumn := func(names ...string) int {
+		for _, name := range names {
+			if i, ok := columns[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	userColumn := findColumn("employee_id_number", "employee_id", "user_id", "userid", "pin", "id")
+	timestampColumn := findColumn("timestamp", "datetime", "date_time", "punch_time")
+	dateColumn, timeColumn := findColumn("date"), findColumn("time")
+	if userColumn < 0 || (timestampColumn < 0 && (dateColumn < 0 || timeColumn < 0)) {
+		return nil, nil, 0, ErrInvalidAttendanceImportFile
+	}
+
+	var punches []legacyPunch
+	var issues []AttendanceImportIssue
+	rows := 0
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rows++
+		if err != nil {
+			issues = append(issues, AttendanceImportIssue{Row: row, Kind: ImportIssueInvalidRow, Message: "The row is not valid CSV."})
+			continue
+		}
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		punch := legacyPunch{Row: row, UserID: field(userColumn)}
+		if timestampColumn >= 0 {
+			punch.Raw = field(timestampColumn)
+		} else {
+			punch.Raw = strings.TrimSpace(field(dateColumn) + " " + field(timeColumn))
+		}
+		if punch.UserID == "" {
+			issues = append(issues, punch.issue(ImportIssueInvalidRow, "The user ID is empty."))
+			continue
+		}
+		parsed := false
+		for _, layout := range legacyTimestampLayouts {
+			if punchTime, err := time.ParseInLocation(layout, punch.Raw, companyLocation); err == nil {
+				punch.Time = punchTime
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			issues = append(issues, punch.issue(ImportIssueInvalidRow, "The punch time is not in a supported format, e.g. YYYY-MM-DD HH:MM:SS."))
+			continue
+		}
+		punches = append(punches, punch)
+	}
+	return punches, issues, rows, nil
+}
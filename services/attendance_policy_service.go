@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"time"
+)
+
+// AttendancePolicyService defines the interface for company attendance rules and their division and shift overrides.
+type AttendancePolicyService interface {
+	GetAttendancePolicies(companyID int) ([]models.AttendancePolicy, error)
+	SaveAttendancePolicy(companyID int, req AttendancePolicyRequest) (*models.AttendancePolicy, error)
+	DeleteAttendancePolicy(companyID int, policyID uint) error
+}
+
+// attendancePolicyService is the concrete implementation of AttendancePolicyService.
+type attendancePolicyService struct {
+	attendancePolicyRepo repository.AttendancePolicyRepository
+	divisionRepo         repository.DivisionRepository
+	shiftRepo            repository.ShiftRepository
+}
+
+// NewAttendancePolicyService creates a new instance of AttendancePolicyService.
+func NewAttendancePolicyService(attendancePolicyRepo repository.AttendancePolicyRepository, divisionRepo repository.DivisionRepository, shiftRepo repository.ShiftRepository) AttendancePolicyService {
+	return &attendancePolicyService{
+		attendancePolicyRepo: attendancePolicyRepo,
+		divisionRepo:         divisionRepo,
+		shiftRepo:            shiftRepo,
+	}
+}
+
+// AttendancePolicyRequest defines the payload for saving the company policy, or a division or shift override
+// when DivisionID or ShiftID is set. Rules left empty are inherited.
+type AttendancePolicyRequest struct {
+	DivisionID             *uint   `json:"division_id"`
+	ShiftID                *int    `json:"shift_id"`
+	EarlyCheckInMinutes    *int    `json:"early_check_in_minutes" binding:"omitempty,min=0,max=720"`
+	LateThresholdMinutes   *int    `json:"late_threshold_minutes" binding:"omitempty,min=0,max=720"`
+	CloseAfterShiftMinutes *int    `json:"close_after_shift_minutes" binding:"omitempty,min=30,max=1440"`
+	MinWorkedMinutes       *int    `json:"min_worked_minutes" binding:"omitempty,min=0,max=1440"`
+	RoundingMinutes        *int    `json:"rounding_minutes" binding:"omitempty,min=0,max=60"`
+	RoundingMode           *string `json:"rounding_mode" binding:"omitempty,oneof=nearest up down"`
+	RequireFaceOnCheckOut  *bool   `json:"require_face_on_check_out"`
+}
+
+func (s *attendancePolicyService) GetAttendancePolicies(companyID int) ([]models.AttendancePolicy, error) {
+	return s.attendancePolicyRepo.GetAttendancePoliciesByCompanyID(companyID)
+}
+
+// SaveAttendancePolicy creates or replaces the policy of the requested scope.
+func (s *attendancePolicyService) SaveAttendancePolicy(companyID int, req AttendancePolicyRequest) (*models.AttendancePolicy, error) {
+	if req.DivisionID != nil && req.ShiftID != nil {
+		return nil, ErrInvalidAttendancePolicyScope
+	}
+	if req.DivisionID != nil {
+		division, err := s.divisionRepo.GetDivisionByID(*req.DivisionID)
+		if err != nil || division == nil || division.CompanyID != uint(companyID) {
+			return nil, ErrAttendancePolicyScopeNotFound
+		}
+	}
+	if req.ShiftID != nil {
+		shift, err := s.shiftRepo.GetShiftByID(*req.ShiftID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve shift: %w", err)
+		}
+		if shift == nil || shift.CompanyID != companyID {
+			return nil, ErrAttendancePolicyScopeNotFound
+		}
+	}
+
+	policy, err := s.attendancePolicyRepo.GetAttendancePolicyForScope(companyID, req.DivisionID, req.ShiftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance policy: %w", err)
+	}
+	if policy == nil {
+		policy = &models.AttendancePolicy{
+			CompanyID:  companyID,
+			DivisionID: req.DivisionID,
+			ShiftID:    req.ShiftID,
+		}
+	}
+
+	policy.EarlyCheckInMinutes = req.EarlyCheckInMinutes
+	policy.LateThresholdMinutes = req.LateThresholdMinutes
+	policy.CloseAfterShiftMinutes = req.CloseAfterShiftMinutes
+	policy.MinWorkedMinutes = req.MinWorkedMinutes
+	policy.RoundingMinutes = req.RoundingMinutes
+	policy.RoundingMode = req.RoundingMode
+	policy.RequireFaceOnCheckOut = req.RequireFaceOnCheckOut
+
+	if err := s.attendancePolicyRepo.SaveAttendancePolicy(policy); err != nil {
+		return nil, fmt.Errorf("failed to save attendance policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *attendancePolicyService) DeleteAttendancePolicy(companyID int, policyID uint) error {
+	policy, err := s.attendancePolicyRepo.GetAttendancePolicyByID(policyID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve attendance policy: %w", err)
+	}
+	if policy == nil || policy.CompanyID != companyID {
+		return ErrAttendancePolicyNotFound
+	}
+	return s.attendancePolicyRepo.DeleteAttendancePolicy(policyID)
+}
+
+// attendanceRules are the attendance rules in effect for one division and shift.
+type attendanceRules struct {
+	EarlyCheckIn          time.Duration
+	LateThreshold         time.Duration
+	CloseAfterShift       time.Duration
+	MinWorked             time.Duration
+	Rounding              time.Duration
+	RoundingMode          string
+	RequireFaceOnCheckOut bool
+}
+
+// apply overrides the rules a policy sets.
+func (r *attendanceRules) apply(policy *models.AttendancePolicy) {
+	if policy == nil {
+		return
+	}
+	if policy.EarlyCheckInMinutes != nil {
+		r.EarlyCheckIn = time.Duration(*policy.EarlyCheckInMinutes) * time.Minute
+	}
+	if policy.LateThresholdMinutes != nil {
+		r.LateThreshold = time.Duration(*policy.LateThresholdMinutes) * time.Minute
+	}
+	if policy.CloseAfterShiftMinutes != nil {
+		r.CloseAfterShift = time.Duration(*policy.CloseAfterShiftMinutes) * time.Minute
+	}
+	if policy.MinWorkedMinutes != nil {
+		r.MinWorked = time.Duration(*policy.MinWorkedMinutes) * time.Minute
+	}
+	if policy.RoundingMinutes != nil {
+		r.Rounding = time.Duration(*policy.RoundingMinutes) * time.Minute
+	}
+	if policy.RoundingMode != nil {
+		r.RoundingMode = *policy.RoundingMode
+	}
+	if policy.RequireFaceOnCheckOut != nil {
+		r.RequireFaceOnCheckOut = *policy.RequireFaceOnCheckOut
+	}
+}
+
+// round rounds a check-in or check-out time to the rounding interval, counted from local midnight so that
+// timezones with half-hour offsets round to whole local quarters. Stored times are never rounded.
+func (r attendanceRules) round(t time.Time) time.Time {
+	if r.Rounding <= 0 {
+		return t
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	switch r.RoundingMode {
+	case models.AttendanceRoundingDown:
+		offset = offset.Truncate(r.Rounding)
+	case models.AttendanceRoundingUp:
+		if truncated := offset.Truncate(r.Rounding); truncated < offset {
+			offset = truncated + r.Rounding
+		}
+	default:
+		offset = offset.Round(r.Rounding)
+	}
+	return midnight.Add(offset)
+}
+
+// attendancePolicySet holds a company's attendance policy and its overrides, keyed by scope.
+type attendancePolicySet struct {
+	company   *models.AttendancePolicy
+	divisions map[uint]*models.AttendancePolicy
+	shifts    map[int]*models.AttendancePolicy
+}
+
+// loadAttendancePolicySet loads a company's attendance policy and all of its overrides.
+func loadAttendancePolicySet(attendancePolicyRepo repository.AttendancePolicyRepository, companyID int) (*attendancePolicySet, error) {
+	policies, err := attendancePolicyRepo.GetAttendancePoliciesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendance policies: %w", err)
+	}
+
+	set := &attendancePolicySet{
+		divisions: make(map[uint]*models.AttendancePolicy),
+		shifts:    make(map[int]*models.AttendancePolicy),
+	}
+	for i := range policies {
+		policy := &policies[i]
+		switch {
+		case policy.ShiftID != nil:
+			set.shifts[*policy.ShiftID] = policy
+		case policy.DivisionID != nil:
+			set.divisions[*policy.DivisionID] = policy
+		default:
+			set.company = policy
+		}
+	}
+	return set, nil
+}
+
+// rules resolves the rules for an employee's division and shift. Without any policy, the shift's grace period
+// decides both how early check-in opens and lateness, as it did before attendance policies, and absentees are
+// judged GracePeriodAfterShift after the shift ends.
+func (p *attendancePolicySet) rules(divisionID *int, shift models.ShiftsTable) attendanceRules {
+	grace := time.Duration(shift.GracePeriodMinutes) * time.Minute
+	rules := attendanceRules{
+		EarlyCheckIn:          grace,
+		LateThreshold:         grace,
+		CloseAfterShift:       GracePeriodAfterShift,
+		RoundingMode:          models.AttendanceRoundingNearest,
+		RequireFaceOnCheckOut: true,
+	}
+	rules.apply(p.company)
+	if divisionID != nil {
+		rules.apply(p.divisions[uint(*divisionID)])
+	}
+	rules.apply(p.shifts[shift.ID])
+	return rules
+}
+
+// closeDelays returns every post-shift close time that can apply to a shift: the one without a division
+// and one per division override.
+func (p *attendancePolicySet) closeDelays(shift models.ShiftsTable) []time.Duration {
+	delays := []time.Duration{p.rules(nil, shift).CloseAfterShift}
+	for divisionID := range p.divisions {
+		id := int(divisionID)
+		delays = append(delays, p.rules(&id, shift).CloseAfterShift)
+	}
+	return delays
+}
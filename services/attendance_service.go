@@ -1,94 +1,185 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"go-face-auth/database/repository"
 	"go-face-auth/helper"
 	"go-face-auth/models"
+	"io"
 	"log"
+	"math"
+	"slices"
+	"strings"
 	"time"
-
-	"github.com/xuri/excelize/v2"
 )
 
 // Constants for attendance business rules
 const (
-	// EarlyCheckInWindow is how early before shift start an employee can check in.
+	// EarlyCheckInWindow is the outer limit on early check-ins from before attendance policies. Imported time
+	// clock punches this early still count as check-ins.
 	EarlyCheckInWindow = 90 * time.Minute // 1.5 hours
 
-	// GracePeriodAfterShift is the buffer after shift end before marking absent.
+	// GracePeriodAfterShift is the default buffer after shift end before marking absent.
 	GracePeriodAfterShift = 5 * time.Hour
+
+	// MaxPlausibleTravelSpeed is the fastest speed in km/h between consecutive punches that is not flagged.
+	MaxPlausibleTravelSpeed = 200.0
+
+	// MinTravelCheckDistance ignores GPS jitter: moves shorter than this many meters are never flagged.
+	MinTravelCheckDistance = 1000.0
+
+	// MaxDeviceClockSkew is how far the device clock may drift from the server before the punch is flagged.
+	MaxDeviceClockSkew = 10 * time.Minute
+)
+
+// Location flags stored on punches and attendance records for admin review.
+const (
+	LocationFlagImpossibleTravel = "impossible_travel"
+	LocationFlagDeviceClockSkew  = "device_clock_skew"
 )
 
 type AttendanceService interface {
 	HandleAttendance(req AttendanceRequest) (string, *models.EmployeesTable, time.Time, error)
-	HandleOvertimeCheckIn(req OvertimeAttendanceRequest) (*models.EmployeesTable, time.Time, error)
-	HandleOvertimeCheckOut(req OvertimeAttendanceRequest) (*models.EmployeesTable, time.Time, int, time.Time, error)
+	HandleOfflineAttendance(req AttendanceRequest, capturedAt time.Time, source string) (string, *models.EmployeesTable, time.Time, error)
+	HandleOvertimeCheckIn(req OvertimeAttendanceRequest) (*models.EmployeesTable, *models.AttendancesTable, error)
+	HandleOvertimeCheckOut(req OvertimeAttendanceRequest) (*models.EmployeesTable, *models.AttendancesTable, error)
+	AutoCloseOvertimeSessions() ([]models.AttendancesTable, error)
+	CloseOvertimeSession(adminID uint, companyID int, attendanceID int, req CloseOvertimeSessionRequest) (*models.AttendancesTable, error)
 	GetAttendancesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.AttendancesTable, int64, error)
-	ExportEmployeeAttendanceToExcel(employeeID int, startDate, endDate *time.Time) (*excelize.File, string, error)
-	ExportAllAttendancesToExcel(companyID int, startDate, endDate *time.Time) (*excelize.File, string, error)
-	ExportUnaccountedToExcel(companyID int, startDate, endDate *time.Time, search string) (*excelize.File, string, error)
-	ExportOvertimeToExcel(companyID int, startDate, endDate *time.Time, search string) (*excelize.File, string, error)
+	ExportEmployeeAttendance(employeeID int, startDate, endDate *time.Time) (*helper.Report, error)
+	ExportAllAttendances(companyID int, startDate, endDate *time.Time) (*helper.Report, error)
+	CountAllAttendances(companyID int, startDate, endDate *time.Time) (int64, error)
+	StreamAllAttendances(companyID int, startDate, endDate *time.Time, format string, w io.Writer) (int, error)
+	ExportUnaccounted(companyID int, startDate, endDate *time.Time, search string) (*helper.Report, error)
+	ExportOvertime(companyID int, startDate, endDate *time.Time, search string) (*helper.Report, error)
 	GetOvertimeAttendancesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.AttendancesTable, int64, error)
 	GetUnaccountedEmployeesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.EmployeesTable, int64, error)
 	GetEmployeeAttendances(employeeID int, startDate, endDate *time.Time) ([]models.AttendancesTable, error)
+	GetAttendancePunchesPaginated(companyID int, result string, flaggedOnly bool, search string, startDate, endDate *time.Time, page int, pageSize int) ([]models.AttendancePunch, int64, error)
 	CorrectAttendance(adminID uint, req CorrectionRequest) (*models.AttendancesTable, error)
 	MarkDailyAbsentees() error
+	BackfillAbsentees(req AbsenteeBackfillRequest) (*AbsenteeBackfillReport, error)
 }
 
 type attendanceService struct {
-	employeeRepo     repository.EmployeeRepository
-	companyRepo      repository.CompanyRepository
-	attendanceRepo   repository.AttendanceRepository
-	faceImageRepo    repository.FaceImageRepository
-	locationRepo     repository.AttendanceLocationRepository
-	leaveRequestRepo repository.LeaveRequestRepository
-	shiftRepo        repository.ShiftRepository
-	divisionRepo     repository.DivisionRepository
-	pythonClient     PythonServerClientInterface
-}
-
-func NewAttendanceService(employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, attendanceRepo repository.AttendanceRepository, faceImageRepo repository.FaceImageRepository, locationRepo repository.AttendanceLocationRepository, leaveRequestRepo repository.LeaveRequestRepository, shiftRepo repository.ShiftRepository, divisionRepo repository.DivisionRepository, pythonClient PythonServerClientInterface) AttendanceService {
+	employeeRepo         repository.EmployeeRepository
+	companyRepo          repository.CompanyRepository
+	attendanceRepo       repository.AttendanceRepository
+	faceImageRepo        repository.FaceImageRepository
+	locationRepo         repository.AttendanceLocationRepository
+	leaveRequestRepo     repository.LeaveRequestRepository
+	shiftRepo            repository.ShiftRepository
+	divisionRepo         repository.DivisionRepository
+	overtimeRequestRepo  repository.OvertimeRequestRepository
+	overtimePolicyRepo   repository.OvertimePolicyRepository
+	publicHolidayRepo    repository.PublicHolidayRepository
+	attendancePunchRepo  repository.AttendancePunchRepository
+	remoteWorkRepo       repository.RemoteWorkRepository
+	absenteeRepo         repository.AbsenteeRepository
+	attendancePolicyRepo repository.AttendancePolicyRepository
+	leaveTypeRepo        repository.LeaveTypeRepository
+	pythonClient         PythonServerClientInterface
+}
+
+func NewAttendanceService(employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, attendanceRepo repository.AttendanceRepository, faceImageRepo repository.FaceImageRepository, locationRepo repository.AttendanceLocationRepository, leaveRequestRepo repository.LeaveRequestRepository, shiftRepo repository.ShiftRepository, divisionRepo repository.DivisionRepository, overtimeRequestRepo repository.OvertimeRequestRepository, overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, attendancePunchRepo repository.AttendancePunchRepository, remoteWorkRepo repository.RemoteWorkRepository, absenteeRepo repository.AbsenteeRepository, attendancePolicyRepo repository.AttendancePolicyRepository, leaveTypeRepo repository.LeaveTypeRepository, pythonClient PythonServerClientInterface) AttendanceService {
 	return &attendanceService{
-		employeeRepo:     employeeRepo,
-		companyRepo:      companyRepo,
-		attendanceRepo:   attendanceRepo,
-		faceImageRepo:    faceImageRepo,
-		locationRepo:     locationRepo,
-		leaveRequestRepo: leaveRequestRepo,
-		shiftRepo:        shiftRepo,
-		divisionRepo:     divisionRepo,
-		pythonClient:     pythonClient,
+		employeeRepo:         employeeRepo,
+		companyRepo:          companyRepo,
+		attendanceRepo:       attendanceRepo,
+		faceImageRepo:        faceImageRepo,
+		locationRepo:         locationRepo,
+		leaveRequestRepo:     leaveRequestRepo,
+		shiftRepo:            shiftRepo,
+		divisionRepo:         divisionRepo,
+		overtimeRequestRepo:  overtimeRequestRepo,
+		overtimePolicyRepo:   overtimePolicyRepo,
+		publicHolidayRepo:    publicHolidayRepo,
+		attendancePunchRepo:  attendancePunchRepo,
+		remoteWorkRepo:       remoteWorkRepo,
+		absenteeRepo:         absenteeRepo,
+		attendancePolicyRepo: attendancePolicyRepo,
+		leaveTypeRepo:        leaveTypeRepo,
+		pythonClient:         pythonClient,
 	}
 }
 
 // AttendanceRequest represents the request body for attendance.
 type AttendanceRequest struct {
-	EmployeeID int     `json:"employee_id" binding:"required"`
-	Latitude   float64 `json:"latitude" binding:"required"`
-	Longitude  float64 `json:"longitude" binding:"required"`
-	ImageData  string  `json:"image_data" binding:"required"`
+	EmployeeID     int        `json:"employee_id" binding:"required"`
+	Latitude       float64    `json:"latitude" binding:"required"`
+	Longitude      float64    `json:"longitude" binding:"required"`
+	ImageData      string     `json:"image_data" binding:"required"`
+	Accuracy       *float64   `json:"accuracy"`         // Horizontal accuracy in meters reported by the device
+	Provider       string     `json:"provider"`         // Location provider, e.g. "gps", "network", "fused"
+	IsMockLocation bool       `json:"is_mock_location"` // Set when the OS reports the location as mocked
+	DeviceTime     *time.Time `json:"device_time"`      // Device clock when the location was read
+	WifiBSSIDs     []string   `json:"wifi_bssids"`      // Access points the device can currently see
+	QRCode         string     `json:"qr_code"`          // Code scanned from the screen at the location
+	DeviceID       string     `json:"device_id"`        // Stable identifier of the app installation or kiosk
 }
 
 // OvertimeAttendanceRequest represents the request body for overtime attendance.
 type OvertimeAttendanceRequest struct {
-	EmployeeID int     `json:"employee_id" binding:"required"`
-	Latitude   float64 `json:"latitude" binding:"required"`
-	Longitude  float64 `json:"longitude" binding:"required"`
-	ImageData  string  `json:"image_data" binding:"required"`
+	EmployeeID     int        `json:"employee_id" binding:"required"`
+	Latitude       float64    `json:"latitude" binding:"required"`
+	Longitude      float64    `json:"longitude" binding:"required"`
+	ImageData      string     `json:"image_data" binding:"required"`
+	Accuracy       *float64   `json:"accuracy"`
+	Provider       string     `json:"provider"`
+	IsMockLocation bool       `json:"is_mock_location"`
+	DeviceTime     *time.Time `json:"device_time"`
+	WifiBSSIDs     []string   `json:"wifi_bssids"`
+	QRCode         string     `json:"qr_code"`
+	DeviceID       string     `json:"device_id"`
+}
+
+// LocationEvidence is what the device reported about the position used for a punch.
+type LocationEvidence struct {
+	Latitude       float64
+	Longitude      float64
+	Accuracy       *float64
+	Provider       string
+	IsMockLocation bool
+	DeviceTime     *time.Time
+	WifiBSSIDs     []string
+	QRCode         string
+	DeviceID       string
+}
+
+func (r AttendanceRequest) locationEvidence() LocationEvidence {
+	return LocationEvidence{r.Latitude, r.Longitude, r.Accuracy, r.Provider, r.IsMockLocation, r.DeviceTime, r.WifiBSSIDs, r.QRCode, r.DeviceID}
+}
+
+func (r OvertimeAttendanceRequest) locationEvidence() LocationEvidence {
+	return LocationEvidence{r.Latitude, r.Longitude, r.Accuracy, r.Provider, r.IsMockLocation, r.DeviceTime, r.WifiBSSIDs, r.QRCode, r.DeviceID}
 }
 
 // --- Private helper methods to eliminate code duplication ---
 
 // verifyFaceRecognition performs face recognition against the employee's registered face images.
-func (s *attendanceService) verifyFaceRecognition(employeeID int, imageData string) error {
-	faceImages, err := s.faceImageRepo.GetFaceImagesByEmployeeID(employeeID)
+func (s *attendanceService) verifyFaceRecognition(employeeID int, imageData string) (*faceMatch, error) {
+	return verifyEmployeeFace(s.faceImageRepo, s.pythonClient, employeeID, imageData)
+}
+
+// faceMatch is how closely a recognized face matched the registered one, as reported by the recognition server.
+// A distance close to the threshold is a borderline match.
+type faceMatch struct {
+	Distance  float64
+	Threshold float64
+}
+
+// verifyEmployeeFace sends the captured image to the Python recognition server and compares it with the
+// employee's registered face. It is shared by every flow that needs a face-verified employee. The match is
+// nil when the server did not report its distance.
+func verifyEmployeeFace(faceImageRepo repository.FaceImageRepository, pythonClient PythonServerClientInterface, employeeID int, imageData string) (*faceMatch, error) {
+	faceImages, err := faceImageRepo.GetFaceImagesByEmployeeID(employeeID)
 	if err != nil {
 		log.Printf("Error getting face image from DB for employee %d: %v", employeeID, err)
-		return ErrFaceImageRetrieval
+		return nil, ErrFaceImageRetrieval
 	}
 	if len(faceImages) == 0 {
-		return ErrNoRegisteredFaceImages
+		return nil, ErrNoRegisteredFaceImages
 	}
 
 	pythonPayload := PythonRecognitionRequest{
@@ -96,18 +187,23 @@ func (s *attendanceService) verifyFaceRecognition(employeeID int, imageData stri
 		DBImagePath:     faceImages[0].ImagePath,
 	}
 
-	pythonResponse, err := s.pythonClient.SendToPythonServer(pythonPayload)
+	pythonResponse, err := pythonClient.SendToPythonServer(pythonPayload)
 	if err != nil {
 		log.Printf("Error communicating with Python server: %v", err)
-		return ErrFaceRecognitionUnavailable
+		return nil, ErrFaceRecognitionUnavailable
 	}
 
 	status, ok := pythonResponse["status"].(string)
 	if !ok || status != "recognized" {
-		return ErrFaceNotRecognized
+		return nil, ErrFaceNotRecognized
 	}
 
-	return nil
+	distance, hasDistance := pythonResponse["distance"].(float64)
+	threshold, hasThreshold := pythonResponse["threshold"].(float64)
+	if !hasDistance || !hasThreshold {
+		return nil, nil
+	}
+	return &faceMatch{Distance: distance, Threshold: threshold}, nil
 }
 
 // getCompanyTimezone loads the timezone for a given company.
@@ -181,31 +277,298 @@ func (s *attendanceService) resolveEffectiveShiftAndLocations(employee *models.E
 	return effectiveShift, effectiveLocations, nil
 }
 
-// validateLocation checks if the given coordinates are within any of the attendance locations.
-func (s *attendanceService) validateLocation(latitude, longitude float64, locations []models.AttendanceLocation) error {
+// resolveCheckInLocations determines the effective shift and the locations a check-in is validated against,
+// and whether the day is a remote work day. On remote work days the employee's approved home location is
+// accepted next to the office locations, or geofencing is skipped when the company's remote work policy says so.
+func (s *attendanceService) resolveCheckInLocations(employee *models.EmployeesTable, day time.Time) (models.ShiftsTable, []models.AttendanceLocation, bool, error) {
+	effectiveShift, effectiveLocations, err := s.resolveEffectiveShiftAndLocations(employee)
+	if err != nil && !errors.Is(err, ErrNoLocationsConfigured) {
+		return effectiveShift, nil, false, err
+	}
+
+	remote, remoteErr := isRemoteWorkDay(s.remoteWorkRepo, employee, day)
+	if remoteErr != nil {
+		log.Printf("Error checking remote work day for employee %d: %v", employee.ID, remoteErr)
+		return effectiveShift, nil, false, ErrLocationRetrieval
+	}
+	if !remote {
+		return effectiveShift, effectiveLocations, false, err
+	}
+
+	policy, err := loadRemoteWorkPolicy(s.remoteWorkRepo, employee.CompanyID)
+	if err != nil {
+		return effectiveShift, nil, true, ErrLocationRetrieval
+	}
+	if policy.GeofenceMode == models.RemoteGeofenceNone {
+		return effectiveShift, nil, true, nil
+	}
+
+	homeLocation, err := s.remoteWorkRepo.GetHomeLocationByEmployeeID(uint(employee.ID))
+	if err != nil {
+		return effectiveShift, nil, true, ErrLocationRetrieval
+	}
+	if homeLocation == nil || homeLocation.Status != "approved" {
+		return effectiveShift, nil, true, ErrNoApprovedHomeLocation
+	}
+	home := models.AttendanceLocation{
+		Name:      "Home",
+		Latitude:  homeLocation.Latitude,
+		Longitude: homeLocation.Longitude,
+		Radius:    policy.HomeRadius,
+		Shape:     models.LocationShapeCircle,
+	}
+	return effectiveShift, append(effectiveLocations, home), true, nil
+}
+
+// checkInWorkMode tags a check-in as remote when it was made on a remote work day away from the office locations.
+func checkInWorkMode(remoteDay bool, punch *models.AttendancePunch) string {
+	if remoteDay && punch.LocationID == nil {
+		return models.WorkModeRemote
+	}
+	return models.WorkModeOnsite
+}
+
+// checkInLocationError reports a failed geofence check on a remote work day against the home location.
+func checkInLocationError(remoteDay bool, err error) error {
+	if remoteDay && errors.Is(err, ErrOutsideAttendanceLocation) {
+		return ErrOutsideHomeLocation
+	}
+	return err
+}
+
+// locationMatch is the attendance location a punch was matched to and the proof that matched it.
+type locationMatch struct {
+	LocationID uint
+	Proof      string
+}
+
+// validateLocation checks that the reading is genuine and that the employee is at one of the attendance locations.
+// GPS is tried first; locations that accept them can also be proven with a whitelisted Wi-Fi access point or the
+// rotating QR code shown on site. A nil location list checks the reading only and returns no match.
+func (s *attendanceService) validateLocation(evidence LocationEvidence, locations []models.AttendanceLocation) (*locationMatch, error) {
+	if evidence.IsMockLocation || isMockLocationProvider(evidence.Provider) {
+		return nil, ErrMockLocationDetected
+	}
+	accuracy := 0.0
+	if evidence.Accuracy != nil {
+		accuracy = *evidence.Accuracy
+		if accuracy < 0 || math.IsNaN(accuracy) || math.IsInf(accuracy, 0) {
+			return nil, ErrInvalidLocationAccuracy
+		}
+	}
+
+	if locations == nil {
+		return nil, nil // No geofence applies to this punch
+	}
+
+	gpsErr := ErrOutsideAttendanceLocation
+	for _, loc := range locations {
+		if !loc.AcceptsProof(models.LocationProofGPS) {
+			continue
+		}
+		inside, covered := gpsWithinLocation(evidence, accuracy, loc)
+		if covered {
+			return &locationMatch{LocationID: loc.ID, Proof: models.LocationProofGPS}, nil
+		}
+		if inside {
+			gpsErr = ErrLocationAccuracyInsufficient
+		}
+	}
+
+	// GPS is unreliable indoors, so fall back to the other proofs the locations accept.
 	for _, loc := range locations {
-		distance := helper.HaversineDistance(latitude, longitude, loc.Latitude, loc.Longitude)
-		if distance <= float64(loc.Radius) {
-			return nil
+		if loc.AcceptsProof(models.LocationProofWiFi) && seesWhitelistedBSSID(evidence.WifiBSSIDs, loc.WifiBSSIDs) {
+			return &locationMatch{LocationID: loc.ID, Proof: models.LocationProofWiFi}, nil
+		}
+	}
+	if evidence.QRCode != "" {
+		now := time.Now()
+		for _, loc := range locations {
+			if loc.AcceptsProof(models.LocationProofQR) && helper.VerifyLocationQRCode(loc.QRSecret, evidence.QRCode, now) {
+				return &locationMatch{LocationID: loc.ID, Proof: models.LocationProofQR}, nil
+			}
+		}
+		return nil, ErrInvalidLocationQRCode
+	}
+	return nil, gpsErr
+}
+
+// gpsWithinLocation reports whether the reported position is inside the location and whether its whole accuracy
+// circle is. Polygon locations use a point-in-polygon test; circles use the distance from their centre.
+func gpsWithinLocation(evidence LocationEvidence, accuracy float64, loc models.AttendanceLocation) (inside, covered bool) {
+	if loc.Shape == models.LocationShapePolygon {
+		area, err := helper.ParseGeoJSONGeometry(loc.Geometry)
+		if err != nil {
+			log.Printf("Invalid geometry for attendance location %d: %v", loc.ID, err)
+			return false, false
+		}
+		if !area.Contains(evidence.Latitude, evidence.Longitude) {
+			return false, false
+		}
+		return true, area.DistanceToBoundary(evidence.Latitude, evidence.Longitude) >= accuracy
+	}
+	distance := helper.HaversineDistance(evidence.Latitude, evidence.Longitude, loc.Latitude, loc.Longitude)
+	if distance > float64(loc.Radius) {
+		return false, false
+	}
+	return true, distance+accuracy <= float64(loc.Radius)
+}
+
+// seesWhitelistedBSSID reports whether any access point the device can see is on the location's whitelist.
+func seesWhitelistedBSSID(seen, whitelist []string) bool {
+	for _, bssid := range seen {
+		normalized, err := helper.NormalizeBSSID(bssid)
+		if err != nil {
+			continue
+		}
+		if slices.Contains(whitelist, normalized) {
+			return true
 		}
 	}
-	return ErrOutsideAttendanceLocation
+	return false
+}
+
+// isMockLocationProvider recognises provider names used by fake-GPS apps and the Android test provider.
+func isMockLocationProvider(provider string) bool {
+	provider = strings.ToLower(provider)
+	return strings.Contains(provider, "mock") || strings.Contains(provider, "fake") || strings.Contains(provider, "test")
+}
+
+// assessPunch validates the location evidence of a punch attempt. Rejected attempts are recorded straight away;
+// accepted ones are returned with their review flags and recorded by recordAcceptedPunch once the attendance is saved.
+func (s *attendanceService) assessPunch(employeeID int, kind string, evidence LocationEvidence, face *faceMatch, at time.Time, locations []models.AttendanceLocation) (*models.AttendancePunch, error) {
+	punch := &models.AttendancePunch{
+		EmployeeID:     employeeID,
+		Kind:           kind,
+		PunchedAt:      at,
+		DeviceID:       evidence.DeviceID,
+		Latitude:       evidence.Latitude,
+		Longitude:      evidence.Longitude,
+		Accuracy:       evidence.Accuracy,
+		Provider:       evidence.Provider,
+		IsMockLocation: evidence.IsMockLocation,
+		DeviceTime:     evidence.DeviceTime,
+		WifiBSSIDs:     evidence.WifiBSSIDs,
+		Result:         "accepted",
+	}
+	if face != nil {
+		punch.FaceDistance = &face.Distance
+		punch.FaceThreshold = &face.Threshold
+	}
+
+	match, err := s.validateLocation(evidence, locations)
+	if err != nil {
+		punch.Result = "rejected"
+		punch.RejectionReason = err.Error()
+		if createErr := s.attendancePunchRepo.CreateAttendancePunch(punch); createErr != nil {
+			log.Printf("Failed to record rejected punch for employee %d: %v", employeeID, createErr)
+		}
+		return nil, err
+	}
+	if match != nil {
+		if match.LocationID != 0 { // Home locations are not attendance locations
+			punch.LocationID = &match.LocationID
+		}
+		punch.Proof = match.Proof
+	}
+
+	var flags []string
+	previous, err := s.attendancePunchRepo.GetLatestAcceptedPunchBefore(employeeID, at)
+	if err != nil {
+		log.Printf("Could not load previous punch for employee %d: %v", employeeID, err)
+	} else if previous != nil && isImpossibleTravel(previous, punch) {
+		flags = append(flags, LocationFlagImpossibleTravel)
+	}
+	if evidence.DeviceTime != nil {
+		skew := at.Sub(*evidence.DeviceTime)
+		if skew > MaxDeviceClockSkew || skew < -MaxDeviceClockSkew {
+			flags = append(flags, LocationFlagDeviceClockSkew)
+		}
+	}
+	punch.Flags = strings.Join(flags, ",")
+
+	return punch, nil
+}
+
+// recordAcceptedPunch stores an accepted punch against the attendance record it produced.
+func (s *attendanceService) recordAcceptedPunch(punch *models.AttendancePunch, attendanceID int) {
+	punch.AttendanceID = &attendanceID
+	if err := s.attendancePunchRepo.CreateAttendancePunch(punch); err != nil {
+		log.Printf("Failed to record punch for attendance %d: %v", attendanceID, err)
+	}
+}
+
+// isImpossibleTravel reports whether getting from the previous punch to this one needs an implausible speed.
+// Punches proven by Wi-Fi or QR code are skipped because their GPS position was not trusted.
+func isImpossibleTravel(previous, current *models.AttendancePunch) bool {
+	if !isGPSProven(previous) || !isGPSProven(current) {
+		return false
+	}
+	distance := helper.HaversineDistance(previous.Latitude, previous.Longitude, current.Latitude, current.Longitude)
+	if distance < MinTravelCheckDistance {
+		return false
+	}
+	elapsed := current.PunchedAt.Sub(previous.PunchedAt).Hours()
+	if elapsed <= 0 {
+		return true
+	}
+	return distance/1000/elapsed > MaxPlausibleTravelSpeed
+}
+
+func isGPSProven(punch *models.AttendancePunch) bool {
+	return punch.Proof == "" || punch.Proof == models.LocationProofGPS
+}
+
+// mergeLocationFlags adds new flags to the ones already stored on an attendance record.
+func mergeLocationFlags(existing, added string) string {
+	if added == "" {
+		return existing
+	}
+	if existing == "" {
+		return added
+	}
+	flags := strings.Split(existing, ",")
+	for _, flag := range strings.Split(added, ",") {
+		if !slices.Contains(flags, flag) {
+			flags = append(flags, flag)
+		}
+	}
+	return strings.Join(flags, ",")
 }
 
 // --- Main attendance handlers ---
 
 func (s *attendanceService) HandleAttendance(req AttendanceRequest) (string, *models.EmployeesTable, time.Time, error) {
+	return s.recordAttendanceAt(req, time.Now(), "")
+}
+
+// HandleOfflineAttendance records a check-in or check-out captured by an offline kiosk, applying the
+// leave, shift and location rules as of the time it was captured.
+func (s *attendanceService) HandleOfflineAttendance(req AttendanceRequest, capturedAt time.Time, source string) (string, *models.EmployeesTable, time.Time, error) {
+	return s.recordAttendanceAt(req, capturedAt, source)
+}
+
+// recordAttendanceAt performs a regular check-in or check-out at the given time. The source, when set,
+// is appended to the reason stored in the attendance history.
+func (s *attendanceService) recordAttendanceAt(req AttendanceRequest, at time.Time, source string) (string, *models.EmployeesTable, time.Time, error) {
 	employee, err := s.employeeRepo.GetEmployeeByID(req.EmployeeID)
 	if err != nil || employee == nil {
 		return "", nil, time.Time{}, ErrEmployeeNotFound
 	}
 
+	// Concurrent submissions for the same employee would otherwise both be treated as the check-in.
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employee.ID)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	defer unlock()
+
 	companyLocation, _, err := s.getCompanyTimezone(employee.CompanyID)
 	if err != nil {
 		return "", nil, time.Time{}, err
 	}
 
-	now := time.Now().In(companyLocation)
+	now := at.In(companyLocation)
 
 	// Check leave status
 	approvedLeave, err := s.leaveRequestRepo.IsEmployeeOnApprovedLeave(employee.ID, now)
@@ -214,37 +577,52 @@ func (s *attendanceService) HandleAttendance(req AttendanceRequest) (string, *mo
 		return "", nil, time.Time{}, ErrLeaveCheckFailed
 	}
 	if approvedLeave != nil {
-		leaveType := "cuti"
-		if approvedLeave.Type == "sakit" {
-			leaveType = "sakit"
-		}
-		return "", nil, time.Time{}, fmt.Errorf("anda sedang dalam pengajuan %s yang disetujui untuk hari ini", leaveType)
-	}
-
-	// Face recognition
-	if err := s.verifyFaceRecognition(req.EmployeeID, req.ImageData); err != nil {
-		return "", nil, time.Time{}, err
+		return "", nil, time.Time{}, fmt.Errorf("anda sedang dalam pengajuan %s yang disetujui untuk hari ini", approvedLeave.Type)
 	}
 
 	// Resolve shift and locations
-	effectiveShift, effectiveLocations, err := s.resolveEffectiveShiftAndLocations(employee)
+	effectiveShift, effectiveLocations, remoteDay, err := s.resolveCheckInLocations(employee, now)
 	if err != nil {
 		return "", nil, time.Time{}, err
 	}
-
-	// Validate location
-	if err := s.validateLocation(req.Latitude, req.Longitude, effectiveLocations); err != nil {
+	policies, err := loadAttendancePolicySet(s.attendancePolicyRepo, employee.CompanyID)
+	if err != nil {
 		return "", nil, time.Time{}, err
 	}
-
-	var message string
-	var status string
+	rules := policies.rules(employee.DivisionID, effectiveShift)
 
 	todaysAttendance, err := s.attendanceRepo.GetLatestAttendanceForDate(req.EmployeeID, now)
 	if err != nil {
 		return "", nil, time.Time{}, ErrAttendanceRetrieval
 	}
 
+	// Face recognition, which the attendance policy can waive for check-outs
+	checkingOut := todaysAttendance != nil && todaysAttendance.Status.IsOpen() && todaysAttendance.CheckOutTime == nil
+	var face *faceMatch
+	if !checkingOut || rules.RequireFaceOnCheckOut {
+		if face, err = s.verifyFaceRecognition(req.EmployeeID, req.ImageData); err != nil {
+			return "", nil, time.Time{}, err
+		}
+	}
+
+	// Validate location
+	punch, err := s.assessPunch(req.EmployeeID, "regular", req.locationEvidence(), face, now, effectiveLocations)
+	if err != nil {
+		return "", nil, time.Time{}, checkInLocationError(remoteDay, err)
+	}
+	workMode := checkInWorkMode(remoteDay, punch)
+
+	var message string
+	var status models.AttendanceStatus
+	var savedAttendance *models.AttendancesTable
+
+	// An absent record written by the daily job before a delayed offline upload arrived is replaced by the check-in.
+	var absentRecord *models.AttendancesTable
+	if todaysAttendance != nil && todaysAttendance.Status == models.AttendanceStatusAbsent {
+		absentRecord = todaysAttendance
+		todaysAttendance = nil
+	}
+
 	if todaysAttendance == nil {
 		// CASE 1: CHECK-IN
 		shiftStartToday, err := helper.ParseTime(now, effectiveShift.StartTime, companyLocation)
@@ -252,13 +630,13 @@ func (s *attendanceService) HandleAttendance(req AttendanceRequest) (string, *mo
 			log.Printf("Error parsing shift start time for early check-in: %v", err)
 			return "", nil, time.Time{}, ErrShiftValidationFailed
 		}
-		earliestCheckInTime := shiftStartToday.Add(-EarlyCheckInWindow)
+		earliestCheckInTime := shiftStartToday.Add(-rules.EarlyCheckIn)
 
 		if now.Before(earliestCheckInTime) {
 			return "", nil, time.Time{}, ErrTooEarlyForCheckIn
 		}
 
-		isWithinShift, err := helper.IsTimeWithinShift(now, effectiveShift.StartTime, effectiveShift.EndTime, effectiveShift.GracePeriodMinutes, companyLocation)
+		isWithinShift, err := helper.IsTimeWithinShift(now, effectiveShift.StartTime, effectiveShift.EndTime, int(rules.EarlyCheckIn.Minutes()), companyLocation)
 		if err != nil {
 			log.Printf("Error checking time within shift: %v", err)
 			return "", nil, time.Time{}, ErrShiftValidationFailed
@@ -267,131 +645,198 @@ func (s *attendanceService) HandleAttendance(req AttendanceRequest) (string, *mo
 			return "", nil, time.Time{}, ErrOutsideShiftHours
 		}
 
-		if now.After(shiftStartToday.Add(time.Duration(effectiveShift.GracePeriodMinutes) * time.Minute)) {
-			status = "late"
+		if rules.round(now).After(shiftStartToday.Add(rules.LateThreshold)) {
+			status = models.AttendanceStatusLate
 		} else {
-			status = "on_time"
+			status = models.AttendanceStatusOnTime
 		}
 
-		newAttendance := &models.AttendancesTable{
-			EmployeeID:  req.EmployeeID,
-			CheckInTime: now,
-			Status:      status,
+		if absentRecord != nil {
+			absentRecord.CheckInTime = now
+			absentRecord.Status = status
+			absentRecord.IsCorrection = false
+			absentRecord.Notes = ""
+			absentRecord.LocationFlags = punch.Flags
+			absentRecord.CheckInProof = punch.Proof
+			absentRecord.WorkMode = workMode
+			err = s.attendanceRepo.UpdateAttendance(absentRecord, employeeAttendanceChange(req.EmployeeID, models.AttendanceEventCheckIn, attendanceReason("Check-in", source)))
+			savedAttendance = absentRecord
+		} else {
+			newAttendance := &models.AttendancesTable{
+				EmployeeID:    req.EmployeeID,
+				CheckInTime:   now,
+				Status:        status,
+				LocationFlags: punch.Flags,
+				CheckInProof:  punch.Proof,
+				WorkMode:      workMode,
+			}
+			err = s.attendanceRepo.CreateAttendance(newAttendance, employeeAttendanceChange(req.EmployeeID, models.AttendanceEventCheckIn, attendanceReason("Check-in", source)))
+			savedAttendance = newAttendance
 		}
-		err = s.attendanceRepo.CreateAttendance(newAttendance)
 		message = "Check-in successful!"
 
-	} else if todaysAttendance.CheckOutTime == nil {
+	} else if todaysAttendance.Status.IsOpen() && todaysAttendance.CheckOutTime == nil {
 		// CASE 2: CHECK-OUT
+		if !now.After(todaysAttendance.CheckInTime) {
+			return "", nil, time.Time{}, ErrCheckOutBeforeCheckIn
+		}
 		todaysAttendance.CheckOutTime = &now
-		todaysAttendance.Status = "present"
-		err = s.attendanceRepo.UpdateAttendance(todaysAttendance)
+		todaysAttendance.Status = models.AttendanceStatusPresent
+		if rules.round(now).Sub(rules.round(todaysAttendance.CheckInTime)) < rules.MinWorked {
+			todaysAttendance.Status = models.AttendanceStatusIncomplete
+		}
+		todaysAttendance.LocationFlags = mergeLocationFlags(todaysAttendance.LocationFlags, punch.Flags)
+		err = s.attendanceRepo.UpdateAttendance(todaysAttendance, employeeAttendanceChange(req.EmployeeID, models.AttendanceEventCheckOut, attendanceReason("Check-out", source)))
+		savedAttendance = todaysAttendance
 		message = "Check-out successful!"
 
+	} else if todaysAttendance.Status == models.AttendanceStatusOvertimeIn {
+		// CASE 3: AN OVERTIME SESSION IS STILL OPEN
+		return "", nil, time.Time{}, ErrMustCheckOutOvertime
 	} else {
-		// CASE 3: ALREADY DONE
+		// CASE 4: ALREADY DONE, including days the scheduler closed as incomplete
 		return "", nil, time.Time{}, ErrAlreadyCheckedOut
 	}
 
 	if err != nil {
 		return "", nil, time.Time{}, fmt.Errorf("failed to record attendance: %w", err)
 	}
+	s.recordAcceptedPunch(punch, savedAttendance.ID)
 
 	return message, employee, now, nil
 }
 
 
-func (s *attendanceService) HandleOvertimeCheckIn(req OvertimeAttendanceRequest) (*models.EmployeesTable, time.Time, error) {
+func (s *attendanceService) HandleOvertimeCheckIn(req OvertimeAttendanceRequest) (*models.EmployeesTable, *models.AttendancesTable, error) {
 	employee, err := s.employeeRepo.GetEmployeeByID(req.EmployeeID)
 	if err != nil || employee == nil {
-		return nil, time.Time{}, ErrEmployeeNotFound
+		return nil, nil, ErrEmployeeNotFound
 	}
 
-	companyLocation, _, err := s.getCompanyTimezone(employee.CompanyID)
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employee.ID)
 	if err != nil {
-		return nil, time.Time{}, err
+		return nil, nil, err
+	}
+	defer unlock()
+
+	companyLocation, company, err := s.getCompanyTimezone(employee.CompanyID)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	now := time.Now().In(companyLocation)
 
-	if err := s.verifyFaceRecognition(req.EmployeeID, req.ImageData); err != nil {
-		return nil, time.Time{}, err
+	// Overtime must be pre-approved when the company requires it; otherwise it is recorded but flagged.
+	overtimeRequest, err := s.overtimeRequestRepo.GetApprovedOvertimeRequestForDate(req.EmployeeID, now)
+	if err != nil {
+		return nil, nil, ErrAttendanceRetrieval
+	}
+	if overtimeRequest == nil && company.OvertimeRequiresApproval {
+		return nil, nil, ErrNoApprovedOvertimeRequest
 	}
 
-	effectiveShift, effectiveLocations, err := s.resolveEffectiveShiftAndLocations(employee)
+	face, err := s.verifyFaceRecognition(req.EmployeeID, req.ImageData)
 	if err != nil {
-		return nil, time.Time{}, err
+		return nil, nil, err
 	}
 
-	if err := s.validateLocation(req.Latitude, req.Longitude, effectiveLocations); err != nil {
-		return nil, time.Time{}, err
+	effectiveShift, effectiveLocations, remoteDay, err := s.resolveCheckInLocations(employee, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	punch, err := s.assessPunch(req.EmployeeID, "overtime_in", req.locationEvidence(), face, now, effectiveLocations)
+	if err != nil {
+		return nil, nil, checkInLocationError(remoteDay, err)
 	}
 
 	// Validate: Cannot check-in for overtime if within regular shift hours
 	isWithinShift, err := helper.IsTimeWithinShift(now, effectiveShift.StartTime, effectiveShift.EndTime, effectiveShift.GracePeriodMinutes, companyLocation)
 	if err != nil {
 		log.Printf("Error checking time within shift for overtime check-in: %v", err)
-		return nil, time.Time{}, ErrShiftValidationFailed
+		return nil, nil, ErrShiftValidationFailed
 	}
 	if isWithinShift {
-		return nil, time.Time{}, ErrOvertimeDuringShift
+		return nil, nil, ErrOvertimeDuringShift
 	}
 
 	// Check if employee has an open regular check-in
 	latestRegularAttendance, err := s.attendanceRepo.GetLatestAttendanceByEmployeeID(req.EmployeeID)
 	if err != nil {
-		return nil, time.Time{}, ErrAttendanceRetrieval
+		return nil, nil, ErrAttendanceRetrieval
 	}
-	if latestRegularAttendance != nil && latestRegularAttendance.CheckOutTime == nil && latestRegularAttendance.Status != "overtime_in" && latestRegularAttendance.Status != "overtime_out" {
-		return nil, time.Time{}, ErrMustCheckOutRegular
+	if latestRegularAttendance != nil && latestRegularAttendance.CheckOutTime == nil && !latestRegularAttendance.Status.IsOvertime() {
+		return nil, nil, ErrMustCheckOutRegular
 	}
 
 	// Check if employee is already checked in for overtime
 	latestOvertimeAttendance, err := s.attendanceRepo.GetLatestOvertimeAttendanceByEmployeeID(req.EmployeeID)
 	if err != nil {
-		return nil, time.Time{}, ErrAttendanceRetrieval
+		return nil, nil, ErrAttendanceRetrieval
 	}
-	if latestOvertimeAttendance != nil && latestOvertimeAttendance.CheckOutTime == nil && latestOvertimeAttendance.Status == "overtime_in" {
-		return nil, time.Time{}, ErrAlreadyCheckedInOvertime
+	if latestOvertimeAttendance != nil && latestOvertimeAttendance.CheckOutTime == nil && latestOvertimeAttendance.Status == models.AttendanceStatusOvertimeIn {
+		return nil, nil, ErrAlreadyCheckedInOvertime
 	}
 
 	newOvertimeAttendance := &models.AttendancesTable{
-		EmployeeID:  req.EmployeeID,
-		CheckInTime: now,
-		Status:      "overtime_in", // Specific status for overtime check-in
+		EmployeeID:    req.EmployeeID,
+		CheckInTime:   now,
+		Status:        models.AttendanceStatusOvertimeIn,
+		LocationFlags: punch.Flags,
+		CheckInProof:  punch.Proof,
+		WorkMode:      checkInWorkMode(remoteDay, punch),
 	}
-	err = s.attendanceRepo.CreateAttendance(newOvertimeAttendance)
+	if overtimeRequest != nil {
+		newOvertimeAttendance.OvertimeRequestID = &overtimeRequest.ID
+	} else {
+		newOvertimeAttendance.IsOvertimeUnapproved = true
+	}
+	err = s.attendanceRepo.CreateAttendance(newOvertimeAttendance, employeeAttendanceChange(req.EmployeeID, models.AttendanceEventOvertimeCheckIn, "Overtime check-in"))
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to record overtime check-in: %w", err)
+		return nil, nil, fmt.Errorf("failed to record overtime check-in: %w", err)
 	}
+	s.recordAcceptedPunch(punch, newOvertimeAttendance.ID)
 
-	return employee, now, nil
+	return employee, newOvertimeAttendance, nil
 }
 
-func (s *attendanceService) HandleOvertimeCheckOut(req OvertimeAttendanceRequest) (*models.EmployeesTable, time.Time, int, time.Time, error) {
+func (s *attendanceService) HandleOvertimeCheckOut(req OvertimeAttendanceRequest) (*models.EmployeesTable, *models.AttendancesTable, error) {
 	employee, err := s.employeeRepo.GetEmployeeByID(req.EmployeeID)
 	if err != nil || employee == nil {
-		return nil, time.Time{}, 0, time.Time{}, ErrEmployeeNotFound
+		return nil, nil, ErrEmployeeNotFound
 	}
 
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employee.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
 	companyLocation, _, err := s.getCompanyTimezone(employee.CompanyID)
 	if err != nil {
-		return nil, time.Time{}, 0, time.Time{}, err
+		return nil, nil, err
 	}
 
 	now := time.Now().In(companyLocation)
 
-	if err := s.verifyFaceRecognition(req.EmployeeID, req.ImageData); err != nil {
-		return nil, time.Time{}, 0, time.Time{}, err
+	face, err := s.verifyFaceRecognition(req.EmployeeID, req.ImageData)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Find the latest "overtime_in" record that is not checked out
+	// Find the latest overtime_in record that is not checked out
 	latestOvertimeAttendance, err := s.attendanceRepo.GetLatestOvertimeAttendanceByEmployeeID(req.EmployeeID)
 	if err != nil {
-		return nil, time.Time{}, 0, time.Time{}, ErrAttendanceRetrieval
+		return nil, nil, ErrAttendanceRetrieval
+	}
+	if latestOvertimeAttendance == nil || latestOvertimeAttendance.CheckOutTime != nil || latestOvertimeAttendance.Status != models.AttendanceStatusOvertimeIn {
+		return nil, nil, ErrNotCheckedInForOvertime
 	}
-	if latestOvertimeAttendance == nil || latestOvertimeAttendance.CheckOutTime != nil || latestOvertimeAttendance.Status != "overtime_in" {
-		return nil, time.Time{}, 0, time.Time{}, ErrNotCheckedInForOvertime
+
+	// Overtime check-out is not tied to a site, so only the reading itself is checked.
+	punch, err := s.assessPunch(req.EmployeeID, "overtime_out", req.locationEvidence(), face, now, nil)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	overtimeDuration := now.Sub(latestOvertimeAttendance.CheckInTime)
@@ -399,231 +844,352 @@ func (s *attendanceService) HandleOvertimeCheckOut(req OvertimeAttendanceRequest
 
 	latestOvertimeAttendance.CheckOutTime = &now
 	latestOvertimeAttendance.OvertimeMinutes = overtimeMinutes
-	latestOvertimeAttendance.Status = "overtime_out" // Specific status for overtime check-out
+	latestOvertimeAttendance.Status = models.AttendanceStatusOvertimeOut
+	latestOvertimeAttendance.ApprovedOvertimeMinutes = s.calculateApprovedOvertimeMinutes(latestOvertimeAttendance)
+	latestOvertimeAttendance.LocationFlags = mergeLocationFlags(latestOvertimeAttendance.LocationFlags, punch.Flags)
 
-	err = s.attendanceRepo.UpdateAttendance(latestOvertimeAttendance)
+	err = s.attendanceRepo.UpdateAttendance(latestOvertimeAttendance, employeeAttendanceChange(req.EmployeeID, models.AttendanceEventOvertimeCheckOut, "Overtime check-out"))
 	if err != nil {
-		return nil, time.Time{}, 0, time.Time{}, fmt.Errorf("failed to record overtime check-out: %w", err)
+		return nil, nil, fmt.Errorf("failed to record overtime check-out: %w", err)
 	}
+	s.recordAcceptedPunch(punch, latestOvertimeAttendance.ID)
 
-	return employee, now, overtimeMinutes, latestOvertimeAttendance.CheckInTime, nil
+	return employee, latestOvertimeAttendance, nil
 }
 
-func (s *attendanceService) GetAttendancesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.AttendancesTable, int64, error) {
-	return s.attendanceRepo.GetAttendancesPaginated(companyID, startDate, endDate, search, page, pageSize)
+// CloseOvertimeSessionRequest defines the payload for an admin closing a forgotten overtime session.
+type CloseOvertimeSessionRequest struct {
+	CheckOutTime time.Time `json:"check_out_time" binding:"required"`
+	Notes        string    `json:"notes" binding:"required,min=10"`
 }
 
-func (s *attendanceService) ExportEmployeeAttendanceToExcel(employeeID int, startDate, endDate *time.Time) (*excelize.File, string, error) {
-	attendances, err := s.attendanceRepo.GetEmployeeAttendances(employeeID, startDate, endDate)
+// AutoCloseOvertimeSessions closes the overtime sessions left open for longer than the company's maximum session
+// length. They end at the maximum and count zero minutes, or the maximum when the overtime policy credits
+// auto-closed sessions, and are flagged incomplete for admin review. The closed sessions are returned with their
+// employees so that they can be notified.
+func (s *attendanceService) AutoCloseOvertimeSessions() ([]models.AttendancesTable, error) {
+	companies, err := s.companyRepo.GetAllActiveCompanies()
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to retrieve employee attendance for export: %w", err)
+		return nil, fmt.Errorf("failed to get active companies: %w", err)
 	}
 
-	f := excelize.NewFile()
-	// Define and set the sheet name
-	sheetName := "Employee Attendance"
-	f.SetSheetName("Sheet1", sheetName)
+	var closed []models.AttendancesTable
+	for _, company := range companies {
+		policy, err := loadOvertimePolicy(s.overtimePolicyRepo, company.ID)
+		if err != nil {
+			log.Printf("Error loading overtime policy for company %d: %v", company.ID, err)
+			continue
+		}
+		maxSession := time.Duration(policy.MaxSessionMinutes) * time.Minute
 
-	// Set headers
-	f.SetCellValue(sheetName, "A1", "Employee Name")
-	f.SetCellValue(sheetName, "B1", "Check In Time")
-	f.SetCellValue(sheetName, "C1", "Check Out Time")
-	f.SetCellValue(sheetName, "D1", "Status")
+		sessions, err := s.attendanceRepo.FindOpenOvertimeAttendancesByCompany(company.ID, time.Now().Add(-maxSession))
+		if err != nil {
+			log.Printf("Error finding open overtime sessions for company %d: %v", company.ID, err)
+			continue
+		}
 
-	// Apply style to header row
-	style, err := f.NewStyle(&excelize.Style{
-		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
-		Font:      &excelize.Font{Bold: true},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
+		for _, session := range sessions {
+			attendance, err := s.autoCloseOvertimeSession(session.EmployeeID, session.ID, policy)
+			if err != nil {
+				log.Printf("Failed to auto-close overtime session %d for employee %d: %v", session.ID, session.EmployeeID, err)
+				continue
+			}
+			if attendance != nil {
+				closed = append(closed, *attendance)
+			}
+		}
+	}
+
+	return closed, nil
+}
+
+// autoCloseOvertimeSession closes one overtime session under the employee lock. It returns nil when the employee
+// checked out in the meantime.
+func (s *attendanceService) autoCloseOvertimeSession(employeeID, attendanceID int, policy *models.OvertimePolicy) (*models.AttendancesTable, error) {
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employeeID)
 	if err != nil {
-		log.Printf("Error creating style: %v", err)
-	} else {
-		f.SetCellStyle(sheetName, "A1", "D1", style)
+		return nil, err
 	}
+	defer unlock()
 
-	// Populate data
-	for i, att := range attendances {
-		row := i + 2 // Start from row 2 after headers
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), att.Employee.Name)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), att.CheckInTime.Format("2006-01-02 15:04:05"))
-		checkOutTime := "N/A"
-		if att.CheckOutTime != nil {
-			checkOutTime = att.CheckOutTime.Format("2006-01-02 15:04:05")
-		}
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), checkOutTime)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), att.Status)
+	attendance, err := s.attendanceRepo.GetAttendanceByID(attendanceID)
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil || attendance.Status != models.AttendanceStatusOvertimeIn || attendance.CheckOutTime != nil {
+		return nil, nil
 	}
 
-	fileName := "employee_attendance.xlsx"
-	if len(attendances) > 0 {
-		employeeName := attendances[0].Employee.Name
-		dateRange := ""
-		if startDate != nil && endDate != nil {
-			dateRange = fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-		} else if startDate != nil {
-			dateRange = fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
-		} else if endDate != nil {
-			dateRange = fmt.Sprintf("_until_%s", endDate.Format("2006-01-02"))
-		}
-		fileName = fmt.Sprintf("%s_attendance%s.xlsx", employeeName, dateRange)
+	checkOutTime := attendance.CheckInTime.Add(time.Duration(policy.MaxSessionMinutes) * time.Minute)
+	attendance.CheckOutTime = &checkOutTime
+	attendance.Status = models.AttendanceStatusOvertimeOut
+	attendance.IsOvertimeIncomplete = true
+	attendance.OvertimeMinutes = 0
+	attendance.ApprovedOvertimeMinutes = 0
+	if policy.CreditAutoClosedSessions {
+		attendance.OvertimeMinutes = policy.MaxSessionMinutes
+		attendance.ApprovedOvertimeMinutes = s.calculateApprovedOvertimeMinutes(attendance)
 	}
 
-	return f, fileName, nil
+	reason := fmt.Sprintf("Overtime session auto-closed after %d minutes without check-out", policy.MaxSessionMinutes)
+	if err := s.attendanceRepo.UpdateAttendance(attendance, systemAttendanceChange(models.AttendanceEventOvertimeClose, reason)); err != nil {
+		return nil, err
+	}
+	return attendance, nil
 }
 
-func (s *attendanceService) ExportAllAttendancesToExcel(companyID int, startDate, endDate *time.Time) (*excelize.File, string, error) {
-	attendances, err := s.attendanceRepo.GetCompanyAttendancesFiltered(companyID, startDate, endDate, "all")
+// CloseOvertimeSession lets an admin close an employee's open overtime session with the time it really ended.
+func (s *attendanceService) CloseOvertimeSession(adminID uint, companyID int, attendanceID int, req CloseOvertimeSessionRequest) (*models.AttendancesTable, error) {
+	attendance, err := s.attendanceRepo.GetAttendanceByID(attendanceID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to retrieve all company attendances for export: %w", err)
+		return nil, ErrAttendanceRetrieval
+	}
+	if attendance == nil || attendance.Employee.CompanyID != companyID {
+		return nil, ErrAttendanceNotFound
 	}
 
-	f := excelize.NewFile()
-	// Define and set the sheet name
-	sheetName := "All Attendances"
-	f.SetSheetName("Sheet1", sheetName)
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, attendance.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
-	// Set headers
-	f.SetCellValue(sheetName, "A1", "Employee Name")
-	f.SetCellValue(sheetName, "B1", "Check In Time")
-	f.SetCellValue(sheetName, "C1", "Check Out Time")
-	f.SetCellValue(sheetName, "D1", "Status")
+	// Re-read under the lock in case the employee checked out in the meantime.
+	attendance, err = s.attendanceRepo.GetAttendanceByID(attendanceID)
+	if err != nil || attendance == nil {
+		return nil, ErrAttendanceRetrieval
+	}
+	if attendance.Status != models.AttendanceStatusOvertimeIn || attendance.CheckOutTime != nil {
+		return nil, ErrOvertimeSessionNotOpen
+	}
+	if !req.CheckOutTime.After(attendance.CheckInTime) || req.CheckOutTime.After(time.Now()) {
+		return nil, ErrInvalidOvertimeCheckOutTime
+	}
 
-	// Apply style to header row
-	style, err := f.NewStyle(&excelize.Style{
-		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
-		Font:      &excelize.Font{Bold: true},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
-	if err != nil {
-		log.Printf("Error creating style: %v", err)
-	} else {
-		f.SetCellStyle(sheetName, "A1", "D1", style)
+	checkOutTime := req.CheckOutTime
+	attendance.CheckOutTime = &checkOutTime
+	attendance.Status = models.AttendanceStatusOvertimeOut
+	attendance.OvertimeMinutes = int(checkOutTime.Sub(attendance.CheckInTime).Minutes())
+	attendance.ApprovedOvertimeMinutes = s.calculateApprovedOvertimeMinutes(attendance)
+	attendance.IsCorrection = true
+	attendance.Notes = req.Notes
+	attendance.CorrectedByAdminID = &adminID
+
+	change := models.AttendanceChange{
+		ActorType: models.AttendanceActorAdmin,
+		ActorID:   &adminID,
+		Reason:    req.Notes,
+		Event:     models.AttendanceEventOvertimeClose,
 	}
+	if err := s.attendanceRepo.UpdateAttendance(attendance, change); err != nil {
+		return nil, fmt.Errorf("failed to close overtime session: %w", err)
+	}
+	return attendance, nil
+}
 
-	// Populate data
-	for i, att := range attendances {
-		row := i + 2 // Start from row 2 after headers
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), att.Employee.Name)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), att.CheckInTime.Format("2006-01-02 15:04:05"))
-		checkOutTime := "N/A"
-		if att.CheckOutTime != nil {
-			checkOutTime = att.CheckOutTime.Format("2006-01-02 15:04:05")
+// calculateApprovedOvertimeMinutes caps a finished overtime session at what is left of its approved request.
+// Sessions without an approved request get no approved minutes until the request is (re-)approved.
+func (s *attendanceService) calculateApprovedOvertimeMinutes(attendance *models.AttendancesTable) int {
+	if attendance.OvertimeRequestID == nil {
+		return 0
+	}
+	overtimeRequest, err := s.overtimeRequestRepo.GetOvertimeRequestByID(*attendance.OvertimeRequestID)
+	if err != nil || overtimeRequest == nil || overtimeRequest.Status != "approved" {
+		return 0
+	}
+
+	sessions, err := s.attendanceRepo.GetOvertimeAttendancesForDate(attendance.EmployeeID, attendance.CheckInTime)
+	if err != nil {
+		log.Printf("Error fetching overtime sessions for employee %d: %v", attendance.EmployeeID, err)
+		return 0
+	}
+	used := 0
+	for _, session := range sessions {
+		if session.ID != attendance.ID && session.OvertimeRequestID != nil && *session.OvertimeRequestID == overtimeRequest.ID {
+			used += session.ApprovedOvertimeMinutes
 		}
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), checkOutTime)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), att.Status)
 	}
 
-	fileName := "all_company_attendance.xlsx"
-	dateRange := ""
-	if startDate != nil && endDate != nil {
-		dateRange = fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	} else if startDate != nil {
-		dateRange = fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
-	} else if endDate != nil {
-		dateRange = fmt.Sprintf("_until_%s", endDate.Format("2006-01-02"))
+	return capApprovedOvertime(attendance.OvertimeMinutes, overtimeRequest.ApprovedMinutes-used)
+}
+
+
+func (s *attendanceService) GetAttendancesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.AttendancesTable, int64, error) {
+	return s.attendanceRepo.GetAttendancesPaginated(companyID, startDate, endDate, search, page, pageSize)
+}
+
+func (s *attendanceService) ExportEmployeeAttendance(employeeID int, startDate, endDate *time.Time) (*helper.Report, error) {
+	attendances, err := s.attendanceRepo.GetEmployeeAttendances(employeeID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve employee attendance for export: %w", err)
+	}
+
+	table := helper.ReportTable{
+		Name:    "Employee Attendance",
+		Headers: []string{"Employee Name", "Check In Time", "Check Out Time", "Status", "Work Mode"},
+	}
+	for _, att := range attendances {
+		table.Rows = append(table.Rows, attendanceReportRow(att))
+	}
+
+	report := &helper.Report{
+		Title:    "Employee Attendance",
+		Subtitle: helper.ReportPeriod(startDate, endDate),
+		FileName: "employee_attendance",
+		Tables:   []helper.ReportTable{table},
+	}
+	if len(attendances) > 0 {
+		employeeName := attendances[0].Employee.Name
+		report.Title = fmt.Sprintf("Attendance of %s", employeeName)
+		report.FileName = fmt.Sprintf("%s_attendance%s", employeeName, helper.ReportDateRangeSuffix(startDate, endDate))
 	}
-	fileName = fmt.Sprintf("all_company_attendance%s.xlsx", dateRange)
 
-	return f, fileName, nil
+	return report, nil
 }
 
-func (s *attendanceService) ExportUnaccountedToExcel(companyID int, startDate, endDate *time.Time, search string) (*excelize.File, string, error) {
-	unaccountedEmployees, err := s.attendanceRepo.GetUnaccountedEmployeesFiltered(companyID, startDate, endDate, search)
+func (s *attendanceService) ExportAllAttendances(companyID int, startDate, endDate *time.Time) (*helper.Report, error) {
+	attendances, err := s.attendanceRepo.GetCompanyAttendancesFiltered(companyID, startDate, endDate, "all")
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to retrieve unaccounted employees for export: %w", err)
+		return nil, fmt.Errorf("failed to retrieve all company attendances for export: %w", err)
 	}
 
-	f := excelize.NewFile()
-	sheetName := "Unaccounted Employees"
-	f.SetSheetName("Sheet1", sheetName)
+	table := allAttendancesReportTable()
+	for _, att := range attendances {
+		table.Rows = append(table.Rows, attendanceReportRow(att))
+	}
 
-	f.SetCellValue(sheetName, "A1", "Employee Name")
-	f.SetCellValue(sheetName, "B1", "Email")
-	f.SetCellValue(sheetName, "C1", "Position")
+	return &helper.Report{
+		Title:    "Company Attendance",
+		Subtitle: helper.ReportPeriod(startDate, endDate),
+		FileName: AllAttendancesFileName(startDate, endDate),
+		Tables:   []helper.ReportTable{table},
+	}, nil
+}
 
-	// Apply style to header row
-	style, err := f.NewStyle(&excelize.Style{
-		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
-		Font:      &excelize.Font{Bold: true},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
+// CountAllAttendances counts the records of the company attendance export, to decide whether it can be
+// streamed in the request or must run in the background.
+func (s *attendanceService) CountAllAttendances(companyID int, startDate, endDate *time.Time) (int64, error) {
+	return s.attendanceRepo.CountCompanyAttendancesFiltered(companyID, startDate, endDate)
+}
+
+// StreamAllAttendances writes the company attendance export to w as xlsx or csv, reading the records from the
+// database in batches. It returns the number of records written.
+func (s *attendanceService) StreamAllAttendances(companyID int, startDate, endDate *time.Time, format string, w io.Writer) (int, error) {
+	stream, err := helper.NewReportStreamWriter(w, format, allAttendancesReportTable())
 	if err != nil {
-		log.Printf("Error creating style: %v", err)
-	} else {
-		f.SetCellStyle(sheetName, "A1", "C1", style)
+		return 0, err
 	}
 
-	for i, emp := range unaccountedEmployees {
-		row := i + 2
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), emp.Name)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), emp.Email)
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), emp.Position)
+	written := 0
+	err = s.attendanceRepo.FindCompanyAttendancesInBatches(companyID, startDate, endDate, ExportBatchSize, func(attendances []models.AttendancesTable) error {
+		for _, att := range attendances {
+			if err := stream.WriteRow(attendanceReportRow(att)); err != nil {
+				return err
+			}
+			written++
+		}
+		return nil
+	})
+	if err != nil {
+		stream.Close()
+		return written, fmt.Errorf("failed to stream company attendances for export: %w", err)
+	}
+	if err := stream.Close(); err != nil {
+		return written, fmt.Errorf("failed to finish company attendance export: %w", err)
 	}
+	return written, nil
+}
 
-	fileName := "unaccounted_employees.xlsx"
-	dateRange := ""
-	if startDate != nil && endDate != nil {
-		dateRange = fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	} else if startDate != nil {
-		dateRange = fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
+// AllAttendancesFileName is the file name, without extension, of the company attendance export.
+func AllAttendancesFileName(startDate, endDate *time.Time) string {
+	return fmt.Sprintf("all_company_attendance%s", helper.ReportDateRangeSuffix(startDate, endDate))
+}
+
+// allAttendancesReportTable is the empty table of the company attendance export.
+func allAttendancesReportTable() helper.ReportTable {
+	return helper.ReportTable{
+		Name:    "All Attendances",
+		Headers: []string{"Employee Name", "Check In Time", "Check Out Time", "Status", "Work Mode"},
 	}
-	fileName = fmt.Sprintf("unaccounted_employees%s.xlsx", dateRange)
+}
 
-	return f, fileName, nil
+// attendanceReportRow is the row of an attendance record in the attendance exports.
+func attendanceReportRow(att models.AttendancesTable) []interface{} {
+	checkOutTime := "N/A"
+	if att.CheckOutTime != nil {
+		checkOutTime = att.CheckOutTime.Format("2006-01-02 15:04:05")
+	}
+	return []interface{}{att.Employee.Name, att.CheckInTime.Format("2006-01-02 15:04:05"), checkOutTime, string(att.Status), att.WorkMode}
 }
 
-func (s *attendanceService) ExportOvertimeToExcel(companyID int, startDate, endDate *time.Time, search string) (*excelize.File, string, error) {
-	overtimeAttendances, err := s.attendanceRepo.GetOvertimeAttendancesFiltered(companyID, startDate, endDate, search)
+func (s *attendanceService) ExportUnaccounted(companyID int, startDate, endDate *time.Time, search string) (*helper.Report, error) {
+	unaccountedEmployees, err := s.attendanceRepo.GetUnaccountedEmployeesFiltered(companyID, startDate, endDate, search)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to retrieve overtime attendances for export: %w", err)
+		return nil, fmt.Errorf("failed to retrieve unaccounted employees for export: %w", err)
 	}
 
-	f := excelize.NewFile()
-	sheetName := "Overtime Attendances"
-	f.SetSheetName("Sheet1", sheetName)
+	table := helper.ReportTable{
+		Name:    "Unaccounted Employees",
+		Headers: []string{"Employee Name", "Email", "Position"},
+	}
+	for _, emp := range unaccountedEmployees {
+		table.Rows = append(table.Rows, []interface{}{emp.Name, emp.Email, emp.Position})
+	}
 
-	f.SetCellValue(sheetName, "A1", "Employee Name")
-	f.SetCellValue(sheetName, "B1", "Check In Time")
-	f.SetCellValue(sheetName, "C1", "Check Out Time")
-	f.SetCellValue(sheetName, "D1", "Overtime Minutes")
+	return &helper.Report{
+		Title:    "Unaccounted Employees",
+		Subtitle: helper.ReportPeriod(startDate, endDate),
+		FileName: fmt.Sprintf("unaccounted_employees%s", helper.ReportDateRangeSuffix(startDate, endDate)),
+		Tables:   []helper.ReportTable{table},
+	}, nil
+}
 
-	// Apply style to header row
-	style, err := f.NewStyle(&excelize.Style{
-		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}}, // Light blue background
-		Font:      &excelize.Font{Bold: true},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
+func (s *attendanceService) ExportOvertime(companyID int, startDate, endDate *time.Time, search string) (*helper.Report, error) {
+	overtimeAttendances, err := s.attendanceRepo.GetOvertimeAttendancesFiltered(companyID, startDate, endDate, search)
 	if err != nil {
-		log.Printf("Error creating style: %v", err)
-	} else {
-		f.SetCellStyle(sheetName, "A1", "D1", style)
+		return nil, fmt.Errorf("failed to retrieve overtime attendances for export: %w", err)
 	}
 
-	for i, att := range overtimeAttendances {
-		row := i + 2
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), att.Employee.Name)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), att.CheckInTime.Format("2006-01-02 15:04:05"))
+	calculator, err := newOvertimeCalculator(companyID, startDate, endDate, s.overtimePolicyRepo, s.publicHolidayRepo, s.companyRepo)
+	if err != nil {
+		return nil, err
+	}
+	compensations := calculator.CalculateAll(overtimeAttendances)
+
+	sessions := helper.ReportTable{
+		Name:    "Overtime Attendances",
+		Headers: []string{"Employee Name", "Check In Time", "Check Out Time", "Overtime Minutes", "Payable Minutes", "Day Type", "Weighted Overtime Hours"},
+	}
+	for _, att := range overtimeAttendances {
 		checkOutTime := "N/A"
 		if att.CheckOutTime != nil {
 			checkOutTime = att.CheckOutTime.Format("2006-01-02 15:04:05")
 		}
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), checkOutTime)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), att.OvertimeMinutes)
+		compensation := compensations[att.ID]
+		sessions.Rows = append(sessions.Rows, []interface{}{
+			att.Employee.Name, att.CheckInTime.Format("2006-01-02 15:04:05"), checkOutTime, att.OvertimeMinutes,
+			compensation.PayableMinutes, compensation.DayType, compensation.WeightedHours,
+		})
 	}
 
-	fileName := "overtime_attendances.xlsx"
-	dateRange := ""
-	if startDate != nil && endDate != nil {
-		dateRange = fmt.Sprintf("_%s_to_%s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
-	} else if startDate != nil {
-		dateRange = fmt.Sprintf("_%s_onwards", startDate.Format("2006-01-02"))
-	} else if endDate != nil {
-		dateRange = fmt.Sprintf("_until_%s", endDate.Format("2006-01-02"))
+	// Per-employee totals for payroll
+	summary := helper.ReportTable{
+		Name:    "Overtime Summary",
+		Headers: []string{"Employee ID Number", "Employee Name", "Sessions", "Raw Minutes", "Payable Minutes", "Weekday Minutes", "Rest Day Minutes", "Public Holiday Minutes", "Weighted Overtime Hours"},
+	}
+	for _, employee := range summarizeOvertimeCompensation(overtimeAttendances, compensations) {
+		summary.Rows = append(summary.Rows, []interface{}{
+			employee.EmployeeIDNumber, employee.EmployeeName, employee.Sessions, employee.RawMinutes, employee.PayableMinutes,
+			employee.WeekdayMinutes, employee.RestDayMinutes, employee.HolidayMinutes, employee.WeightedHours,
+		})
 	}
-	fileName = fmt.Sprintf("overtime_attendances%s.xlsx", dateRange)
 
-	return f, fileName, nil
+	return &helper.Report{
+		Title:    "Overtime Attendances",
+		Subtitle: helper.ReportPeriod(startDate, endDate),
+		FileName: fmt.Sprintf("overtime_attendances%s", helper.ReportDateRangeSuffix(startDate, endDate)),
+		Tables:   []helper.ReportTable{sessions, summary},
+	}, nil
 }
 
 func (s *attendanceService) GetOvertimeAttendancesPaginated(companyID int, startDate, endDate *time.Time, search string, page int, pageSize int) ([]models.AttendancesTable, int64, error) {
@@ -637,6 +1203,11 @@ func (s *attendanceService) GetEmployeeAttendances(employeeID int, startDate, en
 	return s.attendanceRepo.GetEmployeeAttendances(employeeID, startDate, endDate)
 }
 
+// GetAttendancePunchesPaginated lists the location evidence of punch attempts for admin review.
+func (s *attendanceService) GetAttendancePunchesPaginated(companyID int, result string, flaggedOnly bool, search string, startDate, endDate *time.Time, page int, pageSize int) ([]models.AttendancePunch, int64, error) {
+	return s.attendancePunchRepo.GetAttendancePunchesPaginated(companyID, result, flaggedOnly, search, startDate, endDate, page, pageSize)
+}
+
 // CorrectionRequest defines the payload for a manual attendance correction.
 type CorrectionRequest struct {
 	EmployeeID     int       `json:"employee_id" binding:"required"`
@@ -653,6 +1224,13 @@ func (s *attendanceService) CorrectAttendance(adminID uint, req CorrectionReques
 		return nil, fmt.Errorf("employee with ID %d not found", req.EmployeeID)
 	}
 
+	change := models.AttendanceChange{
+		ActorType: models.AttendanceActorAdmin,
+		ActorID:   &adminID,
+		Reason:    req.Notes,
+		Event:     models.AttendanceEventCorrection,
+	}
+
 	// 2. Handle based on correction type
 	switch req.CorrectionType {
 case "check_out":
@@ -669,12 +1247,12 @@ case "check_out":
 		// Update the existing record
 		now := req.CorrectionTime
 		latestAttendance.CheckOutTime = &now
-		latestAttendance.Status = "present (corrected)"
+		latestAttendance.Status = models.AttendanceStatusCorrected
 		latestAttendance.IsCorrection = true
 		latestAttendance.Notes = req.Notes
 		latestAttendance.CorrectedByAdminID = &adminID
 
-		if err := s.attendanceRepo.UpdateAttendance(latestAttendance); err != nil {
+		if err := s.attendanceRepo.UpdateAttendance(latestAttendance, change); err != nil {
 			return nil, fmt.Errorf("failed to save corrected attendance: %w", err)
 		}
 		return latestAttendance, nil
@@ -684,13 +1262,13 @@ case "check_out":
 		newAttendance := &models.AttendancesTable{
 			EmployeeID:         req.EmployeeID,
 			CheckInTime:        req.CorrectionTime,
-			Status:             "present (corrected)",
+			Status:             models.AttendanceStatusCorrected,
 			IsCorrection:       true,
 			Notes:              req.Notes,
 			CorrectedByAdminID: &adminID,
 		}
 
-		if err := s.attendanceRepo.CreateAttendance(newAttendance); err != nil {
+		if err := s.attendanceRepo.CreateAttendance(newAttendance, change); err != nil {
 			return nil, fmt.Errorf("failed to create new corrected attendance: %w", err)
 		}
 		return newAttendance, nil
@@ -699,159 +1277,451 @@ case "check_out":
 	return nil, fmt.Errorf("invalid correction type specified")
 }
 
-// MarkDailyAbsentees checks for employees who haven't checked in and aren't on leave, and marks them as absent.
-// It also cleans up incomplete attendance records from the previous day.
-func (s *attendanceService) MarkDailyAbsentees() error {
-	log.Println("Starting daily absentee and cleanup process...")
+// employeeAttendanceChange describes a change an employee makes to their own attendance by checking in or out.
+func employeeAttendanceChange(employeeID int, event models.AttendanceEvent, reason string) models.AttendanceChange {
+	actorID := uint(employeeID)
+	return models.AttendanceChange{ActorType: models.AttendanceActorEmployee, ActorID: &actorID, Reason: reason, Event: event}
+}
+
+// attendanceReason describes a check-in or check-out for the attendance history, naming where it came from.
+func attendanceReason(action, source string) string {
+	if source == "" {
+		return action
+	}
+	return fmt.Sprintf("%s via %s", action, source)
+}
 
+// systemAttendanceChange describes a change made by a scheduled job.
+func systemAttendanceChange(event models.AttendanceEvent, reason string) models.AttendanceChange {
+	return models.AttendanceChange{ActorType: models.AttendanceActorSystem, Reason: reason, Event: event}
+}
+
+// MaxAbsenteeBackfillDays limits how many days a single absentee backfill may cover.
+const MaxAbsenteeBackfillDays = 92
+
+// Actions reported by absentee processing.
+const (
+	AbsenteeActionCreate = "create" // A new absent, on_leave or on_sick record
+	AbsenteeActionUpdate = "update" // An existing absence record whose status was stale
+	AbsenteeActionClose  = "close"  // A record left without a check-out, closed as incomplete
+)
+
+// AbsenteeBackfillRequest defines the payload for re-running absentee processing over a date range.
+// Dates are calendar days in each company's timezone.
+type AbsenteeBackfillRequest struct {
+	CompanyID *int   `json:"company_id"` // Nil processes every active company
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+// AbsenteeChange is one record written by absentee processing, or that would be written in a dry run.
+type AbsenteeChange struct {
+	EmployeeID     int                     `json:"employee_id"`
+	EmployeeName   string                  `json:"employee_name"`
+	Action         string                  `json:"action"`                   // One of the AbsenteeAction* constants
+	AttendanceID   int                     `json:"attendance_id,omitempty"`  // Zero for records not created yet
+	PreviousStatus models.AttendanceStatus `json:"previous_status,omitempty"`
+	Status         models.AttendanceStatus `json:"status"`
+}
+
+// AbsenteeReport is the outcome of absentee processing for one company on one day.
+type AbsenteeReport struct {
+	CompanyID   int              `json:"company_id"`
+	CompanyName string           `json:"company_name"`
+	Date        string           `json:"date"`
+	DryRun      bool             `json:"dry_run"`
+	Created     int              `json:"created"`
+	Updated     int              `json:"updated"`
+	Closed      int              `json:"closed"`
+	Unchanged   int              `json:"unchanged"` // Employees who attended or whose absence record is already correct
+	Pending     int              `json:"pending"`   // Employees whose shift has not ended long enough ago to judge
+	Skipped     int              `json:"skipped"`   // Employees without a shift or hired after the day
+	Failed      int              `json:"failed"`
+	Changes     []AbsenteeChange `json:"changes"`
+}
+
+// AbsenteeBackfillReport is the outcome of an absentee backfill, with one report per company and day.
+type AbsenteeBackfillReport struct {
+	StartDate string           `json:"start_date"`
+	EndDate   string           `json:"end_date"`
+	DryRun    bool             `json:"dry_run"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Closed    int              `json:"closed"`
+	Failed    int              `json:"failed"`
+	Days      []AbsenteeReport `json:"days"`
+}
+
+// MarkDailyAbsentees runs absentee processing for every active company whose day is due, and is meant to be
+// called every few minutes. A day is due when one of the company's shifts ended more than its post-shift close
+// time ago, or when the day is over, and it has not been processed since. Yesterday is checked as well, so
+// days missed while the server was down are caught up on the next run.
+func (s *attendanceService) MarkDailyAbsentees() error {
 	companies, err := s.companyRepo.GetAllActiveCompanies()
 	if err != nil {
 		return fmt.Errorf("failed to get active companies: %w", err)
 	}
 
 	for _, company := range companies {
-		log.Printf("Processing company: %s (ID: %d)", company.Name, company.ID)
-
 		companyLocation, err := time.LoadLocation(company.Timezone)
 		if err != nil {
 			log.Printf("Error loading company timezone %s for company %d: %v", company.Timezone, company.ID, err)
 			continue // Skip this company if timezone is invalid
 		}
-
-		nowInCompanyLocation := time.Now().In(companyLocation)
-		yesterday := nowInCompanyLocation.AddDate(0, 0, -1)
-
-		// --- Cleanup: Mark incomplete attendances from yesterday ---
-		log.Printf("Checking for incomplete attendances from %s for company %s", yesterday.Format("2006-01-02"), company.Name)
-		incompleteAttendances, err := s.attendanceRepo.FindIncompleteAttendancesByCompany(company.ID, yesterday)
+		policies, err := loadAttendancePolicySet(s.attendancePolicyRepo, company.ID)
 		if err != nil {
-			log.Printf("Error finding incomplete attendances for company %d: %v", company.ID, err)
-		} else if len(incompleteAttendances) > 0 {
-			log.Printf("Found %d incomplete attendance records to clean up.", len(incompleteAttendances))
-			for _, att := range incompleteAttendances {
-				attToUpdate := att // Make a new variable to avoid loop variable issues
-				attToUpdate.Status = "incomplete"
-				attToUpdate.Notes = "Automatically marked due to forgotten check-out."
-				attToUpdate.IsCorrection = true
-				if err := s.attendanceRepo.UpdateAttendance(&attToUpdate); err != nil {
-					log.Printf("Failed to update incomplete attendance record %d: %v", attToUpdate.ID, err)
-				} else {
-					log.Printf("Marked attendance record %d as incomplete.", attToUpdate.ID)
-				}
-			}
-		}
-		// --- End of Cleanup ---
-
-		employees, err := s.employeeRepo.GetActiveEmployeesByCompanyID(company.ID)
-		if err != nil {
-			log.Printf("Failed to get active employees for company %d: %v", company.ID, err)
+			log.Printf("Error loading attendance policies for company %d: %v", company.ID, err)
 			continue
 		}
-
 		shifts, err := s.shiftRepo.GetShiftsByCompanyID(company.ID)
 		if err != nil {
 			log.Printf("Failed to get shifts for company %d: %v", company.ID, err)
 			continue
 		}
 
-		shiftMap := make(map[uint]models.ShiftsTable)
-		for _, shift := range shifts {
-			shiftMap[uint(shift.ID)] = shift
-		}
-
-		for _, employee := range employees {
-			// Skip if employee has no shift assigned
-			if employee.ShiftID == nil {
-				log.Printf("Employee %s (ID: %d) has no shift assigned. Skipping.", employee.Name, employee.ID)
+		now := time.Now().In(companyLocation)
+		for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+			startOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, companyLocation)
+			due, err := s.absenteeRunDue(company.ID, startOfDay, shifts, policies, now)
+			if err != nil {
+				log.Printf("Error checking absentee runs for company %d: %v", company.ID, err)
 				continue
 			}
-
-			shift, ok := shiftMap[uint(*employee.ShiftID)]
-			if !ok {
-				log.Printf("Shift with ID %d not found for employee %s (ID: %d). Skipping.", *employee.ShiftID, employee.Name, employee.ID)
+			if !due {
 				continue
 			}
 
-			shiftEnd, err := helper.ParseTime(nowInCompanyLocation, shift.EndTime, companyLocation)
+			log.Printf("Processing absentees for company %s (ID: %d) on %s", company.Name, company.ID, startOfDay.Format("2006-01-02"))
+			report, err := s.runAbsentees(&company, companyLocation, startOfDay, policies, models.AbsenteeRunTriggerSchedule)
 			if err != nil {
-				log.Printf("Error parsing shift end time %s for employee %s (ID: %d): %v", shift.EndTime, employee.Name, employee.ID, err)
+				log.Printf("Error processing absentees for company %d on %s: %v", company.ID, startOfDay.Format("2006-01-02"), err)
 				continue
 			}
+			log.Printf("Absentees for company %s on %s: %d created, %d updated, %d closed, %d failed.", company.Name, report.Date, report.Created, report.Updated, report.Closed, report.Failed)
+		}
+	}
 
-			shiftStart, err := helper.ParseTime(nowInCompanyLocation, shift.StartTime, companyLocation)
-			if err != nil {
-				log.Printf("Error parsing shift start time %s for employee %s (ID: %d): %v", shift.StartTime, employee.Name, employee.ID, err)
-				continue
-			}
-			if shiftEnd.Before(shiftStart) {
-				shiftEnd = shiftEnd.Add(24 * time.Hour)
-			}
+	return nil
+}
 
-			gracePeriodAfterShift := 5 * time.Hour
-			processingCutoffTime := shiftEnd.Add(gracePeriodAfterShift)
+// absenteeRunDue reports whether more of a company's day can be judged than at its last successful run: a
+// shift has ended more than its post-shift close time ago, or the day is over and forgotten check-outs can be closed.
+func (s *attendanceService) absenteeRunDue(companyID int, startOfDay time.Time, shifts []models.ShiftsTable, policies *attendancePolicySet, now time.Time) (bool, error) {
+	lastRun, err := s.absenteeRepo.GetLatestAbsenteeRunForDate(companyID, startOfDay)
+	if err != nil {
+		return false, err
+	}
 
-			if nowInCompanyLocation.Before(processingCutoffTime) {
-				log.Printf("Current time %s is before processing cutoff %s for employee %s (ID: %d). Skipping.", nowInCompanyLocation.Format("15:04"), processingCutoffTime.Format("15:04"), employee.Name, employee.ID)
-				continue
-			}
+	dueTimes := []time.Time{startOfDay.AddDate(0, 0, 1)}
+	for _, shift := range shifts {
+		_, shiftEnd, err := shiftBounds(startOfDay, shift)
+		if err != nil {
+			continue
+		}
+		for _, closeDelay := range policies.closeDelays(shift) {
+			dueTimes = append(dueTimes, shiftEnd.Add(closeDelay))
+		}
+	}
+	for _, dueAt := range dueTimes {
+		if !dueAt.After(now) && (lastRun == nil || lastRun.StartedAt.Before(dueAt)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-			hasAttendance, err := s.attendanceRepo.HasAttendanceForDate(employee.ID, nowInCompanyLocation)
-			if err != nil {
-				log.Printf("Error checking attendance for employee %s (ID: %d): %v", employee.Name, employee.ID, err)
-				continue
-			}
+// runAbsentees processes a company's day and records the run, including failed ones.
+func (s *attendanceService) runAbsentees(company *models.CompaniesTable, companyLocation *time.Location, day time.Time, policies *attendancePolicySet, trigger string) (*AbsenteeReport, error) {
+	startedAt := time.Now()
+	report, err := s.processAbsentees(company, companyLocation, day, policies, false)
+
+	day = day.In(companyLocation)
+	run := &models.AbsenteeRun{
+		CompanyID:  company.ID,
+		Date:       time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC), // Stored as a plain calendar date
+		Trigger:    trigger,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+	if report != nil {
+		run.Created = report.Created
+		run.Updated = report.Updated
+		run.Closed = report.Closed
+		run.Pending = report.Pending
+		run.Failed = report.Failed
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if recordErr := s.absenteeRepo.CreateAbsenteeRun(run); recordErr != nil {
+		log.Printf("Failed to record absentee run for company %d: %v", company.ID, recordErr)
+	}
 
-			if hasAttendance {
-				log.Printf("Employee %s (ID: %d) already has attendance for today. Skipping.", employee.Name, employee.ID)
-				continue
-			}
+	return report, err
+}
+
+// BackfillAbsentees re-runs absentee processing for every day in a range, for one company or all active
+// companies. A dry run reports the changes without writing them.
+func (s *attendanceService) BackfillAbsentees(req AbsenteeBackfillRequest) (*AbsenteeBackfillReport, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, ErrInvalidAbsenteeBackfillRange
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil || endDate.Before(startDate) {
+		return nil, ErrInvalidAbsenteeBackfillRange
+	}
+	if int(endDate.Sub(startDate).Hours()/24)+1 > MaxAbsenteeBackfillDays {
+		return nil, ErrInvalidAbsenteeBackfillRange
+	}
+
+	var companies []models.CompaniesTable
+	if req.CompanyID != nil {
+		company, err := s.companyRepo.GetCompanyByID(*req.CompanyID)
+		if err != nil || company == nil {
+			return nil, ErrCompanyNotFound
+		}
+		companies = append(companies, *company)
+	} else {
+		companies, err = s.companyRepo.GetAllActiveCompanies()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get active companies: %w", err)
+		}
+	}
+
+	backfill := &AbsenteeBackfillReport{StartDate: req.StartDate, EndDate: req.EndDate, DryRun: req.DryRun}
+	for i := range companies {
+		company := &companies[i]
+		companyLocation, err := time.LoadLocation(company.Timezone)
+		if err != nil {
+			log.Printf("Error loading company timezone %s for company %d: %v", company.Timezone, company.ID, err)
+			continue
+		}
 
-			approvedLeave, err := s.leaveRequestRepo.IsEmployeeOnApprovedLeave(employee.ID, nowInCompanyLocation)
+		policies, err := loadAttendancePolicySet(s.attendancePolicyRepo, company.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+			startOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, companyLocation)
+			var report *AbsenteeReport
+			if req.DryRun {
+				report, err = s.processAbsentees(company, companyLocation, startOfDay, policies, true)
+			} else {
+				report, err = s.runAbsentees(company, companyLocation, startOfDay, policies, models.AbsenteeRunTriggerBackfill)
+			}
 			if err != nil {
-				log.Printf("Error checking leave status for employee %s (ID: %d): %v", employee.Name, employee.ID, err)
-				continue
+				return nil, err
 			}
+			backfill.Created += report.Created
+			backfill.Updated += report.Updated
+			backfill.Closed += report.Closed
+			backfill.Failed += report.Failed
+			backfill.Days = append(backfill.Days, *report)
+		}
+	}
 
-			if approvedLeave != nil {
-				var status string
-				var notes string
-				if approvedLeave.Type == "sakit" {
-					status = "on_sick"
-					notes = "Automatically marked as on sick leave due to approved sick request."
-				} else {
-					status = "on_leave"
-					notes = "Automatically marked as on leave due to approved leave request."
-				}
-				log.Printf("Employee %s (ID: %d) is on approved %s. Creating '%s' record.", employee.Name, employee.ID, approvedLeave.Type, status)
-				absenceTime := nowInCompanyLocation
-				newAttendance := &models.AttendancesTable{
-					EmployeeID:   employee.ID,
-					CheckInTime:  absenceTime,
-					Status:       status,
-					IsCorrection: true,
-					Notes:        notes,
-				}
-				if err := s.attendanceRepo.CreateAttendance(newAttendance); err != nil {
-					log.Printf("Failed to create %s record for employee %s (ID: %d): %v", status, employee.Name, employee.ID, err)
+	return backfill, nil
+}
+
+// processAbsentees brings one company's attendance for one day in line with the shifts and approved leave as
+// they are now. Records left open on a past day are closed as incomplete, employees without attendance get an
+// absent, on_leave or on_sick record, and absence records that no longer match approved leave are updated.
+// Running it again for the same day changes nothing.
+func (s *attendanceService) processAbsentees(company *models.CompaniesTable, companyLocation *time.Location, day time.Time, policies *attendancePolicySet, dryRun bool) (*AbsenteeReport, error) {
+	day = day.In(companyLocation)
+	startOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, companyLocation)
+	now := time.Now().In(companyLocation)
+	report := &AbsenteeReport{CompanyID: company.ID, CompanyName: company.Name, Date: startOfDay.Format("2006-01-02"), DryRun: dryRun, Changes: []AbsenteeChange{}}
+
+	employees, err := s.employeeRepo.GetActiveEmployeesByCompanyID(company.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active employees: %w", err)
+	}
+	employeeNames := make(map[int]string)
+	for _, employee := range employees {
+		employeeNames[employee.ID] = employee.Name
+	}
+
+	// --- Cleanup: Mark incomplete attendances once the day is over ---
+	if !now.Before(startOfDay.AddDate(0, 0, 1)) {
+		incompleteAttendances, err := s.attendanceRepo.FindIncompleteAttendancesByCompany(company.ID, startOfDay)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find incomplete attendances: %w", err)
+		}
+		for _, att := range incompleteAttendances {
+			attToUpdate := att // Make a new variable to avoid loop variable issues
+			change := AbsenteeChange{EmployeeID: att.EmployeeID, EmployeeName: employeeNames[att.EmployeeID], Action: AbsenteeActionClose, AttendanceID: att.ID, PreviousStatus: att.Status, Status: models.AttendanceStatusIncomplete}
+			if !dryRun {
+				attToUpdate.Status = models.AttendanceStatusIncomplete
+				attToUpdate.Notes = "Automatically marked due to forgotten check-out."
+				attToUpdate.IsCorrection = true
+				if err := s.attendanceRepo.UpdateAttendance(&attToUpdate, systemAttendanceChange(models.AttendanceEventAutoClose, attToUpdate.Notes)); err != nil {
+					log.Printf("Failed to update incomplete attendance record %d: %v", attToUpdate.ID, err)
+					report.Failed++
+					continue
 				}
-				continue
 			}
+			report.Closed++
+			report.Changes = append(report.Changes, change)
+		}
+	}
+	// --- End of Cleanup ---
 
-			log.Printf("Marking employee %s (ID: %d) as absent for today.", employee.Name, employee.ID)
-			absenceTime := nowInCompanyLocation
-			newAttendance := &models.AttendancesTable{
-				EmployeeID:   employee.ID,
-				CheckInTime:  absenceTime,
-				Status:       "absent",
-				IsCorrection: true,
-				Notes:        "Automatically marked as absent due to no check-in and no approved leave.",
-			}
-			if err := s.attendanceRepo.CreateAttendance(newAttendance); err != nil {
-				log.Printf("Failed to create absent record for employee %s (ID: %d): %v", employee.Name, employee.ID, err)
+	shifts, err := s.shiftRepo.GetShiftsByCompanyID(company.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shifts: %w", err)
+	}
+	shiftMap := make(map[uint]models.ShiftsTable)
+	for _, shift := range shifts {
+		shiftMap[uint(shift.ID)] = shift
+	}
+
+	for _, employee := range employees {
+		// Skip if employee has no shift assigned or was not employed yet
+		if employee.ShiftID == nil || !employee.CreatedAt.Before(startOfDay.AddDate(0, 0, 1)) {
+			report.Skipped++
+			continue
+		}
+		shift, ok := shiftMap[uint(*employee.ShiftID)]
+		if !ok {
+			log.Printf("Shift with ID %d not found for employee %s (ID: %d). Skipping.", *employee.ShiftID, employee.Name, employee.ID)
+			report.Skipped++
+			continue
+		}
+
+		shiftStart, shiftEnd, err := shiftBounds(startOfDay, shift)
+		if err != nil {
+			log.Printf("Error parsing shift %d for employee %s (ID: %d): %v", shift.ID, employee.Name, employee.ID, err)
+			report.Failed++
+			continue
+		}
+		if now.Before(shiftEnd.Add(policies.rules(employee.DivisionID, shift).CloseAfterShift)) {
+			report.Pending++
+			continue
+		}
+
+		change, err := s.reconcileAbsence(&employee, startOfDay, shiftStart, dryRun)
+		if err != nil {
+			log.Printf("Failed to process absence of employee %s (ID: %d) on %s: %v", employee.Name, employee.ID, report.Date, err)
+			report.Failed++
+			continue
+		}
+		if change == nil {
+			report.Unchanged++
+			continue
+		}
+		if change.Action == AbsenteeActionCreate {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		report.Changes = append(report.Changes, *change)
+	}
+
+	return report, nil
+}
+
+// shiftBounds returns when a shift starts and ends on the day beginning at startOfDay. Shifts that cross
+// midnight end on the next day.
+func shiftBounds(startOfDay time.Time, shift models.ShiftsTable) (time.Time, time.Time, error) {
+	shiftStart, err := helper.ParseTime(startOfDay, shift.StartTime, startOfDay.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	shiftEnd, err := helper.ParseTime(startOfDay, shift.EndTime, startOfDay.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if shiftEnd.Before(shiftStart) {
+		shiftEnd = shiftEnd.Add(24 * time.Hour)
+	}
+	return shiftStart, shiftEnd, nil
+}
+
+// reconcileAbsence writes the absence record an employee should have for a day, if any. It returns nil when
+// the employee attended or their absence record is already correct.
+func (s *attendanceService) reconcileAbsence(employee *models.EmployeesTable, startOfDay, shiftStart time.Time, dryRun bool) (*AbsenteeChange, error) {
+	unlock, err := lockEmployeeAttendance(s.attendanceRepo, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	attendances, err := s.attendanceRepo.GetAttendancesForDate(employee.ID, startOfDay)
+	if err != nil {
+		return nil, ErrAttendanceRetrieval
+	}
+	var absence *models.AttendancesTable
+	for i := range attendances {
+		switch attendances[i].Status {
+		case models.AttendanceStatusAbsent, models.AttendanceStatusOnLeave, models.AttendanceStatusOnSick:
+			if absence == nil {
+				absence = &attendances[i]
 			}
+		default:
+			return nil, nil // The employee attended
 		}
 	}
 
-	log.Println("Daily absentee and cleanup process finished.")
-	return nil
-}
\ No newline at end of file
+	approvedLeave, err := s.leaveRequestRepo.IsEmployeeOnApprovedLeave(employee.ID, time.Date(startOfDay.Year(), startOfDay.Month(), startOfDay.Day(), 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, ErrLeaveCheckFailed
+	}
+	status := models.AttendanceStatusAbsent
+	event := models.AttendanceEventMarkAbsent
+	notes := "Automatically marked as absent due to no check-in and no approved leave."
+	if approvedLeave != nil {
+		event = models.AttendanceEventLeaveOverlay
+		leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, employee.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		// The leave type decides the status; types missing from the catalogue are treated as regular leave.
+		if leaveTypes[approvedLeave.Type].AttendanceStatus == models.AttendanceStatusOnSick {
+			status = models.AttendanceStatusOnSick
+			notes = "Automatically marked as on sick leave due to approved sick request."
+		} else {
+			status = models.AttendanceStatusOnLeave
+			notes = "Automatically marked as on leave due to approved leave request."
+		}
+	}
+
+	change := &AbsenteeChange{EmployeeID: employee.ID, EmployeeName: employee.Name, Status: status}
+	if absence != nil {
+		if absence.Status == status {
+			return nil, nil
+		}
+		change.Action = AbsenteeActionUpdate
+		change.AttendanceID = absence.ID
+		change.PreviousStatus = absence.Status
+		if dryRun {
+			return change, nil
+		}
+		absence.Status = status
+		absence.Notes = notes
+		if err := s.attendanceRepo.UpdateAttendance(absence, systemAttendanceChange(event, notes)); err != nil {
+			return nil, err
+		}
+		return change, nil
+	}
+
+	change.Action = AbsenteeActionCreate
+	if dryRun {
+		return change, nil
+	}
+	newAttendance := &models.AttendancesTable{
+		EmployeeID:   employee.ID,
+		CheckInTime:  shiftStart, // Keeps the record on its own day, even when processed later
+		Status:       status,
+		IsCorrection: true,
+		Notes:        notes,
+	}
+	if err := s.attendanceRepo.CreateAttendance(newAttendance, systemAttendanceChange(event, notes)); err != nil {
+		return nil, err
+	}
+	change.AttendanceID = newAttendance.ID
+	return change, nil
+}
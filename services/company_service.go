@@ -18,7 +18,7 @@ type CompanyService interface {
 	CreateCompany(req CreateCompanyRequest) (*models.CompaniesTable, error)
 	GetCompanyByID(companyID int) (*models.CompaniesTable, error)
 	GetCompanyDetails(companyID int) (map[string]interface{}, error)
-	UpdateCompanyDetails(companyID int, name, address, timezone string) (*models.CompaniesTable, error)
+	UpdateCompanyDetails(companyID int, name, address, timezone string, overtimeRequiresApproval *bool) (*models.CompaniesTable, error)
 	RegisterCompany(req RegisterCompanyRequest) (*models.CompaniesTable, *models.AdminCompaniesTable, error)
 	ConfirmEmail(token string) error
 	GetCompanySubscriptionStatus(companyID int) (map[string]interface{}, error)
@@ -91,7 +91,7 @@ func (s *companyService) GetCompanyDetails(companyID int) (map[string]interface{
 	return responseData, nil
 }
 
-func (s *companyService) UpdateCompanyDetails(companyID int, name, address, timezone string) (*models.CompaniesTable, error) {
+func (s *companyService) UpdateCompanyDetails(companyID int, name, address, timezone string, overtimeRequiresApproval *bool) (*models.CompaniesTable, error) {
 	company, err := s.companyRepo.GetCompanyByID(companyID)
 	if err != nil {
 		return nil, err
@@ -113,6 +113,9 @@ func (s *companyService) UpdateCompanyDetails(companyID int, name, address, time
 		}
 		company.Timezone = timezone
 	}
+	if overtimeRequiresApproval != nil {
+		company.OvertimeRequiresApproval = *overtimeRequiresApproval
+	}
 
 	if err := s.companyRepo.UpdateCompany(company); err != nil {
 		return nil, err
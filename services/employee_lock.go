@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-face-auth/database/repository"
+	"sync"
+)
+
+// lockEmployeeAttendance serialises attendance writes per employee, so that two concurrent submissions cannot
+// both see "no check-in yet" and record two check-ins. The lock is held in the database, so it also holds when
+// several instances of the API run; employeeLocks queues the callers within this process first, so that waiting
+// does not hold a database connection each.
+func lockEmployeeAttendance(attendanceRepo repository.AttendanceRepository, employeeID int) (func(), error) {
+	unlock := employeeLocks.Lock(employeeID)
+	release, err := attendanceRepo.LockEmployeeAttendance(employeeID)
+	if err != nil {
+		unlock()
+		if errors.Is(err, repository.ErrLockTimeout) {
+			return nil, ErrAttendanceLocked
+		}
+		return nil, fmt.Errorf("failed to lock attendance of employee %d: %w", employeeID, err)
+	}
+	return func() {
+		release()
+		unlock()
+	}, nil
+}
+
+// employeeLocks serialises attendance writes per employee within this process. It is shared by every
+// attendance service instance, including the one used by the cron jobs.
+var employeeLocks = &keyedMutex{locks: make(map[int]*refCountedMutex)}
+
+type refCountedMutex struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// keyedMutex hands out one mutex per key and drops it again once nobody holds or waits for it.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[int]*refCountedMutex
+}
+
+// Lock blocks until the key is free and returns the function that releases it.
+func (k *keyedMutex) Lock(key int) func() {
+	k.mu.Lock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &refCountedMutex{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		k.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
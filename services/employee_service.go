@@ -51,9 +51,10 @@ type employeeService struct {
 	attendanceRepo        repository.AttendanceRepository
 	leaveRequestRepo      repository.LeaveRequestRepository
 	attendanceLocationRepo repository.AttendanceLocationRepository
+	leaveBalanceService   LeaveBalanceService
 }
 
-func NewEmployeeService(employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, shiftRepo repository.ShiftRepository, passwordResetRepo repository.PasswordResetRepository, faceImageRepo repository.FaceImageRepository, attendanceRepo repository.AttendanceRepository, leaveRequestRepo repository.LeaveRequestRepository, attendanceLocationRepo repository.AttendanceLocationRepository) EmployeeService {
+func NewEmployeeService(employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, shiftRepo repository.ShiftRepository, passwordResetRepo repository.PasswordResetRepository, faceImageRepo repository.FaceImageRepository, attendanceRepo repository.AttendanceRepository, leaveRequestRepo repository.LeaveRequestRepository, attendanceLocationRepo repository.AttendanceLocationRepository, leaveBalanceService LeaveBalanceService) EmployeeService {
 	return &employeeService{
 		employeeRepo:          employeeRepo,
 		companyRepo:           companyRepo,
@@ -63,6 +64,7 @@ func NewEmployeeService(employeeRepo repository.EmployeeRepository, companyRepo
 		attendanceRepo:        attendanceRepo,
 		leaveRequestRepo:      leaveRequestRepo,
 		attendanceLocationRepo: attendanceLocationRepo,
+		leaveBalanceService:   leaveBalanceService,
 	}
 }
 
@@ -73,6 +75,7 @@ type CreateEmployeeRequest struct {
 	Position         string `json:"position" binding:"required"`
 	EmployeeIDNumber string `json:"employee_id_number" binding:"required"`
 	ShiftID          *int   `json:"shift_id"`
+	JoinDate         string `json:"join_date" binding:"omitempty,datetime=2006-01-02"` // Day the employee was hired
 }
 
 // CreateEmployee handles the creation of a new employee, including subscription limit checks and initial password setup.
@@ -116,6 +119,13 @@ func (s *employeeService) CreateEmployee(ctx context.Context, companyID uint, re
 		EmployeeIDNumber: req.EmployeeIDNumber,
 		Role:             "employee", // Set default role to employee
 	}
+	if req.JoinDate != "" {
+		joinDate, err := time.Parse("2006-01-02", req.JoinDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid join date: %w", err)
+		}
+		employee.JoinDate = &joinDate
+	}
 
 	// Determine the shift ID for the employee
 	if req.ShiftID != nil {
@@ -638,6 +648,7 @@ type EmployeeDashboardSummary struct {
 	TodayAttendanceStatus   string                 `json:"today_attendance_status"`
 	PendingLeaveRequestsCount int                    `json:"pending_leave_requests_count"`
 	RecentAttendances       []models.AttendancesTable `json:"recent_attendances"`
+	LeaveBalance            *LeaveBalance          `json:"leave_balance,omitempty"` // Omitted when the company has no leave policy
 }
 
 func (s *employeeService) GetEmployeeDashboardSummary(employeeID int) (*EmployeeDashboardSummary, error) {
@@ -652,7 +663,7 @@ func (s *employeeService) GetEmployeeDashboardSummary(employeeID int) (*Employee
 		log.Printf("Error getting today's attendance for employee %d: %v", employeeID, err)
 		todayAttendanceStatus = "Unavailable"
 	} else if todayAttendance != nil {
-		todayAttendanceStatus = todayAttendance.Status
+		todayAttendanceStatus = string(todayAttendance.Status)
 	} else {
 		todayAttendanceStatus = "Not Checked In"
 	}
@@ -672,12 +683,23 @@ func (s *employeeService) GetEmployeeDashboardSummary(employeeID int) (*Employee
 		recentAttendances = []models.AttendancesTable{}
 	}
 
+	leaveBalance, err := s.leaveBalanceService.GetMyLeaveBalance(employeeID, 0)
+	if err != nil {
+		if !errors.Is(err, ErrLeavePolicyNotFound) {
+			log.Printf("Error getting leave balance for employee %d: %v", employeeID, err)
+		}
+		leaveBalance = nil
+	} else {
+		leaveBalance.Entries = nil
+	}
+
 	response := &EmployeeDashboardSummary{
 		EmployeeName:            employee.Name,
 		EmployeePosition:        employee.Position,
 		TodayAttendanceStatus:   todayAttendanceStatus,
 		PendingLeaveRequestsCount: pendingLeaveRequestsCount,
 		RecentAttendances:       recentAttendances,
+		LeaveBalance:            leaveBalance,
 	}
 
 	return response, nil
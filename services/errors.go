@@ -1,6 +1,10 @@
 package services
 
-import "errors"
+import (
+	"errors"
+
+	"go-face-auth/models"
+)
 
 // Sentinel errors for the service layer.
 // These are used to provide structured error handling instead of string comparison.
@@ -16,8 +20,9 @@ var (
 	ErrNoLocationsConfigured    = errors.New("no valid attendance locations configured for employee or division")
 	ErrOutsideShiftHours        = errors.New("cannot check-in for regular attendance outside of shift hours. Use overtime check-in instead")
 	ErrAlreadyCheckedOut        = errors.New("anda sudah melakukan check-in dan check-out untuk hari ini")
+	ErrCheckOutBeforeCheckIn    = errors.New("check-out time must be after the check-in time")
 	ErrOnApprovedLeave          = errors.New("employee is on approved leave")
-	ErrTooEarlyForCheckIn       = errors.New("anda tidak dapat absen lebih dari 1.5 jam sebelum jam shift Anda")
+	ErrTooEarlyForCheckIn       = errors.New("anda belum dapat absen, waktu check-in untuk shift Anda belum dibuka")
 	ErrFaceRecognitionUnavailable = errors.New("face recognition service is unavailable")
 
 	// Overtime specific errors
@@ -25,6 +30,9 @@ var (
 	ErrAlreadyCheckedInOvertime = errors.New("employee is already checked in for overtime")
 	ErrNotCheckedInForOvertime  = errors.New("employee is not currently checked in for overtime")
 	ErrMustCheckOutRegular      = errors.New("anda harus check-out dari shift reguler sebelum check-in lembur")
+	ErrMustCheckOutOvertime     = errors.New("anda harus check-out dari lembur sebelum absen reguler")
+	ErrOvertimeSessionNotOpen      = errors.New("overtime session is not open")
+	ErrInvalidOvertimeCheckOutTime = errors.New("check-out time must be after the overtime check-in and not in the future")
 
 	// General errors
 	ErrInvalidTimezone          = errors.New("invalid company timezone configuration")
@@ -33,4 +41,141 @@ var (
 	ErrLocationRetrieval        = errors.New("failed to retrieve company attendance locations")
 	ErrLeaveCheckFailed         = errors.New("failed to check leave status")
 	ErrShiftValidationFailed    = errors.New("failed to validate shift time")
+	ErrAttendanceLocked         = errors.New("another attendance submission for this employee is still being processed, please try again")
+)
+
+// Overtime request errors
+var (
+	ErrNoApprovedOvertimeRequest   = errors.New("anda tidak memiliki pengajuan lembur yang disetujui untuk hari ini")
+	ErrOvertimeRequestNotFound     = errors.New("overtime request not found")
+	ErrOvertimeRequestNotPending   = errors.New("only pending overtime requests can be reviewed")
+	ErrOvertimeRequestNotApproved  = errors.New("only approved overtime requests can be re-approved")
+	ErrOvertimeRequestUnauthorized = errors.New("you are not authorized to manage this overtime request")
+)
+
+// Overtime policy errors
+var (
+	ErrPublicHolidayNotFound = errors.New("public holiday not found")
+)
+
+// Attendance correction request errors
+var (
+	ErrCorrectionRequestNotFound     = errors.New("attendance correction request not found")
+	ErrCorrectionRequestNotPending   = errors.New("only pending correction requests can be reviewed")
+	ErrCorrectionRequestUnauthorized = errors.New("you are not authorized to manage this correction request")
+	ErrNoOpenAttendanceForCorrection = errors.New("tidak ada check-in tanpa check-out pada tanggal tersebut")
+)
+
+// Attendance history errors
+var (
+	ErrAttendanceNotFound         = errors.New("attendance record not found")
+	ErrAttendanceVersionNotFound  = errors.New("attendance version not found")
+	ErrAttendanceAlreadyAtVersion = errors.New("attendance record already matches this version")
+)
+
+// Attendance status errors
+var (
+	// ErrInvalidAttendanceTransition is returned by the attendance repository when a write breaks the status state machine.
+	ErrInvalidAttendanceTransition = models.ErrInvalidAttendanceTransition
+)
+
+// Attendance policy errors
+var (
+	ErrAttendancePolicyNotFound      = errors.New("attendance policy not found")
+	ErrInvalidAttendancePolicyScope  = errors.New("an attendance policy override applies to either a division or a shift, not both")
+	ErrAttendancePolicyScopeNotFound = errors.New("division or shift not found in this company")
+)
+
+// Absentee processing errors
+var (
+	ErrInvalidAbsenteeBackfillRange = errors.New("invalid backfill range: end date must not be before start date and the range may cover at most 92 days")
+)
+
+// Kiosk errors
+var (
+	ErrKioskDeviceNotFound   = errors.New("kiosk device not found")
+	ErrKioskDeviceRevoked    = errors.New("kiosk device has been revoked")
+	ErrInvalidKioskPublicKey = errors.New("public key must be a base64 encoded Ed25519 key")
+	ErrKioskBatchNotFound    = errors.New("kiosk batch not found")
+	ErrKioskBatchUnverified  = errors.New("no entry in the batch is signed by the kiosk device")
+)
+
+// Location evidence errors
+var (
+	ErrMockLocationDetected         = errors.New("lokasi palsu terdeteksi. Matikan aplikasi fake GPS dan coba lagi")
+	ErrInvalidLocationAccuracy      = errors.New("invalid location accuracy reported by the device")
+	ErrLocationAccuracyInsufficient = errors.New("location accuracy is too low to confirm you are inside the attendance location. Move to an open area and try again")
+	ErrInvalidLocationQRCode        = errors.New("QR code is invalid or has expired. Scan the code currently shown at the location")
+)
+
+// Remote work errors
+var (
+	ErrRemoteWorkRequestNotFound     = errors.New("remote work request not found")
+	ErrRemoteWorkRequestNotPending   = errors.New("only pending remote work requests can be reviewed")
+	ErrRemoteWorkRequestUnauthorized = errors.New("you are not authorized to manage this remote work request")
+	ErrRemoteWorkScheduleNotFound    = errors.New("remote work schedule not found")
+	ErrHomeLocationNotFound          = errors.New("home location not found")
+	ErrNoApprovedHomeLocation        = errors.New("anda belum memiliki lokasi rumah yang disetujui untuk absen WFH")
+	ErrOutsideHomeLocation           = errors.New("you are not within your registered home location")
+)
+
+// Field visit errors
+var (
+	ErrFieldVisitNotFound     = errors.New("field visit not found")
+	ErrFieldVisitInProgress   = errors.New("you already have a field visit in progress")
+	ErrFieldVisitAlreadyEnded = errors.New("field visit has already ended")
+	ErrClientSiteNotFound     = errors.New("client site not found")
+	ErrCustomerNameRequired   = errors.New("customer name is required when no client site is chosen")
+)
+
+// Analytics errors
+var (
+	ErrInvalidAnalyticsRange    = errors.New("analytics range must end after it starts and span at most 366 days")
+	ErrInvalidAnalyticsInterval = errors.New("analytics interval must be day, week or month")
+)
+
+// Attendance anomaly errors
+var (
+	ErrAttendanceAnomalyNotFound        = errors.New("attendance anomaly not found")
+	ErrAttendanceAnomalyAlreadyReviewed = errors.New("attendance anomaly has already been reviewed")
+)
+
+// Export job errors
+var (
+	ErrExportJobNotFound = errors.New("export job not found")
+	ErrExportJobNotReady = errors.New("export is not ready for download yet")
+	ErrExportJobExpired  = errors.New("export has expired, please export it again")
+	ErrExportJobFailed   = errors.New("export failed")
+	ErrPDFExportTooLarge = errors.New("PDF exports are limited to 10000 records, please export as xlsx or csv or choose a shorter period")
+)
+
+// Report subscription errors
+var (
+	ErrReportSubscriptionNotFound = errors.New("report subscription not found")
+	ErrInvalidReportSendTime      = errors.New("send time must be in HH:MM format")
+	ErrReportScheduleDayRequired  = errors.New("weekly reports need a day of the week and monthly reports a day of the month")
+)
+
+// Attendance import errors
+var (
+	ErrInvalidAttendanceImportFormat = errors.New("import format must be zkteco or csv")
+	ErrInvalidAttendanceImportFile   = errors.New("file must have a header row with a user ID column and a timestamp column, or date and time columns")
+	ErrAttendanceImportTooLarge      = errors.New("file has too many rows to import at once, please split it")
+)
+
+// Leave balance errors
+var (
+	ErrLeavePolicyNotFound      = errors.New("the company has no leave policy")
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance")
+	ErrLeaveDuringProbation     = errors.New("annual leave cannot be taken during probation")
+)
+
+// Leave type errors
+var (
+	ErrLeaveTypeNotFound       = errors.New("leave type not found")
+	ErrLeaveTypeCodeTaken      = errors.New("the company already has a leave type with this code")
+	ErrInvalidLeaveTypeCode    = errors.New("leave type code may only contain lowercase letters, digits and underscores")
+	ErrUnknownLeaveType        = errors.New("unknown or inactive leave type")
+	ErrLeaveAttachmentRequired = errors.New("this leave type requires an attachment")
+	ErrLeaveTooLong            = errors.New("leave request is longer than this leave type allows")
 )
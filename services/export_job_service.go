@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+	"go-face-auth/websocket"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Export limits and retention.
+const (
+	// ExportBatchSize is how many records streamed exports read from the database at a time.
+	ExportBatchSize = 1000
+
+	// MaxStreamedExportRows is the largest export served within the request; larger ones run in the background.
+	MaxStreamedExportRows = 50000
+
+	// MaxPDFExportRows is the largest PDF export. PDF reports are rendered in memory, so they cannot be streamed
+	// or run in the background like xlsx and csv. ErrPDFExportTooLarge states the same limit.
+	MaxPDFExportRows = 10000
+
+	// ExportJobRetention is how long the file of a background export can be downloaded.
+	ExportJobRetention = 7 * 24 * time.Hour
+
+	// ExportJobTimeout is how long a background export may stay unfinished before it is considered lost.
+	ExportJobTimeout = 6 * time.Hour
+)
+
+// ExportJobService defines the interface for exports that run in the background.
+type ExportJobService interface {
+	StartAttendanceExport(adminID uint, companyID int, startDate, endDate *time.Time, format string, hub *websocket.Hub) (*models.ExportJob, error)
+	GetExportJobs(companyID int, page, pageSize int) ([]models.ExportJob, int64, error)
+	GetExportJobFile(companyID int, id uint) (*models.ExportJob, error)
+	CleanupExportJobs() (int, error)
+}
+
+// exportJobService is the concrete implementation of ExportJobService.
+type exportJobService struct {
+	exportJobRepo     repository.ExportJobRepository
+	attendanceService AttendanceService
+}
+
+// NewExportJobService creates a new instance of ExportJobService.
+func NewExportJobService(exportJobRepo repository.ExportJobRepository, attendanceService AttendanceService) ExportJobService {
+	return &exportJobService{
+		exportJobRepo:     exportJobRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+// StartAttendanceExport queues the company attendance export and writes it to a file in the background. The
+// company's admins are notified over the websocket when the file is ready or the export failed. Only formats
+// that can be streamed run in the background.
+func (s *exportJobService) StartAttendanceExport(adminID uint, companyID int, startDate, endDate *time.Time, format string, hub *websocket.Hub) (*models.ExportJob, error) {
+	if !helper.IsStreamableReportFormat(format) {
+		return nil, ErrPDFExportTooLarge
+	}
+
+	job := &models.ExportJob{
+		CompanyID:   companyID,
+		RequestedBy: adminID,
+		Report:      models.ExportReportAttendances,
+		Format:      format,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Status:      models.ExportJobStatusPending,
+		FileName:    AllAttendancesFileName(startDate, endDate) + "." + format,
+	}
+	if err := s.exportJobRepo.CreateExportJob(job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	go s.runExportJob(*job, hub)
+
+	return job, nil
+}
+
+// runExportJob writes the file of an export job and records the outcome.
+func (s *exportJobService) runExportJob(job models.ExportJob, hub *websocket.Hub) {
+	job.Status = models.ExportJobStatusRunning
+	if err := s.exportJobRepo.UpdateExportJob(&job); err != nil {
+		log.Printf("Error starting export job %d: %v", job.ID, err)
+		return
+	}
+
+	rows, filePath, err := s.writeExportFile(&job)
+	now := time.Now()
+	if err != nil {
+		log.Printf("Export job %d failed: %v", job.ID, err)
+		if filePath != "" {
+			os.Remove(filePath)
+		}
+		job.Status = models.ExportJobStatusFailed
+		job.Error = err.Error()
+	} else {
+		expiresAt := now.Add(ExportJobRetention)
+		job.Status = models.ExportJobStatusCompleted
+		job.RowCount = rows
+		job.FilePath = filePath
+		job.ExpiresAt = &expiresAt
+		if info, err := os.Stat(filePath); err == nil {
+			job.FileSize = info.Size()
+		}
+	}
+	job.CompletedAt = &now
+	if err := s.exportJobRepo.UpdateExportJob(&job); err != nil {
+		log.Printf("Error recording the outcome of export job %d: %v", job.ID, err)
+		return
+	}
+
+	if hub != nil {
+		setExportDownloadURL(&job)
+		hub.SendMessageToCompanyAdmins(job.CompanyID, "export_job_finished", job)
+	}
+}
+
+// writeExportFile streams the report of a job into a new file in the export directory.
+func (s *exportJobService) writeExportFile(job *models.ExportJob) (int, string, error) {
+	dir := exportStorageDir()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return 0, "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	filePath := filepath.Join(dir, fmt.Sprintf("%d_%s.%s", job.ID, uuid.New().String(), job.Format))
+	file, err := os.Create(filePath)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := s.attendanceService.StreamAllAttendances(job.CompanyID, job.StartDate, job.EndDate, job.Format, file)
+	return rows, filePath, err
+}
+
+func (s *exportJobService) GetExportJobs(companyID int, page, pageSize int) ([]models.ExportJob, int64, error) {
+	jobs, total, err := s.exportJobRepo.GetExportJobsPaginated(companyID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range jobs {
+		setExportDownloadURL(&jobs[i])
+	}
+	return jobs, total, nil
+}
+
+// GetExportJobFile returns a company's export job whose file can be downloaded.
+func (s *exportJobService) GetExportJobFile(companyID int, id uint) (*models.ExportJob, error) {
+	job, err := s.exportJobRepo.GetExportJobByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.CompanyID != companyID {
+		return nil, ErrExportJobNotFound
+	}
+
+	switch job.Status {
+	case models.ExportJobStatusCompleted:
+		if job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt) {
+			return nil, ErrExportJobExpired
+		}
+		return job, nil
+	case models.ExportJobStatusExpired:
+		return nil, ErrExportJobExpired
+	case models.ExportJobStatusFailed:
+		return nil, fmt.Errorf("%w: %s", ErrExportJobFailed, job.Error)
+	}
+	return nil, ErrExportJobNotReady
+}
+
+// CleanupExportJobs deletes the files of expired exports and fails exports that never finished, e.g. because
+// the server restarted while they were running. It returns the number of jobs cleaned up.
+func (s *exportJobService) CleanupExportJobs() (int, error) {
+	now := time.Now()
+	cleaned := 0
+
+	expired, err := s.exportJobRepo.GetExpiredExportJobs(now)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range expired {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+				log.Printf("Error deleting file of export job %d: %v", job.ID, err)
+				continue
+			}
+		}
+		job.Status = models.ExportJobStatusExpired
+		job.FilePath = ""
+		if err := s.exportJobRepo.UpdateExportJob(&job); err != nil {
+			continue
+		}
+		cleaned++
+	}
+
+	stale, err := s.exportJobRepo.GetStaleExportJobs(now.Add(-ExportJobTimeout))
+	if err != nil {
+		return cleaned, err
+	}
+	for _, job := range stale {
+		job.Status = models.ExportJobStatusFailed
+		job.Error = "export was interrupted"
+		job.CompletedAt = &now
+		if err := s.exportJobRepo.UpdateExportJob(&job); err != nil {
+			continue
+		}
+		cleaned++
+	}
+
+	return cleaned, nil
+}
+
+// setExportDownloadURL links completed exports to their download endpoint.
+func setExportDownloadURL(job *models.ExportJob) {
+	if job.Status == models.ExportJobStatusCompleted {
+		job.DownloadURL = fmt.Sprintf("/api/exports/%d/download", job.ID)
+	}
+}
+
+// exportStorageDir is where background exports are written, configurable with EXPORT_STORAGE_DIR.
+func exportStorageDir() string {
+	if dir := os.Getenv("EXPORT_STORAGE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("storage", "exports")
+}
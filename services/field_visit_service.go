@@ -0,0 +1,336 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+	"mime/multipart"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// FieldVisitService defines the interface for field visits of mobile employees and the client sites they visit.
+type FieldVisitService interface {
+	StartFieldVisit(employeeID int, req StartFieldVisitRequest) (*models.FieldVisit, error)
+	EndFieldVisit(employeeID int, visitID uint, req EndFieldVisitRequest) (*models.FieldVisit, error)
+	GetMyFieldVisits(employeeID int, startDate, endDate *time.Time) ([]models.FieldVisit, error)
+	GetFieldVisitTimeline(companyID int, date time.Time, employeeID *int) ([]FieldVisitTimeline, error)
+	GetClientSites(companyID uint) ([]models.ClientSite, error)
+	CreateClientSite(companyID uint, req ClientSiteRequest) (*models.ClientSite, error)
+	UpdateClientSite(companyID, siteID uint, req ClientSiteRequest) (*models.ClientSite, error)
+	DeleteClientSite(companyID, siteID uint) error
+}
+
+// fieldVisitService is the concrete implementation of FieldVisitService.
+type fieldVisitService struct {
+	fieldVisitRepo repository.FieldVisitRepository
+	employeeRepo   repository.EmployeeRepository
+	companyRepo    repository.CompanyRepository
+	faceImageRepo  repository.FaceImageRepository
+	pythonClient   PythonServerClientInterface
+}
+
+// NewFieldVisitService creates a new instance of FieldVisitService.
+func NewFieldVisitService(fieldVisitRepo repository.FieldVisitRepository, employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, faceImageRepo repository.FaceImageRepository, pythonClient PythonServerClientInterface) FieldVisitService {
+	return &fieldVisitService{
+		fieldVisitRepo: fieldVisitRepo,
+		employeeRepo:   employeeRepo,
+		companyRepo:    companyRepo,
+		faceImageRepo:  faceImageRepo,
+		pythonClient:   pythonClient,
+	}
+}
+
+// StartFieldVisitRequest is the multipart form for starting a field visit. CustomerName may be left empty
+// when a registered client site is chosen.
+type StartFieldVisitRequest struct {
+	ClientSiteID   *uint                 `form:"client_site_id"`
+	CustomerName   string                `form:"customer_name"`
+	Notes          string                `form:"notes"`
+	Latitude       float64               `form:"latitude" binding:"required,min=-90,max=90"`
+	Longitude      float64               `form:"longitude" binding:"required,min=-180,max=180"`
+	Accuracy       *float64              `form:"accuracy" binding:"omitempty,min=0"`
+	Provider       string                `form:"provider"`
+	IsMockLocation bool                  `form:"is_mock_location"`
+	ImageData      string                `form:"image_data" binding:"required"` // Base64 selfie for face verification
+	Photo          *multipart.FileHeader `form:"photo"`                         // Optional photo of the visit
+}
+
+// EndFieldVisitRequest is the multipart form for ending a field visit.
+type EndFieldVisitRequest struct {
+	Notes          string                `form:"notes"` // Replaces the notes given at the start when set
+	Latitude       float64               `form:"latitude" binding:"required,min=-90,max=90"`
+	Longitude      float64               `form:"longitude" binding:"required,min=-180,max=180"`
+	Accuracy       *float64              `form:"accuracy" binding:"omitempty,min=0"`
+	Provider       string                `form:"provider"`
+	IsMockLocation bool                  `form:"is_mock_location"`
+	ImageData      string                `form:"image_data" binding:"required"`
+	Photo          *multipart.FileHeader `form:"photo"`
+}
+
+// ClientSiteRequest defines the payload for registering or updating a client site.
+type ClientSiteRequest struct {
+	Name      string  `json:"name" binding:"required"`
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Radius    uint    `json:"radius" binding:"required,min=10,max=5000"`
+}
+
+// FieldVisitTimeline is one employee's field visits on a day, in the order they were made.
+type FieldVisitTimeline struct {
+	EmployeeID       int                 `json:"employee_id"`
+	EmployeeName     string              `json:"employee_name"`
+	EmployeeIDNumber string              `json:"employee_id_number"`
+	VisitCount       int                 `json:"visit_count"`
+	TotalMinutes     int                 `json:"total_minutes"` // Minutes spent in completed visits
+	Visits           []models.FieldVisit `json:"visits"`
+}
+
+// StartFieldVisit face-verifies the employee and opens a visit at their current coordinates.
+func (s *fieldVisitService) StartFieldVisit(employeeID int, req StartFieldVisitRequest) (*models.FieldVisit, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(employeeID)
+	if err != nil || employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if req.IsMockLocation || isMockLocationProvider(req.Provider) {
+		return nil, ErrMockLocationDetected
+	}
+
+	openVisit, err := s.fieldVisitRepo.GetOpenFieldVisit(employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check visits in progress: %w", err)
+	}
+	if openVisit != nil {
+		return nil, ErrFieldVisitInProgress
+	}
+
+	var site *models.ClientSite
+	if req.ClientSiteID != nil {
+		site, err = s.fieldVisitRepo.GetClientSiteByID(*req.ClientSiteID)
+		if err != nil || site == nil || site.CompanyID != uint(employee.CompanyID) {
+			return nil, ErrClientSiteNotFound
+		}
+	} else {
+		site, err = s.findClientSiteAt(uint(employee.CompanyID), req.Latitude, req.Longitude)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	customerName := req.CustomerName
+	if customerName == "" && site != nil {
+		customerName = site.Name
+	}
+	if customerName == "" {
+		return nil, ErrCustomerNameRequired
+	}
+
+	if _, err := verifyEmployeeFace(s.faceImageRepo, s.pythonClient, employeeID, req.ImageData); err != nil {
+		return nil, err
+	}
+
+	visit := &models.FieldVisit{
+		EmployeeID:     employeeID,
+		CustomerName:   customerName,
+		Notes:          req.Notes,
+		Status:         "in_progress",
+		StartedAt:      time.Now(),
+		StartLatitude:  req.Latitude,
+		StartLongitude: req.Longitude,
+		StartAccuracy:  req.Accuracy,
+	}
+	if site != nil {
+		visit.ClientSiteID = &site.ID
+		visit.OutsideSite = outsideClientSite(site, req.Latitude, req.Longitude, req.Accuracy)
+	}
+	if req.Photo != nil {
+		visit.StartPhotoPath, err = helper.SaveUploadedFile(req.Photo, fieldVisitPhotoDir(employee))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save visit photo: %w", err)
+		}
+	}
+
+	if err := s.fieldVisitRepo.CreateFieldVisit(visit); err != nil {
+		return nil, fmt.Errorf("failed to start field visit: %w", err)
+	}
+	visit.ClientSite = site
+
+	return visit, nil
+}
+
+// EndFieldVisit face-verifies the employee and closes their visit at their current coordinates.
+func (s *fieldVisitService) EndFieldVisit(employeeID int, visitID uint, req EndFieldVisitRequest) (*models.FieldVisit, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(employeeID)
+	if err != nil || employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if req.IsMockLocation || isMockLocationProvider(req.Provider) {
+		return nil, ErrMockLocationDetected
+	}
+
+	visit, err := s.fieldVisitRepo.GetFieldVisitByID(visitID)
+	if err != nil || visit == nil || visit.EmployeeID != employeeID {
+		return nil, ErrFieldVisitNotFound
+	}
+	if visit.EndedAt != nil {
+		return nil, ErrFieldVisitAlreadyEnded
+	}
+
+	if _, err := verifyEmployeeFace(s.faceImageRepo, s.pythonClient, employeeID, req.ImageData); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	visit.EndedAt = &now
+	visit.EndLatitude = &req.Latitude
+	visit.EndLongitude = &req.Longitude
+	visit.EndAccuracy = req.Accuracy
+	visit.Status = "completed"
+	if req.Notes != "" {
+		visit.Notes = req.Notes
+	}
+	if visit.ClientSite != nil && outsideClientSite(visit.ClientSite, req.Latitude, req.Longitude, req.Accuracy) {
+		visit.OutsideSite = true
+	}
+	if req.Photo != nil {
+		visit.EndPhotoPath, err = helper.SaveUploadedFile(req.Photo, fieldVisitPhotoDir(employee))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save visit photo: %w", err)
+		}
+	}
+
+	if err := s.fieldVisitRepo.UpdateFieldVisit(visit); err != nil {
+		return nil, fmt.Errorf("failed to end field visit: %w", err)
+	}
+
+	return visit, nil
+}
+
+func (s *fieldVisitService) GetMyFieldVisits(employeeID int, startDate, endDate *time.Time) ([]models.FieldVisit, error) {
+	return s.fieldVisitRepo.GetFieldVisitsByEmployeeID(employeeID, startDate, endDate)
+}
+
+// GetFieldVisitTimeline returns the visits started on the given calendar day in the company's timezone,
+// grouped per employee and sorted by employee name.
+func (s *fieldVisitService) GetFieldVisitTimeline(companyID int, date time.Time, employeeID *int) ([]FieldVisitTimeline, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	visits, err := s.fieldVisitRepo.GetCompanyFieldVisits(companyID, employeeID, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve field visits: %w", err)
+	}
+
+	var timelines []FieldVisitTimeline
+	index := make(map[int]int)
+	for _, visit := range visits {
+		i, ok := index[visit.EmployeeID]
+		if !ok {
+			i = len(timelines)
+			index[visit.EmployeeID] = i
+			timelines = append(timelines, FieldVisitTimeline{
+				EmployeeID:       visit.EmployeeID,
+				EmployeeName:     visit.Employee.Name,
+				EmployeeIDNumber: visit.Employee.EmployeeIDNumber,
+			})
+		}
+		timeline := &timelines[i]
+		timeline.VisitCount++
+		if visit.EndedAt != nil {
+			timeline.TotalMinutes += int(visit.EndedAt.Sub(visit.StartedAt).Minutes())
+		}
+		timeline.Visits = append(timeline.Visits, visit)
+	}
+	sort.Slice(timelines, func(i, j int) bool { return timelines[i].EmployeeName < timelines[j].EmployeeName })
+
+	return timelines, nil
+}
+
+func (s *fieldVisitService) GetClientSites(companyID uint) ([]models.ClientSite, error) {
+	return s.fieldVisitRepo.GetClientSitesByCompanyID(companyID)
+}
+
+func (s *fieldVisitService) CreateClientSite(companyID uint, req ClientSiteRequest) (*models.ClientSite, error) {
+	site := &models.ClientSite{
+		CompanyID: companyID,
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Radius:    req.Radius,
+	}
+	if err := s.fieldVisitRepo.CreateClientSite(site); err != nil {
+		return nil, fmt.Errorf("failed to create client site: %w", err)
+	}
+	return site, nil
+}
+
+func (s *fieldVisitService) UpdateClientSite(companyID, siteID uint, req ClientSiteRequest) (*models.ClientSite, error) {
+	site, err := s.fieldVisitRepo.GetClientSiteByID(siteID)
+	if err != nil || site == nil || site.CompanyID != companyID {
+		return nil, ErrClientSiteNotFound
+	}
+
+	site.Name = req.Name
+	site.Address = req.Address
+	site.Latitude = req.Latitude
+	site.Longitude = req.Longitude
+	site.Radius = req.Radius
+
+	if err := s.fieldVisitRepo.UpdateClientSite(site); err != nil {
+		return nil, fmt.Errorf("failed to update client site: %w", err)
+	}
+	return site, nil
+}
+
+func (s *fieldVisitService) DeleteClientSite(companyID, siteID uint) error {
+	site, err := s.fieldVisitRepo.GetClientSiteByID(siteID)
+	if err != nil || site == nil || site.CompanyID != companyID {
+		return ErrClientSiteNotFound
+	}
+	return s.fieldVisitRepo.DeleteClientSite(siteID)
+}
+
+// findClientSiteAt returns the nearest registered client site whose radius contains the point, if any.
+func (s *fieldVisitService) findClientSiteAt(companyID uint, latitude, longitude float64) (*models.ClientSite, error) {
+	sites, err := s.fieldVisitRepo.GetClientSitesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve client sites: %w", err)
+	}
+
+	var nearest *models.ClientSite
+	nearestDistance := 0.0
+	for i := range sites {
+		distance := helper.HaversineDistance(latitude, longitude, sites[i].Latitude, sites[i].Longitude)
+		if distance <= float64(sites[i].Radius) && (nearest == nil || distance < nearestDistance) {
+			nearest = &sites[i]
+			nearestDistance = distance
+		}
+	}
+	return nearest, nil
+}
+
+// outsideClientSite reports whether a reading is clearly outside the site. The reported accuracy is given
+// to the employee, since the site is only a soft geofence.
+func outsideClientSite(site *models.ClientSite, latitude, longitude float64, accuracy *float64) bool {
+	distance := helper.HaversineDistance(latitude, longitude, site.Latitude, site.Longitude)
+	if accuracy != nil {
+		distance -= *accuracy
+	}
+	return distance > float64(site.Radius)
+}
+
+func fieldVisitPhotoDir(employee *models.EmployeesTable) string {
+	return filepath.Join("field_visits", strconv.Itoa(employee.CompanyID), strconv.Itoa(employee.ID))
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Limits applied to entries uploaded by offline kiosks.
+const (
+	// KioskMaxClockSkew is how far in the future a captured time may be before it is rejected.
+	KioskMaxClockSkew = 5 * time.Minute
+
+	// KioskMaxEntryAge is how old a captured check-in may be when it is uploaded.
+	KioskMaxEntryAge = 7 * 24 * time.Hour
+)
+
+// Reasons a kiosk batch is flagged for review.
+const (
+	KioskFlagInvalidSignature  = "invalid_signature"
+	KioskFlagReplayedEntries   = "replayed_entries"
+	KioskFlagOutOfOrder        = "out_of_order"
+	KioskFlagSequenceGap       = "sequence_gap"
+	KioskFlagClockInconsistent = "clock_inconsistent"
+	KioskFlagFutureTimestamp   = "future_timestamp"
+	KioskFlagStaleEntries      = "stale_entries"
+	KioskFlagForeignEmployee   = "foreign_employee"
+	KioskFlagFaceMismatch      = "face_mismatch"
+)
+
+// KioskPunchDevicePrefix prefixes the device ID recorded on punches captured by a kiosk, followed by the kiosk's ID.
+const KioskPunchDevicePrefix = "kiosk-"
+
+// KioskService defines the interface for offline kiosk devices and their signed check-in batches.
+type KioskService interface {
+	RegisterKioskDevice(companyID int, adminID uint, req RegisterKioskDeviceRequest) (*models.KioskDevice, error)
+	GetKioskDevices(companyID int) ([]models.KioskDevice, error)
+	RevokeKioskDevice(companyID int, deviceID uint) (*models.KioskDevice, error)
+	UploadKioskBatch(req KioskBatchUploadRequest) (*models.KioskBatch, error)
+	GetKioskBatches(companyID int, status string, page, pageSize int) ([]models.KioskBatch, int64, error)
+	GetKioskBatch(companyID int, batchID uint) (*models.KioskBatch, error)
+}
+
+// RegisterKioskDeviceRequest defines the payload for registering a kiosk device.
+type RegisterKioskDeviceRequest struct {
+	Name      string `json:"name" binding:"required"`
+	PublicKey string `json:"public_key" binding:"required"` // Base64 Ed25519 public key generated on the device
+}
+
+// KioskEntryPayload is a single check-in captured offline by a kiosk.
+type KioskEntryPayload struct {
+	Sequence   int64   `json:"sequence" binding:"required,min=1"` // Increases by one for every capture on the device
+	EmployeeID int     `json:"employee_id" binding:"required"`
+	CapturedAt string  `json:"captured_at" binding:"required"` // RFC 3339 with offset, exactly as signed
+	Latitude   float64 `json:"latitude" binding:"required"`
+	Longitude  float64 `json:"longitude" binding:"required"`
+	ImageData  string  `json:"image_data" binding:"required"`
+	Signature  string  `json:"signature" binding:"required"` // Base64 Ed25519 signature of KioskSigningPayload
+}
+
+// KioskBatchUploadRequest defines the payload a kiosk sends when it is back online.
+type KioskBatchUploadRequest struct {
+	DeviceID uint                `json:"device_id" binding:"required"`
+	Entries  []KioskEntryPayload `json:"entries" binding:"required,min=1,max=200,dive"`
+}
+
+// kioskService is the concrete implementation of KioskService.
+type kioskService struct {
+	kioskRepo         repository.KioskRepository
+	employeeRepo      repository.EmployeeRepository
+	attendanceRepo    repository.AttendanceRepository
+	attendanceService AttendanceService // Applies the regular check-in rules as of the captured time
+}
+
+// NewKioskService creates a new instance of KioskService.
+func NewKioskService(kioskRepo repository.KioskRepository, employeeRepo repository.EmployeeRepository, attendanceRepo repository.AttendanceRepository, attendanceService AttendanceService) KioskService {
+	return &kioskService{
+		kioskRepo:         kioskRepo,
+		employeeRepo:      employeeRepo,
+		attendanceRepo:    attendanceRepo,
+		attendanceService: attendanceService,
+	}
+}
+
+// KioskSigningPayload builds the message a kiosk signs for an entry:
+// "<device_id>|<sequence>|<employee_id>|<captured_at>|<latitude>|<longitude>|<sha256 hex of image_data>",
+// with the coordinates written to six decimal places.
+func KioskSigningPayload(deviceID uint, entry KioskEntryPayload) []byte {
+	return []byte(fmt.Sprintf("%d|%d|%d|%s|%.6f|%.6f|%s", deviceID, entry.Sequence, entry.EmployeeID, entry.CapturedAt, entry.Latitude, entry.Longitude, hashKioskImage(entry.ImageData)))
+}
+
+func hashKioskImage(imageData string) string {
+	sum := sha256.Sum256([]byte(imageData))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeKioskPublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKioskPublicKey
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func (s *kioskService) RegisterKioskDevice(companyID int, adminID uint, req RegisterKioskDeviceRequest) (*models.KioskDevice, error) {
+	if _, err := decodeKioskPublicKey(req.PublicKey); err != nil {
+		return nil, err
+	}
+
+	device := &models.KioskDevice{
+		CompanyID:      companyID,
+		Name:           req.Name,
+		PublicKey:      req.PublicKey,
+		Status:         "active",
+		RegisteredByID: adminID,
+	}
+	if err := s.kioskRepo.CreateKioskDevice(device); err != nil {
+		return nil, fmt.Errorf("failed to register kiosk device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *kioskService) GetKioskDevices(companyID int) ([]models.KioskDevice, error) {
+	return s.kioskRepo.GetKioskDevicesByCompanyID(companyID)
+}
+
+// RevokeKioskDevice stops a device from uploading further batches, e.g. when it is lost.
+func (s *kioskService) RevokeKioskDevice(companyID int, deviceID uint) (*models.KioskDevice, error) {
+	device, err := s.kioskRepo.GetKioskDeviceByID(deviceID)
+	if err != nil || device == nil || device.CompanyID != companyID {
+		return nil, ErrKioskDeviceNotFound
+	}
+
+	device.Status = "revoked"
+	if err := s.kioskRepo.UpdateKioskDevice(device); err != nil {
+		return nil, fmt.Errorf("failed to revoke kiosk device: %w", err)
+	}
+	return device, nil
+}
+
+// UploadKioskBatch verifies and records a batch of offline check-ins in sequence order. Each entry is
+// accepted or rejected on its own; anything that looks like tampering, replay or clock problems flags
+// the whole batch for admin review.
+func (s *kioskService) UploadKioskBatch(req KioskBatchUploadRequest) (*models.KioskBatch, error) {
+	device, err := s.kioskRepo.GetKioskDeviceByID(req.DeviceID)
+	if err != nil || device == nil {
+		return nil, ErrKioskDeviceNotFound
+	}
+	if device.Status != "active" {
+		return nil, ErrKioskDeviceRevoked
+	}
+	publicKey, err := decodeKioskPublicKey(device.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]KioskEntryPayload, len(req.Entries))
+	copy(entries, req.Entries)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Sequence < entries[j].Sequence })
+
+	// The upload endpoint is public, so a batch that is not signed by the device at all is not recorded.
+	verified := make([]bool, len(entries))
+	anyVerified := false
+	for i, entry := range entries {
+		signature, err := base64.StdEncoding.DecodeString(entry.Signature)
+		verified[i] = err == nil && ed25519.Verify(publicKey, KioskSigningPayload(device.ID, entry), signature)
+		anyVerified = anyVerified || verified[i]
+	}
+	if !anyVerified {
+		return nil, ErrKioskBatchUnverified
+	}
+
+	// Uploads from a device are processed one at a time, and every entry claims its sequence before it is
+	// applied, so a retried or replayed upload can never apply an entry twice.
+	var batch *models.KioskBatch
+	err = s.kioskRepo.LockKioskDevice(device.ID, func(device *models.KioskDevice) error {
+		if device.Status != "active" {
+			return ErrKioskDeviceRevoked
+		}
+		batch, err = s.processKioskBatch(device, entries, verified)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// processKioskBatch records and applies the entries of a batch in sequence order while the device is locked, and
+// moves the device's last sequence and sync time along. verified tells which entries carry a valid signature.
+func (s *kioskService) processKioskBatch(device *models.KioskDevice, entries []KioskEntryPayload, verified []bool) (*models.KioskBatch, error) {
+	now := time.Now()
+	flags := make(map[string]bool)
+	batch := &models.KioskBatch{
+		KioskDeviceID: device.ID,
+		CompanyID:     device.CompanyID,
+		Status:        "processing",
+		EntryCount:    len(entries),
+	}
+	if err := s.kioskRepo.CreateKioskBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to record kiosk batch: %w", err)
+	}
+	source := fmt.Sprintf("offline kiosk %q", device.Name)
+
+	expectedSequence := device.LastSequence + 1
+	var previousCapturedAt time.Time
+	for i, entry := range entries {
+		record := models.KioskEntry{
+			KioskBatchID:  batch.ID,
+			KioskDeviceID: device.ID,
+			Sequence:      entry.Sequence,
+			EmployeeID:    entry.EmployeeID,
+			Latitude:      entry.Latitude,
+			Longitude:     entry.Longitude,
+			ImageHash:     hashKioskImage(entry.ImageData),
+		}
+		reject := func(flag, message string) error {
+			if flag != "" {
+				flags[flag] = true
+			}
+			record.Result = "rejected"
+			record.Message = message
+			record.AcceptedSequence = nil // Releases the claim on the sequence
+			if err := s.kioskRepo.SaveKioskEntry(&record); err != nil {
+				return fmt.Errorf("failed to record kiosk entry: %w", err)
+			}
+			batch.RejectedCount++
+			batch.Entries = append(batch.Entries, record)
+			return nil
+		}
+
+		if !verified[i] {
+			if err := reject(KioskFlagInvalidSignature, "signature does not match the device key"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		capturedAt, err := time.Parse(time.RFC3339, entry.CapturedAt)
+		if err != nil {
+			if err := reject("", "captured_at must be an RFC 3339 timestamp"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		record.CapturedAt = capturedAt
+
+		// The entry is recorded as pending before it is applied; the unique index on accepted sequences
+		// refuses the claim when another upload has already taken the sequence.
+		record.Result = "pending"
+		record.AcceptedSequence = &record.Sequence
+		claimed, err := s.kioskRepo.ClaimKioskEntry(&record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record kiosk entry: %w", err)
+		}
+		if !claimed {
+			if err := reject(KioskFlagReplayedEntries, "entry has already been processed"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if entry.Sequence < expectedSequence {
+			flags[KioskFlagOutOfOrder] = true
+		} else {
+			if entry.Sequence > expectedSequence {
+				flags[KioskFlagSequenceGap] = true
+			}
+			expectedSequence = entry.Sequence + 1
+		}
+
+		rejection, flag := "", ""
+		switch {
+		case capturedAt.After(now.Add(KioskMaxClockSkew)):
+			flag, rejection = KioskFlagFutureTimestamp, "captured time is in the future"
+		case capturedAt.Before(now.Add(-KioskMaxEntryAge)):
+			flag, rejection = KioskFlagStaleEntries, "entry is too old to be uploaded"
+		case capturedAt.Before(device.CreatedAt):
+			flag, rejection = KioskFlagClockInconsistent, "captured before the device was registered"
+		}
+		if rejection != "" {
+			if err := reject(flag, rejection); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if capturedAt.Before(previousCapturedAt) {
+			flags[KioskFlagClockInconsistent] = true // Later captures should never carry an earlier time
+		}
+		previousCapturedAt = capturedAt
+
+		employee, err := s.employeeRepo.GetEmployeeByID(entry.EmployeeID)
+		if err != nil || employee == nil || employee.CompanyID != device.CompanyID {
+			if err := reject(KioskFlagForeignEmployee, "employee does not belong to the device's company"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		attendanceReq := AttendanceRequest{
+			EmployeeID: entry.EmployeeID,
+			Latitude:   entry.Latitude,
+			Longitude:  entry.Longitude,
+			ImageData:  entry.ImageData,
+			DeviceID:   fmt.Sprintf("%s%d", KioskPunchDevicePrefix, device.ID),
+		}
+		message, _, recordedAt, err := s.attendanceService.HandleOfflineAttendance(attendanceReq, capturedAt, source)
+		if err != nil {
+			flag := ""
+			if errors.Is(err, ErrFaceNotRecognized) {
+				flag = KioskFlagFaceMismatch
+			}
+			if err := reject(flag, err.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if attendance, err := s.attendanceRepo.GetLatestAttendanceForDate(entry.EmployeeID, recordedAt); err == nil && attendance != nil {
+			record.AttendanceID = &attendance.ID
+		}
+		record.Result = "accepted"
+		record.Message = message
+		if err := s.kioskRepo.SaveKioskEntry(&record); err != nil {
+			return nil, fmt.Errorf("failed to record kiosk entry: %w", err)
+		}
+		batch.AcceptedCount++
+		batch.Entries = append(batch.Entries, record)
+		if entry.Sequence > device.LastSequence {
+			device.LastSequence = entry.Sequence
+		}
+	}
+
+	batch.Status = "processed"
+	if len(flags) > 0 {
+		batch.Status = "flagged"
+		flagList := make([]string, 0, len(flags))
+		for flag := range flags {
+			flagList = append(flagList, flag)
+		}
+		sort.Strings(flagList)
+		batch.Flags = strings.Join(flagList, ",")
+	}
+	if err := s.kioskRepo.UpdateKioskBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to record kiosk batch: %w", err)
+	}
+
+	device.LastSyncedAt = &now
+	return batch, nil
+}
+
+func (s *kioskService) GetKioskBatches(companyID int, status string, page, pageSize int) ([]models.KioskBatch, int64, error) {
+	return s.kioskRepo.GetKioskBatchesPaginated(companyID, status, page, pageSize)
+}
+
+func (s *kioskService) GetKioskBatch(companyID int, batchID uint) (*models.KioskBatch, error) {
+	batch, err := s.kioskRepo.GetKioskBatchByID(batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve kiosk batch: %w", err)
+	}
+	if batch == nil || batch.CompanyID != companyID {
+		return nil, ErrKioskBatchNotFound
+	}
+	return batch, nil
+}
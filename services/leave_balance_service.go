@@ -0,0 +1,721 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// LeaveBalanceService defines the interface for leave policies and the annual leave balance of employees.
+type LeaveBalanceService interface {
+	GetLeavePolicy(companyID int) (*models.LeavePolicy, error)
+	UpdateLeavePolicy(companyID int, req UpdateLeavePolicyRequest) (*models.LeavePolicy, error)
+	GetMyLeaveBalance(employeeID int, year int) (*LeaveBalance, error)
+	GetEmployeeLeaveBalance(companyID, employeeID int, year int) (*LeaveBalance, error)
+	GetCompanyLeaveBalances(companyID int, year int, search string, page, pageSize int) ([]LeaveBalance, int64, error)
+	AdjustLeaveBalance(adminID uint, companyID, employeeID int, req AdjustLeaveBalanceRequest) (*models.LeaveLedgerEntry, error)
+	AccrueLeaveBalances() (int, error)
+}
+
+// leaveBalanceService is the concrete implementation of LeaveBalanceService.
+type leaveBalanceService struct {
+	leaveBalanceRepo   repository.LeaveBalanceRepository
+	employeeRepo       repository.EmployeeRepository
+	companyRepo        repository.CompanyRepository
+	leaveRequestRepo   repository.LeaveRequestRepository
+	overtimePolicyRepo repository.OvertimePolicyRepository
+	publicHolidayRepo  repository.PublicHolidayRepository
+	leaveTypeRepo      repository.LeaveTypeRepository
+}
+
+// NewLeaveBalanceService creates a new instance of LeaveBalanceService.
+func NewLeaveBalanceService(leaveBalanceRepo repository.LeaveBalanceRepository, employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, leaveRequestRepo repository.LeaveRequestRepository, overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, leaveTypeRepo repository.LeaveTypeRepository) LeaveBalanceService {
+	return &leaveBalanceService{
+		leaveBalanceRepo:   leaveBalanceRepo,
+		employeeRepo:       employeeRepo,
+		companyRepo:        companyRepo,
+		leaveRequestRepo:   leaveRequestRepo,
+		overtimePolicyRepo: overtimePolicyRepo,
+		publicHolidayRepo:  publicHolidayRepo,
+		leaveTypeRepo:      leaveTypeRepo,
+	}
+}
+
+// UpdateLeavePolicyRequest defines the payload for saving a company's leave policy.
+type UpdateLeavePolicyRequest struct {
+	AnnualQuota           float64 `json:"annual_quota" binding:"gte=0,lte=366"`
+	AccrualMethod         string  `json:"accrual_method" binding:"required,oneof=annual monthly"`
+	ProbationMonths       int     `json:"probation_months" binding:"gte=0,lte=24"`
+	CarryOverLimit        float64 `json:"carry_over_limit" binding:"gte=0,lte=366"`
+	CarryOverExpiryMonths int     `json:"carry_over_expiry_months" binding:"gte=0,lte=12"`
+	InsufficientBalance   string  `json:"insufficient_balance" binding:"required,oneof=reject warn"`
+	StartYear             int     `json:"start_year" binding:"omitempty,min=2000,max=2100"` // Kept when omitted, the current year for a new policy
+}
+
+// AdjustLeaveBalanceRequest defines the payload for correcting an employee's leave balance by hand.
+type AdjustLeaveBalanceRequest struct {
+	Days float64 `json:"days" binding:"required,ne=0"`               // Positive adds days, negative takes them away
+	Year int     `json:"year" binding:"omitempty,min=2000,max=2100"` // The current year when omitted
+	Note string  `json:"note" binding:"required"`
+}
+
+// LeaveBalance is an employee's annual leave for one year. Expired and Used are the days taken away.
+type LeaveBalance struct {
+	EmployeeID         int                       `json:"employee_id"`
+	EmployeeName       string                    `json:"employee_name"`
+	EmployeeIDNumber   string                    `json:"employee_id_number"`
+	Year               int                       `json:"year"`
+	Accrued            float64                   `json:"accrued"`
+	CarriedOver        float64                   `json:"carried_over"`
+	Expired            float64                   `json:"expired"`
+	Used               float64                   `json:"used"` // Approved leave, net of cancellations
+	Adjusted           float64                   `json:"adjusted"`
+	Balance            float64                   `json:"balance"`
+	Pending            float64                   `json:"pending"`   // Days in leave requests awaiting review
+	Available          float64                   `json:"available"` // Balance less pending days
+	CarryOverExpiresOn string                    `json:"carry_over_expires_on,omitempty"`
+	ProbationEndsOn    string                    `json:"probation_ends_on,omitempty"` // Set while the employee is on probation
+	Entries            []models.LeaveLedgerEntry `json:"entries,omitempty"`           // Entries due but not posted yet have no ID
+}
+
+func (s *leaveBalanceService) GetLeavePolicy(companyID int) (*models.LeavePolicy, error) {
+	policy, err := s.leaveBalanceRepo.GetLeavePolicyByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave policy: %w", err)
+	}
+	if policy == nil {
+		return nil, ErrLeavePolicyNotFound
+	}
+	return policy, nil
+}
+
+// UpdateLeavePolicy creates or updates the company's leave policy. Balances start in the policy's start year, and
+// changes apply to accruals that have not been posted yet.
+func (s *leaveBalanceService) UpdateLeavePolicy(companyID int, req UpdateLeavePolicyRequest) (*models.LeavePolicy, error) {
+	policy, err := s.leaveBalanceRepo.GetLeavePolicyByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave policy: %w", err)
+	}
+	if policy == nil {
+		policy = &models.LeavePolicy{CompanyID: companyID}
+	}
+
+	policy.AnnualQuota = req.AnnualQuota
+	policy.AccrualMethod = req.AccrualMethod
+	policy.ProbationMonths = req.ProbationMonths
+	policy.CarryOverLimit = req.CarryOverLimit
+	policy.CarryOverExpiryMonths = req.CarryOverExpiryMonths
+	policy.InsufficientBalance = req.InsufficientBalance
+	if req.StartYear != 0 {
+		policy.StartYear = req.StartYear
+	} else if policy.StartYear == 0 {
+		policy.StartYear = time.Now().Year()
+	}
+
+	if err := s.leaveBalanceRepo.SaveLeavePolicy(policy); err != nil {
+		return nil, fmt.Errorf("failed to save leave policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *leaveBalanceService) GetMyLeaveBalance(employeeID int, year int) (*LeaveBalance, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(employeeID)
+	if err != nil || employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+	return s.leaveBalance(employee, year, true)
+}
+
+func (s *leaveBalanceService) GetEmployeeLeaveBalance(companyID, employeeID int, year int) (*LeaveBalance, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(employeeID)
+	if err != nil || employee == nil || employee.CompanyID != companyID {
+		return nil, ErrEmployeeNotFound
+	}
+	return s.leaveBalance(employee, year, true)
+}
+
+// GetCompanyLeaveBalances returns a page of the company's employees with their balances, without ledger entries.
+func (s *leaveBalanceService) GetCompanyLeaveBalances(companyID int, year int, search string, page, pageSize int) ([]LeaveBalance, int64, error) {
+	if _, err := s.GetLeavePolicy(companyID); err != nil {
+		return nil, 0, err
+	}
+
+	employees, totalRecords, err := s.employeeRepo.GetEmployeesByCompanyIDPaginated(companyID, search, page, pageSize, nil, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve employees: %w", err)
+	}
+
+	balances := make([]LeaveBalance, 0, len(employees))
+	for i := range employees {
+		balance, err := s.leaveBalance(&employees[i], year, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		balances = append(balances, *balance)
+	}
+	return balances, totalRecords, nil
+}
+
+func (s *leaveBalanceService) AdjustLeaveBalance(adminID uint, companyID, employeeID int, req AdjustLeaveBalanceRequest) (*models.LeaveLedgerEntry, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(employeeID)
+	if err != nil || employee == nil || employee.CompanyID != companyID {
+		return nil, ErrEmployeeNotFound
+	}
+	account, err := loadLeaveAccount(s.leaveBalanceRepo, s.companyRepo, employee, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrLeavePolicyNotFound
+	}
+
+	effectiveDate := account.today
+	if req.Year != 0 && req.Year != effectiveDate.Year() {
+		effectiveDate = time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	entry := &models.LeaveLedgerEntry{
+		CompanyID:     employee.CompanyID,
+		EmployeeID:    employee.ID,
+		Year:          effectiveDate.Year(),
+		Kind:          models.LeaveEntryAdjustment,
+		Days:          roundLeaveDays(req.Days),
+		EffectiveDate: effectiveDate,
+		ActorType:     models.AttendanceActorAdmin,
+		ActorID:       &adminID,
+		Note:          req.Note,
+	}
+	if _, err := s.leaveBalanceRepo.CreateLeaveLedgerEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to adjust leave balance: %w", err)
+	}
+	return entry, nil
+}
+
+// AccrueLeaveBalances posts the accruals, carry-overs and expiries that have come due for every employee of the
+// companies with a leave policy, and returns how many entries were posted. It is the only writer of these entries:
+// balances read in between include the ones that are due without posting them.
+func (s *leaveBalanceService) AccrueLeaveBalances() (int, error) {
+	policies, err := s.leaveBalanceRepo.GetLeavePolicies()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve leave policies: %w", err)
+	}
+
+	now := time.Now()
+	posted := 0
+	for _, policy := range policies {
+		employees, err := s.employeeRepo.GetEmployeesByCompanyID(policy.CompanyID)
+		if err != nil {
+			log.Printf("Error getting employees of company %d for leave accrual: %v", policy.CompanyID, err)
+			continue
+		}
+		for i := range employees {
+			account, err := loadLeaveAccount(s.leaveBalanceRepo, s.companyRepo, &employees[i], now)
+			if err != nil {
+				log.Printf("Error accruing leave for employee %d: %v", employees[i].ID, err)
+				continue
+			}
+			if account == nil {
+				continue
+			}
+			created, err := account.postDue(s.leaveBalanceRepo)
+			posted += created
+			if err != nil {
+				log.Printf("Error accruing leave for employee %d: %v", employees[i].ID, err)
+			}
+		}
+	}
+	return posted, nil
+}
+
+// leaveBalance sums the employee's ledger, including the entries that have come due, for the year, the current one
+// when zero. Nothing is posted.
+func (s *leaveBalanceService) leaveBalance(employee *models.EmployeesTable, year int, withEntries bool) (*LeaveBalance, error) {
+	account, err := loadLeaveAccount(s.leaveBalanceRepo, s.companyRepo, employee, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrLeavePolicyNotFound
+	}
+	if year == 0 {
+		year = account.today.Year()
+	}
+
+	calendar, err := loadLeaveCalendar(s.overtimePolicyRepo, s.publicHolidayRepo, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := pendingLeaveDays(s.leaveRequestRepo, calendar, leaveTypes, employee.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := account.balance(year, pending[year])
+	if withEntries {
+		balance.Entries = account.entriesFor(year)
+	}
+	return &balance, nil
+}
+
+// leaveAccount is an employee's leave ledger under their company's leave policy. Dates are days in the company's
+// timezone, held as UTC midnight like leave request dates.
+type leaveAccount struct {
+	policy       *models.LeavePolicy
+	employee     *models.EmployeesTable
+	today        time.Time
+	eligibleFrom time.Time // The day probation ends and leave starts to accrue
+	firstYear    int       // The first year with a balance
+	entries      []models.LeaveLedgerEntry
+	references   map[string]bool
+	due          []models.LeaveLedgerEntry // Entries that have come due but are not posted yet
+}
+
+// loadLeaveAccount loads the employee's ledger and adds the accruals, carry-overs and expiries that have come due
+// to it, without posting them. It returns nil when the employee's company has no leave policy.
+func loadLeaveAccount(leaveBalanceRepo repository.LeaveBalanceRepository, companyRepo repository.CompanyRepository, employee *models.EmployeesTable, now time.Time) (*leaveAccount, error) {
+	policy, err := leaveBalanceRepo.GetLeavePolicyByCompanyID(employee.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave policy: %w", err)
+	}
+	if policy == nil {
+		return nil, nil
+	}
+	company, err := companyRepo.GetCompanyByID(employee.CompanyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	location, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	entries, err := leaveBalanceRepo.GetLeaveLedgerEntriesByEmployeeID(employee.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave ledger: %w", err)
+	}
+
+	joinedOn := leaveDate(employee.CreatedAt.In(location))
+	if employee.JoinDate != nil {
+		joinedOn = leaveDate(*employee.JoinDate)
+	}
+	account := &leaveAccount{
+		policy:       policy,
+		employee:     employee,
+		today:        leaveDate(now.In(location)),
+		eligibleFrom: joinedOn.AddDate(0, policy.ProbationMonths, 0),
+		firstYear:    policy.StartYear,
+		references:   make(map[string]bool, len(entries)),
+	}
+	if account.eligibleFrom.Year() > account.firstYear {
+		account.firstYear = account.eligibleFrom.Year()
+	}
+	for _, entry := range entries {
+		entry.EffectiveDate = leaveDate(entry.EffectiveDate)
+		account.entries = append(account.entries, entry)
+		if entry.Reference != nil {
+			account.references[*entry.Reference] = true
+		}
+	}
+
+	account.addDueEntries()
+	return account, nil
+}
+
+// addDueEntries adds what has come due up to today to the ledger, year by year: the carry-over from the previous
+// year, the accruals and the expiry of carried-over days that were not taken in time. They are held in memory
+// until postDue saves them.
+func (a *leaveAccount) addDueEntries() {
+	for year := a.firstYear; year <= a.today.Year(); year++ {
+		if carried := a.carryOver(year); carried > 0 {
+			entry := a.systemEntry(models.LeaveEntryCarryOver, year, carried, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), fmt.Sprintf("carry_over:%d:%d", a.employee.ID, year))
+			entry.Note = fmt.Sprintf("Carried over from %d", year-1)
+			a.addDue(entry)
+		}
+
+		for _, accrual := range a.accruals(year) {
+			if accrual.EffectiveDate.After(a.today) {
+				break
+			}
+			a.addDue(accrual)
+		}
+
+		if expiresOn, ok := a.carryOverExpiry(year); ok && !a.today.Before(expiresOn) {
+			if unused := a.unusedCarryOver(year, expiresOn); unused > 0 {
+				entry := a.systemEntry(models.LeaveEntryExpiry, year, -unused, expiresOn, fmt.Sprintf("expiry:%d:%d", a.employee.ID, year))
+				entry.Note = "Carried-over days not taken before they expired"
+				a.addDue(entry)
+			}
+		}
+	}
+}
+
+// addDue adds an entry that has come due unless one with the same reference has been posted before.
+func (a *leaveAccount) addDue(entry models.LeaveLedgerEntry) {
+	if entry.Reference != nil {
+		if a.references[*entry.Reference] {
+			return
+		}
+		a.references[*entry.Reference] = true
+	}
+	a.entries = append(a.entries, entry)
+	a.due = append(a.due, entry)
+}
+
+// postDue saves the entries that have come due and returns how many were posted. Entries posted by another run in
+// the meantime are skipped.
+func (a *leaveAccount) postDue(leaveBalanceRepo repository.LeaveBalanceRepository) (int, error) {
+	posted := 0
+	for i := range a.due {
+		created, err := leaveBalanceRepo.CreateLeaveLedgerEntry(&a.due[i])
+		if err != nil {
+			return posted, fmt.Errorf("failed to post leave ledger entry: %w", err)
+		}
+		if created {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// systemEntry builds an entry posted by the scheduler.
+func (a *leaveAccount) systemEntry(kind string, year int, days float64, effectiveDate time.Time, reference string) models.LeaveLedgerEntry {
+	return models.LeaveLedgerEntry{
+		CompanyID:     a.employee.CompanyID,
+		EmployeeID:    a.employee.ID,
+		Year:          year,
+		Kind:          kind,
+		Days:          days,
+		EffectiveDate: effectiveDate,
+		Reference:     &reference,
+		ActorType:     models.AttendanceActorSystem,
+	}
+}
+
+// accruals returns the accrual entries of a year, due or not, in date order. Under annual accrual the year
+// probation ends grants the months left after it; under monthly accrual the months before it accrue nothing.
+func (a *leaveAccount) accruals(year int) []models.LeaveLedgerEntry {
+	if year < a.firstYear {
+		return nil
+	}
+	quota := a.policy.AnnualQuota
+	var entries []models.LeaveLedgerEntry
+
+	if a.policy.AccrualMethod == models.LeaveAccrualMonthly {
+		for month := 1; month <= 12; month++ {
+			date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			if date.Before(a.eligibleFrom) {
+				continue
+			}
+			// Accrue the difference of the rounded running totals, so the year adds up to the quota exactly.
+			days := roundLeaveDays(roundLeaveDays(quota*float64(month)/12) - roundLeaveDays(quota*float64(month-1)/12))
+			entry := a.systemEntry(models.LeaveEntryAccrual, year, days, date, fmt.Sprintf("accrual:%d:%d-%02d", a.employee.ID, year, month))
+			entry.Note = fmt.Sprintf("Accrual for %s", date.Format("January 2006"))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := quota
+	if a.eligibleFrom.After(date) {
+		date = a.eligibleFrom
+		months := 12 - int(date.Month())
+		if date.Day() == 1 {
+			months++
+		}
+		days = roundLeaveDays(quota * float64(months) / 12)
+	}
+	if days > 0 {
+		entry := a.systemEntry(models.LeaveEntryAccrual, year, days, date, fmt.Sprintf("accrual:%d:%d", a.employee.ID, year))
+		entry.Note = fmt.Sprintf("Annual leave for %d", year)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// carryOver returns the days the previous year's balance carries into the year, up to the policy's limit.
+func (a *leaveAccount) carryOver(year int) float64 {
+	if a.policy.CarryOverLimit <= 0 || year <= a.firstYear {
+		return 0
+	}
+	return roundLeaveDays(math.Min(a.total(year-1), a.policy.CarryOverLimit))
+}
+
+// carryOverExpiry returns the day carried-over days expire in the year, if they expire at all.
+func (a *leaveAccount) carryOverExpiry(year int) (time.Time, bool) {
+	if a.policy.CarryOverExpiryMonths <= 0 || a.total(year, models.LeaveEntryCarryOver) <= 0 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, a.policy.CarryOverExpiryMonths, 0), true
+}
+
+// unusedCarryOver returns the carried-over days not taken before they expire. Leave is taken from carried-over
+// days first.
+func (a *leaveAccount) unusedCarryOver(year int, expiresOn time.Time) float64 {
+	carried := a.total(year, models.LeaveEntryCarryOver)
+	used := 0.0
+	for _, entry := range a.entries {
+		if entry.Year == year && entry.EffectiveDate.Before(expiresOn) && (entry.Kind == models.LeaveEntryDebit || entry.Kind == models.LeaveEntryCredit) {
+			used -= entry.Days
+		}
+	}
+	return roundLeaveDays(math.Max(carried-math.Max(used, 0), 0))
+}
+
+// total sums the year's entries of the given kinds, or all of them.
+func (a *leaveAccount) total(year int, kinds ...string) float64 {
+	sum := 0.0
+	for _, entry := range a.entries {
+		if entry.Year != year {
+			continue
+		}
+		if len(kinds) > 0 {
+			matched := false
+			for _, kind := range kinds {
+				matched = matched || entry.Kind == kind
+			}
+			if !matched {
+				continue
+			}
+		}
+		sum += entry.Days
+	}
+	return roundLeaveDays(sum)
+}
+
+// projected returns the year's balance on a future day: the ledger plus the accruals due by then, less the
+// carried-over days that will have expired unused. Carry-over into a year that has not started is not known yet.
+func (a *leaveAccount) projected(year int, on time.Time) float64 {
+	balance := a.total(year)
+	for _, accrual := range a.accruals(year) {
+		if accrual.EffectiveDate.After(on) {
+			break
+		}
+		if accrual.Reference != nil && !a.references[*accrual.Reference] {
+			balance += accrual.Days
+		}
+	}
+	if expiresOn, ok := a.carryOverExpiry(year); ok && a.today.Before(expiresOn) && !on.Before(expiresOn) {
+		balance -= a.unusedCarryOver(year, expiresOn)
+	}
+	return roundLeaveDays(balance)
+}
+
+// check reports whether leave starting on startDate, taking daysByYear, fits in the balance left after pending
+// requests. It returns ErrLeaveDuringProbation or ErrInsufficientLeaveBalance when it does not.
+func (a *leaveAccount) check(startDate time.Time, daysByYear, pending map[int]float64) error {
+	if startDate.Before(a.eligibleFrom) {
+		return fmt.Errorf("%w, which ends on %s", ErrLeaveDuringProbation, a.eligibleFrom.Format("2006-01-02"))
+	}
+
+	years := make([]int, 0, len(daysByYear))
+	for year := range daysByYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	for _, year := range years {
+		on := startDate
+		if on.Year() < year {
+			on = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		available := roundLeaveDays(a.projected(year, on) - pending[year])
+		if daysByYear[year] > available {
+			return fmt.Errorf("%w: %s days requested in %d but %s available", ErrInsufficientLeaveBalance, formatLeaveDays(daysByYear[year]), year, formatLeaveDays(math.Max(available, 0)))
+		}
+	}
+	return nil
+}
+
+// balance sums the ledger of a year.
+func (a *leaveAccount) balance(year int, pending float64) LeaveBalance {
+	balance := LeaveBalance{
+		EmployeeID:       a.employee.ID,
+		EmployeeName:     a.employee.Name,
+		EmployeeIDNumber: a.employee.EmployeeIDNumber,
+		Year:             year,
+		Accrued:          a.total(year, models.LeaveEntryAccrual),
+		CarriedOver:      a.total(year, models.LeaveEntryCarryOver),
+		Expired:          roundLeaveDays(-a.total(year, models.LeaveEntryExpiry)),
+		Used:             roundLeaveDays(-a.total(year, models.LeaveEntryDebit, models.LeaveEntryCredit)),
+		Adjusted:         a.total(year, models.LeaveEntryAdjustment),
+		Balance:          a.total(year),
+		Pending:          roundLeaveDays(pending),
+	}
+	balance.Available = roundLeaveDays(balance.Balance - balance.Pending)
+	if expiresOn, ok := a.carryOverExpiry(year); ok {
+		balance.CarryOverExpiresOn = expiresOn.Format("2006-01-02")
+	}
+	if a.today.Before(a.eligibleFrom) {
+		balance.ProbationEndsOn = a.eligibleFrom.Format("2006-01-02")
+	}
+	return balance
+}
+
+// entriesFor returns the ledger entries of a year.
+func (a *leaveAccount) entriesFor(year int) []models.LeaveLedgerEntry {
+	entries := []models.LeaveLedgerEntry{}
+	for _, entry := range a.entries {
+		if entry.Year == year {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// debitEntries builds the entries that take approved leave from the balance of each year it falls in.
+func debitEntries(leaveRequest *models.LeaveRequest, companyID int, daysByYear map[int]float64, adminID uint) []models.LeaveLedgerEntry {
+	years := make([]int, 0, len(daysByYear))
+	for year := range daysByYear {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	var entries []models.LeaveLedgerEntry
+	for _, year := range years {
+		days := daysByYear[year]
+		if days <= 0 {
+			continue
+		}
+		effectiveDate := leaveDate(leaveRequest.StartDate)
+		if effectiveDate.Year() < year {
+			effectiveDate = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		reference := fmt.Sprintf("debit:%d:%d", leaveRequest.ID, year)
+		entries = append(entries, models.LeaveLedgerEntry{
+			CompanyID:      companyID,
+			EmployeeID:     int(leaveRequest.EmployeeID),
+			Year:           year,
+			Kind:           models.LeaveEntryDebit,
+			Days:           -days,
+			EffectiveDate:  effectiveDate,
+			LeaveRequestID: &leaveRequest.ID,
+			Reference:      &reference,
+			ActorType:      models.AttendanceActorAdmin,
+			ActorID:        &adminID,
+			Note:           fmt.Sprintf("Leave from %s to %s", leaveRequest.StartDate.Format("2006-01-02"), leaveRequest.EndDate.Format("2006-01-02")),
+		})
+	}
+	return entries
+}
+
+// creditEntries builds the entries that give back the days debited for leave cancelled after approval.
+func creditEntries(leaveBalanceRepo repository.LeaveBalanceRepository, leaveRequest *models.LeaveRequest, adminID uint) ([]models.LeaveLedgerEntry, error) {
+	posted, err := leaveBalanceRepo.GetLeaveLedgerEntriesByLeaveRequestID(leaveRequest.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave ledger entries: %w", err)
+	}
+	var entries []models.LeaveLedgerEntry
+	for _, debit := range posted {
+		if debit.Kind != models.LeaveEntryDebit {
+			continue
+		}
+		reference := fmt.Sprintf("credit:%d:%d", leaveRequest.ID, debit.Year)
+		entries = append(entries, models.LeaveLedgerEntry{
+			CompanyID:      debit.CompanyID,
+			EmployeeID:     debit.EmployeeID,
+			Year:           debit.Year,
+			Kind:           models.LeaveEntryCredit,
+			Days:           -debit.Days,
+			EffectiveDate:  leaveDate(debit.EffectiveDate), // Cancelled days no longer count as taken before carry-over expires
+			LeaveRequestID: &leaveRequest.ID,
+			Reference:      &reference,
+			ActorType:      models.AttendanceActorAdmin,
+			ActorID:        &adminID,
+			Note:           "Approved leave cancelled",
+		})
+	}
+	return entries, nil
+}
+
+// pendingLeaveDays returns the days awaiting review per year of leave types that count against the balance,
+// leaving out one request.
+func pendingLeaveDays(leaveRequestRepo repository.LeaveRequestRepository, calendar *leaveCalendar, leaveTypes map[string]models.LeaveType, employeeID int, excludeID uint) (map[int]float64, error) {
+	pendingRequests, err := leaveRequestRepo.GetPendingLeaveRequestsByEmployeeID(employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending leave requests: %w", err)
+	}
+	pending := make(map[int]float64)
+	for _, leaveRequest := range pendingRequests {
+		if leaveRequest.ID == excludeID || !leaveTypes[leaveRequest.Type].CountsAgainstBalance {
+			continue
+		}
+		for year, days := range calendar.daysByYear(leaveRequest.StartDate, leaveRequest.EndDate) {
+			pending[year] += days
+		}
+	}
+	return pending, nil
+}
+
+// leaveCalendar counts the working days leave takes: rest days and public holidays are not taken from the balance.
+type leaveCalendar struct {
+	restDays map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+// loadLeaveCalendar uses the rest days of the company's overtime policy and its public holidays.
+func loadLeaveCalendar(overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, companyID int) (*leaveCalendar, error) {
+	policy, err := loadOvertimePolicy(overtimePolicyRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := publicHolidayRepo.GetPublicHolidaysByCompanyID(companyID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve public holidays: %w", err)
+	}
+
+	calendar := &leaveCalendar{
+		restDays: parseRestDays(policy.RestDays),
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[holiday.Date.Format("2006-01-02")] = true
+	}
+	return calendar, nil
+}
+
+// daysByYear counts the working days from startDate to endDate inclusive, per year.
+func (c *leaveCalendar) daysByYear(startDate, endDate time.Time) map[int]float64 {
+	days := make(map[int]float64)
+	for day := leaveDate(startDate); !day.After(leaveDate(endDate)); day = day.AddDate(0, 0, 1) {
+		if c.restDays[day.Weekday()] || c.holidays[day.Format("2006-01-02")] {
+			continue
+		}
+		days[day.Year()]++
+	}
+	return days
+}
+
+// leaveDate returns the calendar day of t as UTC midnight.
+func leaveDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// roundLeaveDays rounds a number of days to two decimals.
+func roundLeaveDays(days float64) float64 {
+	rounded := math.Round(days*100) / 100
+	if rounded == 0 {
+		return 0 // Not -0, which would be shown as such
+	}
+	return rounded
+}
+
+// formatLeaveDays prints a number of days without trailing zeros, e.g. "3" or "2.5".
+func formatLeaveDays(days float64) string {
+	return fmt.Sprintf("%g", roundLeaveDays(days))
+}
+
+// sumLeaveDays adds up the days of every year.
+func sumLeaveDays(daysByYear map[int]float64) float64 {
+	total := 0.0
+	for _, days := range daysByYear {
+		total += days
+	}
+	return total
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"go-face-auth/database/repository"
 	"go-face-auth/models"
@@ -17,27 +18,41 @@ type LeaveRequestService interface {
 	GetMyLeaveRequests(employeeID uint, startDate, endDate *time.Time) ([]models.LeaveRequest, error)
 	GetAllCompanyLeaveRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.LeaveRequest, int64, error)
 	ReviewLeaveRequest(leaveRequestID, adminID uint, status string) (*models.LeaveRequest, error)
-	ExportCompanyLeaveRequests(companyID int, status, search string, startDate, endDate *time.Time) ([]models.LeaveRequest, error)
+	ExportCompanyLeaveRequests(companyID int, status, search string, startDate, endDate *time.Time) (*helper.Report, error)
 	CancelLeaveRequest(leaveRequestID uint, employeeID uint) (*models.LeaveRequest, error)
 	AdminCancelApprovedLeave(leaveRequestID uint, adminID uint) (*models.LeaveRequest, error)
 }
 
 // leaveRequestService is the concrete implementation of LeaveRequestService.
 type leaveRequestService struct {
-	employeeRepo     repository.EmployeeRepository
-	leaveRequestRepo repository.LeaveRequestRepository
-	adminCompanyRepo repository.AdminCompanyRepository
+	employeeRepo       repository.EmployeeRepository
+	leaveRequestRepo   repository.LeaveRequestRepository
+	adminCompanyRepo   repository.AdminCompanyRepository
+	companyRepo        repository.CompanyRepository
+	leaveBalanceRepo   repository.LeaveBalanceRepository
+	overtimePolicyRepo repository.OvertimePolicyRepository
+	publicHolidayRepo  repository.PublicHolidayRepository
+	leaveTypeRepo      repository.LeaveTypeRepository
 }
 
 // NewLeaveRequestService creates a new instance of LeaveRequestService.
-func NewLeaveRequestService(employeeRepo repository.EmployeeRepository, leaveRequestRepo repository.LeaveRequestRepository, adminCompanyRepo repository.AdminCompanyRepository) LeaveRequestService {
+func NewLeaveRequestService(employeeRepo repository.EmployeeRepository, leaveRequestRepo repository.LeaveRequestRepository, adminCompanyRepo repository.AdminCompanyRepository, companyRepo repository.CompanyRepository, leaveBalanceRepo repository.LeaveBalanceRepository, overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, leaveTypeRepo repository.LeaveTypeRepository) LeaveRequestService {
 	return &leaveRequestService{
-		employeeRepo:     employeeRepo,
-		leaveRequestRepo: leaveRequestRepo,
-		adminCompanyRepo: adminCompanyRepo,
+		employeeRepo:       employeeRepo,
+		leaveRequestRepo:   leaveRequestRepo,
+		adminCompanyRepo:   adminCompanyRepo,
+		companyRepo:        companyRepo,
+		leaveBalanceRepo:   leaveBalanceRepo,
+		overtimePolicyRepo: overtimePolicyRepo,
+		publicHolidayRepo:  publicHolidayRepo,
+		leaveTypeRepo:      leaveTypeRepo,
 	}
 }
 
+// ApplyLeave submits a leave request for one of the company's active leave types, following the type's rules on
+// attachments and length. When the company has a leave policy, types that count against the balance are checked
+// against what is left after other pending requests: depending on the policy a shortfall rejects the request or
+// is returned as a warning on it.
 func (s *leaveRequestService) ApplyLeave(employeeID uint, leaveType, startDateStr, endDateStr, reason string, sickNote *multipart.FileHeader) (*models.LeaveRequest, error) {
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
@@ -57,13 +72,46 @@ func (s *leaveRequestService) ApplyLeave(employeeID uint, leaveType, startDateSt
 		return nil, fmt.Errorf("employee not found")
 	}
 
+	calendar, err := loadLeaveCalendar(s.overtimePolicyRepo, s.publicHolidayRepo, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	daysByYear := calendar.daysByYear(startDate, endDate)
+
+	leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	catalogued, ok := leaveTypes[leaveType]
+	if !ok || !catalogued.IsActive {
+		return nil, ErrUnknownLeaveType
+	}
+	if catalogued.RequiresAttachment && sickNote == nil {
+		return nil, ErrLeaveAttachmentRequired
+	}
+	if catalogued.MaxDaysPerRequest > 0 && sumLeaveDays(daysByYear) > float64(catalogued.MaxDaysPerRequest) {
+		return nil, fmt.Errorf("%w: at most %d working days", ErrLeaveTooLong, catalogued.MaxDaysPerRequest)
+	}
+
+	var balanceWarning string
+	if catalogued.CountsAgainstBalance {
+		balanceWarning, err = s.checkLeaveBalance(employee, calendar, startDate, daysByYear, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var sickNotePath string
-	if leaveType == "sakit" && sickNote != nil {
-		// Save the sick note file
-		subDir := filepath.Join("sick_notes", strconv.Itoa(employee.CompanyID), strconv.Itoa(int(employeeID)))
+	if sickNote != nil {
+		// Save the attachment, keeping sick notes where they have always been
+		dir := "leave_attachments"
+		if catalogued.AttendanceStatus == models.AttendanceStatusOnSick {
+			dir = "sick_notes"
+		}
+		subDir := filepath.Join(dir, strconv.Itoa(employee.CompanyID), strconv.Itoa(int(employeeID)))
 		sickNotePath, err = helper.SaveUploadedFile(sickNote, subDir)
 		if err != nil {
-			return nil, fmt.Errorf("failed to save sick note file: %w", err)
+			return nil, fmt.Errorf("failed to save leave attachment: %w", err)
 		}
 	}
 
@@ -72,6 +120,7 @@ func (s *leaveRequestService) ApplyLeave(employeeID uint, leaveType, startDateSt
 		Type:       leaveType,
 		StartDate:  startDate,
 		EndDate:    endDate,
+		Days:       sumLeaveDays(daysByYear),
 		Reason:     reason,
 		Status:     "pending",
 		SickNotePath: sickNotePath,
@@ -80,6 +129,7 @@ func (s *leaveRequestService) ApplyLeave(employeeID uint, leaveType, startDateSt
 	if err := s.leaveRequestRepo.CreateLeaveRequest(leaveRequest); err != nil {
 		return nil, fmt.Errorf("failed to submit leave request: %w", err)
 	}
+	leaveRequest.BalanceWarning = balanceWarning
 
 	return leaveRequest, nil
 }
@@ -117,20 +167,77 @@ func (s *leaveRequestService) ReviewLeaveRequest(leaveRequestID, adminID uint, s
 		return nil, fmt.Errorf("only pending leave requests can be reviewed")
 	}
 
+	// Approved leave of a type that counts against the balance is checked against it again, as it may have
+	// changed since the request, and debited from it.
+	var daysByYear map[int]float64
+	var balanceWarning string
+	var countsAgainstBalance bool
+	if status == "approved" {
+		calendar, err := loadLeaveCalendar(s.overtimePolicyRepo, s.publicHolidayRepo, employee.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		daysByYear = calendar.daysByYear(leaveRequest.StartDate, leaveRequest.EndDate)
+		leaveRequest.Days = sumLeaveDays(daysByYear)
+
+		leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, employee.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		countsAgainstBalance = leaveTypes[leaveRequest.Type].CountsAgainstBalance
+		if countsAgainstBalance {
+			balanceWarning, err = s.checkLeaveBalance(employee, calendar, leaveRequest.StartDate, daysByYear, leaveRequest.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	leaveRequest.Status = status
 	leaveRequest.ReviewedBy = &adminID
 	now := time.Now()
 	leaveRequest.ReviewedAt = &now
 
-	if err := s.leaveRequestRepo.UpdateLeaveRequest(leaveRequest); err != nil {
+	// The debit is posted with the status, so an approval never lands without it.
+	var entries []models.LeaveLedgerEntry
+	if status == "approved" && countsAgainstBalance {
+		policy, err := s.leaveBalanceRepo.GetLeavePolicyByCompanyID(employee.CompanyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve leave policy: %w", err)
+		}
+		if policy != nil {
+			entries = debitEntries(leaveRequest, employee.CompanyID, daysByYear, adminID)
+		}
+	}
+
+	if err := s.leaveRequestRepo.UpdateLeaveRequestWithLedgerEntries(leaveRequest, entries); err != nil {
 		return nil, fmt.Errorf("failed to update leave request status: %w", err)
 	}
+	leaveRequest.BalanceWarning = balanceWarning
 
 	return leaveRequest, nil
 }
 
-func (s *leaveRequestService) ExportCompanyLeaveRequests(companyID int, status, search string, startDate, endDate *time.Time) ([]models.LeaveRequest, error) {
-	return s.leaveRequestRepo.GetCompanyLeaveRequestsFiltered(companyID, status, search, startDate, endDate)
+func (s *leaveRequestService) ExportCompanyLeaveRequests(companyID int, status, search string, startDate, endDate *time.Time) (*helper.Report, error) {
+	leaveRequests, err := s.leaveRequestRepo.GetCompanyLeaveRequestsFiltered(companyID, status, search, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave requests for export: %w", err)
+	}
+
+	table := helper.ReportTable{
+		Name:    "Leave Requests",
+		Headers: []string{"Employee Name", "Type", "Start Date", "End Date", "Reason", "Status"},
+	}
+	for _, lr := range leaveRequests {
+		table.Rows = append(table.Rows, []interface{}{lr.Employee.Name, lr.Type, lr.StartDate.Format("2006-01-02"), lr.EndDate.Format("2006-01-02"), lr.Reason, lr.Status})
+	}
+
+	return &helper.Report{
+		Title:    "Leave Requests",
+		Subtitle: helper.ReportPeriod(startDate, endDate),
+		FileName: fmt.Sprintf("company_leave_requests%s", helper.ReportDateRangeSuffix(startDate, endDate)),
+		Tables:   []helper.ReportTable{table},
+	}, nil
 }
 
 // CancelLeaveRequest allows an employee to cancel their pending leave request.
@@ -198,9 +305,44 @@ func (s *leaveRequestService) AdminCancelApprovedLeave(leaveRequestID uint, admi
 	leaveRequest.CancelledByActorType = "admin"
 	leaveRequest.CancelledByActorID = &adminID
 
-	if err := s.leaveRequestRepo.UpdateLeaveRequest(leaveRequest); err != nil {
+	// The days debited on approval are credited back with the cancellation.
+	entries, err := creditEntries(s.leaveBalanceRepo, leaveRequest, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.leaveRequestRepo.UpdateLeaveRequestWithLedgerEntries(leaveRequest, entries); err != nil {
 		return nil, fmt.Errorf("failed to cancel approved leave request: %w", err)
 	}
 
 	return leaveRequest, nil
+}
+
+// checkLeaveBalance checks leave that counts against the balance against the employee's balance, leaving out the pending request being
+// reviewed. It returns the shortfall as a warning when the leave policy allows it, and as an error when it does
+// not. Employees of companies without a leave policy have unlimited leave.
+func (s *leaveRequestService) checkLeaveBalance(employee *models.EmployeesTable, calendar *leaveCalendar, startDate time.Time, daysByYear map[int]float64, excludeID uint) (string, error) {
+	account, err := loadLeaveAccount(s.leaveBalanceRepo, s.companyRepo, employee, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if account == nil {
+		return "", nil
+	}
+	leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, employee.CompanyID)
+	if err != nil {
+		return "", err
+	}
+	pending, err := pendingLeaveDays(s.leaveRequestRepo, calendar, leaveTypes, employee.ID, excludeID)
+	if err != nil {
+		return "", err
+	}
+
+	err = account.check(startDate, daysByYear, pending)
+	if err == nil {
+		return "", nil
+	}
+	if account.policy.InsufficientBalance == models.InsufficientLeaveWarn && (errors.Is(err, ErrInsufficientLeaveBalance) || errors.Is(err, ErrLeaveDuringProbation)) {
+		return err.Error(), nil
+	}
+	return "", err
 }
\ No newline at end of file
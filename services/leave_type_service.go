@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"regexp"
+	"strings"
+)
+
+// leaveTypeCodePattern keeps codes usable as stable identifiers in leave requests and reports.
+var leaveTypeCodePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// LeaveTypeService defines the interface for a company's leave type catalogue.
+type LeaveTypeService interface {
+	GetLeaveTypes(companyID int, activeOnly bool) ([]models.LeaveType, error)
+	CreateLeaveType(companyID int, req CreateLeaveTypeRequest) (*models.LeaveType, error)
+	UpdateLeaveType(companyID int, leaveTypeID uint, req LeaveTypeRequest) (*models.LeaveType, error)
+}
+
+// leaveTypeService is the concrete implementation of LeaveTypeService.
+type leaveTypeService struct {
+	leaveTypeRepo repository.LeaveTypeRepository
+}
+
+// NewLeaveTypeService creates a new instance of LeaveTypeService.
+func NewLeaveTypeService(leaveTypeRepo repository.LeaveTypeRepository) LeaveTypeService {
+	return &leaveTypeService{
+		leaveTypeRepo: leaveTypeRepo,
+	}
+}
+
+// LeaveTypeRequest defines the payload for a leave type's settings. IsPaid and IsActive default to true.
+type LeaveTypeRequest struct {
+	Name                 string `json:"name" binding:"required,max=100"`
+	IsPaid               *bool  `json:"is_paid"`
+	RequiresAttachment   bool   `json:"requires_attachment"`
+	MaxDaysPerRequest    int    `json:"max_days_per_request" binding:"min=0,max=366"`
+	CountsAgainstBalance bool   `json:"counts_against_balance"`
+	AttendanceStatus     string `json:"attendance_status" binding:"omitempty,oneof=on_leave on_sick"`
+	IsActive             *bool  `json:"is_active"`
+}
+
+// CreateLeaveTypeRequest defines the payload for adding a leave type. The code cannot be changed afterwards.
+type CreateLeaveTypeRequest struct {
+	Code string `json:"code" binding:"required,max=50"`
+	LeaveTypeRequest
+}
+
+// GetLeaveTypes lists the company's leave types, adding the default ones the first time.
+func (s *leaveTypeService) GetLeaveTypes(companyID int, activeOnly bool) ([]models.LeaveType, error) {
+	leaveTypes, err := loadLeaveTypes(s.leaveTypeRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !activeOnly {
+		return leaveTypes, nil
+	}
+	active := []models.LeaveType{}
+	for _, leaveType := range leaveTypes {
+		if leaveType.IsActive {
+			active = append(active, leaveType)
+		}
+	}
+	return active, nil
+}
+
+func (s *leaveTypeService) CreateLeaveType(companyID int, req CreateLeaveTypeRequest) (*models.LeaveType, error) {
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	if !leaveTypeCodePattern.MatchString(code) {
+		return nil, ErrInvalidLeaveTypeCode
+	}
+	// Make sure the defaults exist first, so they cannot be taken by a type with different rules.
+	if _, err := loadLeaveTypes(s.leaveTypeRepo, companyID); err != nil {
+		return nil, err
+	}
+
+	leaveType := &models.LeaveType{CompanyID: companyID, Code: code}
+	req.apply(leaveType)
+
+	created, err := s.leaveTypeRepo.CreateLeaveType(leaveType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create leave type: %w", err)
+	}
+	if !created {
+		return nil, ErrLeaveTypeCodeTaken
+	}
+	return leaveType, nil
+}
+
+// UpdateLeaveType changes a leave type's settings. Leave types are deactivated rather than deleted, as leave
+// requests refer to them.
+func (s *leaveTypeService) UpdateLeaveType(companyID int, leaveTypeID uint, req LeaveTypeRequest) (*models.LeaveType, error) {
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(leaveTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave type: %w", err)
+	}
+	if leaveType == nil || leaveType.CompanyID != companyID {
+		return nil, ErrLeaveTypeNotFound
+	}
+
+	req.apply(leaveType)
+	if err := s.leaveTypeRepo.UpdateLeaveType(leaveType); err != nil {
+		return nil, fmt.Errorf("failed to update leave type: %w", err)
+	}
+	return leaveType, nil
+}
+
+// apply copies the requested settings onto a leave type.
+func (req LeaveTypeRequest) apply(leaveType *models.LeaveType) {
+	leaveType.Name = strings.TrimSpace(req.Name)
+	leaveType.IsPaid = req.IsPaid == nil || *req.IsPaid
+	leaveType.RequiresAttachment = req.RequiresAttachment
+	leaveType.MaxDaysPerRequest = req.MaxDaysPerRequest
+	leaveType.CountsAgainstBalance = req.CountsAgainstBalance
+	leaveType.AttendanceStatus = models.AttendanceStatusOnLeave
+	if req.AttendanceStatus != "" {
+		leaveType.AttendanceStatus = models.AttendanceStatus(req.AttendanceStatus)
+	}
+	leaveType.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+// defaultLeaveTypes are the leave types every company starts with, matching how leave worked before companies
+// could define their own.
+func defaultLeaveTypes(companyID int) []models.LeaveType {
+	return []models.LeaveType{
+		{CompanyID: companyID, Code: models.LeaveTypeAnnual, Name: "Cuti", IsPaid: true, CountsAgainstBalance: true, AttendanceStatus: models.AttendanceStatusOnLeave, IsActive: true},
+		{CompanyID: companyID, Code: models.LeaveTypeSick, Name: "Sakit", IsPaid: true, RequiresAttachment: true, AttendanceStatus: models.AttendanceStatusOnSick, IsActive: true},
+	}
+}
+
+// loadLeaveTypes returns a company's leave types, adding the default ones when it has none yet.
+func loadLeaveTypes(leaveTypeRepo repository.LeaveTypeRepository, companyID int) ([]models.LeaveType, error) {
+	leaveTypes, err := leaveTypeRepo.GetLeaveTypesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave types: %w", err)
+	}
+	if len(leaveTypes) > 0 {
+		return leaveTypes, nil
+	}
+
+	for _, leaveType := range defaultLeaveTypes(companyID) {
+		if _, err := leaveTypeRepo.CreateLeaveType(&leaveType); err != nil {
+			return nil, fmt.Errorf("failed to create default leave types: %w", err)
+		}
+	}
+	leaveTypes, err = leaveTypeRepo.GetLeaveTypesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave types: %w", err)
+	}
+	return leaveTypes, nil
+}
+
+// leaveTypesByCode indexes a company's leave types by code.
+func leaveTypesByCode(leaveTypeRepo repository.LeaveTypeRepository, companyID int) (map[string]models.LeaveType, error) {
+	leaveTypes, err := loadLeaveTypes(leaveTypeRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]models.LeaveType, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		byCode[leaveType.Code] = leaveType
+	}
+	return byCode, nil
+}
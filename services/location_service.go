@@ -1,12 +1,23 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"go-face-auth/database/repository"
+	"go-face-auth/helper"
 	"go-face-auth/models"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 )
 
 var ErrLocationLimitReached = fmt.Errorf("location limit reached for your subscription package")
+var ErrInvalidLocationGeometry = errors.New("invalid location geometry")
+var ErrInvalidLocationProofs = errors.New("invalid location proof settings")
+var ErrLocationQRCodeDisabled = errors.New("QR code check-in is not enabled for this location")
+var ErrLocationNotFound = errors.New("location not found")
 
 // LocationService defines the interface for location related business logic.
 type LocationService interface {
@@ -14,6 +25,8 @@ type LocationService interface {
 	GetAttendanceLocationsByCompanyID(companyID uint) ([]*models.AttendanceLocation, error)
 	UpdateAttendanceLocation(companyID, locationID uint, locationUpdates *models.AttendanceLocation) (*models.AttendanceLocation, error)
 	DeleteAttendanceLocation(companyID, locationID uint) error
+	ImportAttendanceLocations(companyID uint, fileName string, data []byte) ([]*models.AttendanceLocation, error)
+	GetLocationQRCode(companyID, locationID uint) (string, time.Time, error)
 }
 
 // locationService is the concrete implementation of LocationService.
@@ -31,13 +44,30 @@ func NewLocationService(companyRepo repository.CompanyRepository, attendanceLoca
 }
 
 func (s *locationService) CreateAttendanceLocation(companyID uint, location *models.AttendanceLocation) (*models.AttendanceLocation, error) {
+	if err := prepareLocationShape(location); err != nil {
+		return nil, err
+	}
+	if err := prepareLocationProofs(location); err != nil {
+		return nil, err
+	}
+	if err := s.checkLocationLimit(companyID, 1); err != nil {
+		return nil, err
+	}
+
+	location.CompanyID = companyID
+
+	return s.attendanceLocationRepo.CreateAttendanceLocation(location)
+}
+
+// checkLocationLimit makes sure the company's subscription allows adding the given number of locations.
+func (s *locationService) checkLocationLimit(companyID uint, adding int) error {
 	company, err := s.companyRepo.GetCompanyWithSubscriptionDetails(int(companyID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve company information: %w", err)
+		return fmt.Errorf("failed to retrieve company information: %w", err)
 	}
 
 	if company == nil {
-		return nil, fmt.Errorf("company with ID %d not found", companyID)
+		return fmt.Errorf("company with ID %d not found", companyID)
 	}
 
 	// Determine the effective MaxLocations limit
@@ -47,21 +77,96 @@ func (s *locationService) CreateAttendanceLocation(companyID uint, location *mod
 	} else if company.SubscriptionPackage != nil && company.SubscriptionPackage.ID != 0 {
 		maxLocationsLimit = company.SubscriptionPackage.MaxLocations
 	} else {
-		return nil, fmt.Errorf("company has no active subscription package or custom offer")
+		return fmt.Errorf("company has no active subscription package or custom offer")
 	}
 
 	locationCount, err := s.attendanceLocationRepo.CountAttendanceLocationsByCompanyID(companyID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count existing locations: %w", err)
+		return fmt.Errorf("failed to count existing locations: %w", err)
 	}
 
-	if locationCount >= int64(maxLocationsLimit) {
-		return nil, ErrLocationLimitReached
+	if locationCount+int64(adding) > int64(maxLocationsLimit) {
+		return ErrLocationLimitReached
 	}
 
-	location.CompanyID = companyID
+	return nil
+}
 
-	return s.attendanceLocationRepo.CreateAttendanceLocation(location)
+// prepareLocationShape validates a location's shape. Polygon geometry is normalised to a GeoJSON
+// MultiPolygon and its bounding circle is stored as the centre and radius, so clients that only
+// understand circles can still place the location on a map.
+func prepareLocationShape(location *models.AttendanceLocation) error {
+	switch location.Shape {
+	case "", models.LocationShapeCircle:
+		location.Shape = models.LocationShapeCircle
+		location.Geometry = nil
+		return nil
+	case models.LocationShapePolygon:
+		area, err := helper.ParseGeoJSONGeometry(location.Geometry)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidLocationGeometry, err)
+		}
+		return applyLocationArea(location, area)
+	}
+	return fmt.Errorf("%w: shape must be %q or %q", ErrInvalidLocationGeometry, models.LocationShapeCircle, models.LocationShapePolygon)
+}
+
+// prepareLocationProofs validates which proofs a location accepts. BSSIDs are normalised, and a QR secret
+// is generated the first time QR codes are enabled so the screen at the location can start showing codes.
+func prepareLocationProofs(location *models.AttendanceLocation) error {
+	if len(location.AcceptedProofs) == 0 {
+		location.AcceptedProofs = []string{models.LocationProofGPS}
+	}
+	var proofs []string
+	for _, proof := range location.AcceptedProofs {
+		switch proof {
+		case models.LocationProofGPS, models.LocationProofWiFi, models.LocationProofQR:
+			if !slices.Contains(proofs, proof) {
+				proofs = append(proofs, proof)
+			}
+		default:
+			return fmt.Errorf("%w: unknown proof %q", ErrInvalidLocationProofs, proof)
+		}
+	}
+	location.AcceptedProofs = proofs
+
+	var bssids []string
+	for _, bssid := range location.WifiBSSIDs {
+		normalized, err := helper.NormalizeBSSID(bssid)
+		if err != nil {
+			return fmt.Errorf("%w: %q is not a valid BSSID", ErrInvalidLocationProofs, bssid)
+		}
+		if !slices.Contains(bssids, normalized) {
+			bssids = append(bssids, normalized)
+		}
+	}
+	location.WifiBSSIDs = bssids
+	if location.AcceptsProof(models.LocationProofWiFi) && len(bssids) == 0 {
+		return fmt.Errorf("%w: at least one BSSID is required for Wi-Fi check-in", ErrInvalidLocationProofs)
+	}
+
+	if location.AcceptsProof(models.LocationProofQR) && location.QRSecret == "" {
+		secret, err := helper.GenerateRandomString(32)
+		if err != nil {
+			return fmt.Errorf("failed to generate QR secret: %w", err)
+		}
+		location.QRSecret = secret
+	}
+	return nil
+}
+
+func applyLocationArea(location *models.AttendanceLocation, area helper.GeoMultiPolygon) error {
+	geometry, err := area.MarshalGeoJSON()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLocationGeometry, err)
+	}
+	latitude, longitude, radius := area.BoundingCircle()
+	location.Shape = models.LocationShapePolygon
+	location.Geometry = geometry
+	location.Latitude = latitude
+	location.Longitude = longitude
+	location.Radius = uint(math.Ceil(radius))
+	return nil
 }
 
 func (s *locationService) GetAttendanceLocationsByCompanyID(companyID uint) ([]*models.AttendanceLocation, error) {
@@ -90,6 +195,16 @@ func (s *locationService) UpdateAttendanceLocation(companyID, locationID uint, l
 	existingLocation.Latitude = locationUpdates.Latitude
 	existingLocation.Longitude = locationUpdates.Longitude
 	existingLocation.Radius = locationUpdates.Radius
+	existingLocation.Shape = locationUpdates.Shape
+	existingLocation.Geometry = locationUpdates.Geometry
+	existingLocation.AcceptedProofs = locationUpdates.AcceptedProofs
+	existingLocation.WifiBSSIDs = locationUpdates.WifiBSSIDs
+	if err := prepareLocationShape(existingLocation); err != nil {
+		return nil, err
+	}
+	if err := prepareLocationProofs(existingLocation); err != nil {
+		return nil, err
+	}
 
 	return s.attendanceLocationRepo.UpdateAttendanceLocation(existingLocation)
 }
@@ -104,4 +219,58 @@ func (s *locationService) DeleteAttendanceLocation(companyID, locationID uint) e
 	}
 
 	return s.attendanceLocationRepo.DeleteAttendanceLocation(locationID)
-}
\ No newline at end of file
+}
+
+// ImportAttendanceLocations creates one polygon location per feature of a GeoJSON file or per
+// placemark of a KML file. Unnamed features are named after the file.
+func (s *locationService) ImportAttendanceLocations(companyID uint, fileName string, data []byte) ([]*models.AttendanceLocation, error) {
+	var features []helper.GeoFeature
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".kml":
+		features, err = helper.ParseKMLFeatures(data)
+	case ".geojson", ".json":
+		features, err = helper.ParseGeoJSONFeatures(data)
+	default:
+		return nil, fmt.Errorf("%w: only .geojson, .json and .kml files are supported", ErrInvalidLocationGeometry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLocationGeometry, err)
+	}
+
+	if err := s.checkLocationLimit(companyID, len(features)); err != nil {
+		return nil, err
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	var imported []*models.AttendanceLocation
+	for i, feature := range features {
+		location := &models.AttendanceLocation{CompanyID: companyID, Name: feature.Name, AcceptedProofs: []string{models.LocationProofGPS}}
+		if location.Name == "" {
+			location.Name = fmt.Sprintf("%s %d", baseName, i+1)
+		}
+		if err := applyLocationArea(location, feature.Geometry); err != nil {
+			return imported, err
+		}
+		created, err := s.attendanceLocationRepo.CreateAttendanceLocation(location)
+		if err != nil {
+			return imported, fmt.Errorf("failed to import location %q: %w", location.Name, err)
+		}
+		imported = append(imported, created)
+	}
+	return imported, nil
+}
+
+// GetLocationQRCode returns the QR code the screen at a location should currently show and when it expires.
+func (s *locationService) GetLocationQRCode(companyID, locationID uint) (string, time.Time, error) {
+	location, err := s.attendanceLocationRepo.GetAttendanceLocationByID(locationID)
+	if err != nil || location.CompanyID != companyID {
+		return "", time.Time{}, ErrLocationNotFound
+	}
+	if !location.AcceptsProof(models.LocationProofQR) || location.QRSecret == "" {
+		return "", time.Time{}, ErrLocationQRCodeDisabled
+	}
+
+	now := time.Now()
+	return helper.GenerateLocationQRCode(location.QRSecret, now), helper.LocationQRCodeExpiresAt(now), nil
+}
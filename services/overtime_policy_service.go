@@ -0,0 +1,445 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Overtime day types used to pick the multiplier tiers.
+const (
+	OvertimeDayWeekday = "weekday"
+	OvertimeDayRestDay = "rest_day"
+	OvertimeDayHoliday = "public_holiday"
+)
+
+// OvertimePolicyService defines the interface for overtime compensation rules and weighted overtime hours.
+type OvertimePolicyService interface {
+	GetOvertimePolicy(companyID int) (*models.OvertimePolicy, error)
+	UpdateOvertimePolicy(companyID int, req UpdateOvertimePolicyRequest) (*models.OvertimePolicy, error)
+	GetPublicHolidays(companyID int, startDate, endDate *time.Time) ([]models.PublicHoliday, error)
+	CreatePublicHoliday(companyID int, req CreatePublicHolidayRequest) (*models.PublicHoliday, error)
+	DeletePublicHoliday(companyID int, holidayID uint) error
+	GetOvertimeCompensationSummary(companyID int, startDate, endDate *time.Time, search string) ([]EmployeeOvertimeCompensation, error)
+}
+
+// overtimePolicyService is the concrete implementation of OvertimePolicyService.
+type overtimePolicyService struct {
+	overtimePolicyRepo repository.OvertimePolicyRepository
+	publicHolidayRepo  repository.PublicHolidayRepository
+	companyRepo        repository.CompanyRepository
+	attendanceRepo     repository.AttendanceRepository
+}
+
+// NewOvertimePolicyService creates a new instance of OvertimePolicyService.
+func NewOvertimePolicyService(overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, companyRepo repository.CompanyRepository, attendanceRepo repository.AttendanceRepository) OvertimePolicyService {
+	return &overtimePolicyService{
+		overtimePolicyRepo: overtimePolicyRepo,
+		publicHolidayRepo:  publicHolidayRepo,
+		companyRepo:        companyRepo,
+		attendanceRepo:     attendanceRepo,
+	}
+}
+
+// UpdateOvertimePolicyRequest defines the payload for updating a company's overtime policy.
+type UpdateOvertimePolicyRequest struct {
+	RestDays                   []int   `json:"rest_days" binding:"dive,min=0,max=6"`
+	WeekdayFirstHourMultiplier float64 `json:"weekday_first_hour_multiplier" binding:"required,gt=0"`
+	WeekdayNextHoursMultiplier float64 `json:"weekday_next_hours_multiplier" binding:"required,gt=0"`
+	RestDayRegularHours        int     `json:"rest_day_regular_hours" binding:"required,min=1,max=24"`
+	RestDayRegularMultiplier   float64 `json:"rest_day_regular_multiplier" binding:"required,gt=0"`
+	RestDayNextHourMultiplier  float64 `json:"rest_day_next_hour_multiplier" binding:"required,gt=0"`
+	RestDayAfterMultiplier     float64 `json:"rest_day_after_multiplier" binding:"required,gt=0"`
+	HolidayRegularHours        int     `json:"holiday_regular_hours" binding:"required,min=1,max=24"`
+	HolidayRegularMultiplier   float64 `json:"holiday_regular_multiplier" binding:"required,gt=0"`
+	HolidayNextHourMultiplier  float64 `json:"holiday_next_hour_multiplier" binding:"required,gt=0"`
+	HolidayAfterMultiplier     float64 `json:"holiday_after_multiplier" binding:"required,gt=0"`
+	MaxSessionMinutes          int     `json:"max_session_minutes" binding:"omitempty,min=60,max=1440"` // Unchanged when omitted
+	CreditAutoClosedSessions   *bool   `json:"credit_auto_closed_sessions"`                             // Unchanged when omitted
+}
+
+// CreatePublicHolidayRequest defines the payload for adding a public holiday.
+type CreatePublicHolidayRequest struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	Name string `json:"name" binding:"required"`
+}
+
+// OvertimeTierHours is the number of overtime hours paid at one multiplier.
+type OvertimeTierHours struct {
+	DayType       string  `json:"day_type"`
+	Tier          string  `json:"tier"`
+	Multiplier    float64 `json:"multiplier"`
+	Hours         float64 `json:"hours"`
+	WeightedHours float64 `json:"weighted_hours"`
+}
+
+// OvertimeCompensation is the weighted result for a single overtime session.
+type OvertimeCompensation struct {
+	DayType        string              `json:"day_type"`
+	PayableMinutes int                 `json:"payable_minutes"`
+	WeightedHours  float64             `json:"weighted_hours"`
+	Tiers          []OvertimeTierHours `json:"tiers"`
+}
+
+// EmployeeOvertimeCompensation is the payroll-ready overtime summary of one employee for a period.
+type EmployeeOvertimeCompensation struct {
+	EmployeeID       int                 `json:"employee_id"`
+	EmployeeName     string              `json:"employee_name"`
+	EmployeeIDNumber string              `json:"employee_id_number"`
+	Sessions         int                 `json:"sessions"`
+	RawMinutes       int                 `json:"raw_minutes"`
+	PayableMinutes   int                 `json:"payable_minutes"`
+	WeekdayMinutes   int                 `json:"weekday_minutes"`
+	RestDayMinutes   int                 `json:"rest_day_minutes"`
+	HolidayMinutes   int                 `json:"holiday_minutes"`
+	WeightedHours    float64             `json:"weighted_hours"`
+	Tiers            []OvertimeTierHours `json:"tiers"`
+}
+
+// DefaultOvertimePolicy returns the statutory Indonesian overtime multipliers (PP 35/2021, five-day work week).
+func DefaultOvertimePolicy(companyID int) *models.OvertimePolicy {
+	return &models.OvertimePolicy{
+		CompanyID:                  companyID,
+		RestDays:                   "0,6",
+		WeekdayFirstHourMultiplier: 1.5,
+		WeekdayNextHoursMultiplier: 2,
+		RestDayRegularHours:        8,
+		RestDayRegularMultiplier:   2,
+		RestDayNextHourMultiplier:  3,
+		RestDayAfterMultiplier:     4,
+		HolidayRegularHours:        8,
+		HolidayRegularMultiplier:   2,
+		HolidayNextHourMultiplier:  3,
+		HolidayAfterMultiplier:     4,
+		MaxSessionMinutes:          720,
+	}
+}
+
+func (s *overtimePolicyService) GetOvertimePolicy(companyID int) (*models.OvertimePolicy, error) {
+	return loadOvertimePolicy(s.overtimePolicyRepo, companyID)
+}
+
+func (s *overtimePolicyService) UpdateOvertimePolicy(companyID int, req UpdateOvertimePolicyRequest) (*models.OvertimePolicy, error) {
+	policy, err := loadOvertimePolicy(s.overtimePolicyRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	restDays := make([]string, 0, len(req.RestDays))
+	for _, day := range req.RestDays {
+		restDays = append(restDays, strconv.Itoa(day))
+	}
+
+	policy.RestDays = strings.Join(restDays, ",")
+	policy.WeekdayFirstHourMultiplier = req.WeekdayFirstHourMultiplier
+	policy.WeekdayNextHoursMultiplier = req.WeekdayNextHoursMultiplier
+	policy.RestDayRegularHours = req.RestDayRegularHours
+	policy.RestDayRegularMultiplier = req.RestDayRegularMultiplier
+	policy.RestDayNextHourMultiplier = req.RestDayNextHourMultiplier
+	policy.RestDayAfterMultiplier = req.RestDayAfterMultiplier
+	policy.HolidayRegularHours = req.HolidayRegularHours
+	policy.HolidayRegularMultiplier = req.HolidayRegularMultiplier
+	policy.HolidayNextHourMultiplier = req.HolidayNextHourMultiplier
+	policy.HolidayAfterMultiplier = req.HolidayAfterMultiplier
+	if req.MaxSessionMinutes != 0 {
+		policy.MaxSessionMinutes = req.MaxSessionMinutes
+	}
+	if req.CreditAutoClosedSessions != nil {
+		policy.CreditAutoClosedSessions = *req.CreditAutoClosedSessions
+	}
+
+	if err := s.overtimePolicyRepo.SaveOvertimePolicy(policy); err != nil {
+		return nil, fmt.Errorf("failed to save overtime policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *overtimePolicyService) GetPublicHolidays(companyID int, startDate, endDate *time.Time) ([]models.PublicHoliday, error) {
+	return s.publicHolidayRepo.GetPublicHolidaysByCompanyID(companyID, startDate, endDate)
+}
+
+func (s *overtimePolicyService) CreatePublicHoliday(companyID int, req CreatePublicHolidayRequest) (*models.PublicHoliday, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format. Use YYYY-MM-DD")
+	}
+
+	holiday := &models.PublicHoliday{
+		CompanyID: companyID,
+		Date:      date,
+		Name:      req.Name,
+	}
+	if err := s.publicHolidayRepo.CreatePublicHoliday(holiday); err != nil {
+		return nil, fmt.Errorf("failed to create public holiday: %w", err)
+	}
+
+	return holiday, nil
+}
+
+func (s *overtimePolicyService) DeletePublicHoliday(companyID int, holidayID uint) error {
+	holiday, err := s.publicHolidayRepo.GetPublicHolidayByID(holidayID)
+	if err != nil {
+		return err
+	}
+	if holiday == nil || holiday.CompanyID != companyID {
+		return ErrPublicHolidayNotFound
+	}
+
+	return s.publicHolidayRepo.DeletePublicHoliday(holidayID)
+}
+
+// GetOvertimeCompensationSummary returns weighted overtime hours per employee for the given period.
+func (s *overtimePolicyService) GetOvertimeCompensationSummary(companyID int, startDate, endDate *time.Time, search string) ([]EmployeeOvertimeCompensation, error) {
+	attendances, err := s.attendanceRepo.GetOvertimeAttendancesFiltered(companyID, startDate, endDate, search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve overtime attendances: %w", err)
+	}
+
+	calculator, err := newOvertimeCalculator(companyID, startDate, endDate, s.overtimePolicyRepo, s.publicHolidayRepo, s.companyRepo)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeOvertimeCompensation(attendances, calculator.CalculateAll(attendances)), nil
+}
+
+// summarizeOvertimeCompensation totals weighted overtime per employee, sorted by employee name.
+func summarizeOvertimeCompensation(attendances []models.AttendancesTable, compensations map[int]OvertimeCompensation) []EmployeeOvertimeCompensation {
+	summaries := make(map[int]*EmployeeOvertimeCompensation)
+	for _, att := range attendances {
+		summary, ok := summaries[att.EmployeeID]
+		if !ok {
+			summary = &EmployeeOvertimeCompensation{
+				EmployeeID:       att.EmployeeID,
+				EmployeeName:     att.Employee.Name,
+				EmployeeIDNumber: att.Employee.EmployeeIDNumber,
+			}
+			summaries[att.EmployeeID] = summary
+		}
+
+		compensation := compensations[att.ID]
+		summary.Sessions++
+		summary.RawMinutes += att.OvertimeMinutes
+		summary.PayableMinutes += compensation.PayableMinutes
+		switch compensation.DayType {
+		case OvertimeDayHoliday:
+			summary.HolidayMinutes += compensation.PayableMinutes
+		case OvertimeDayRestDay:
+			summary.RestDayMinutes += compensation.PayableMinutes
+		default:
+			summary.WeekdayMinutes += compensation.PayableMinutes
+		}
+		summary.WeightedHours += compensation.WeightedHours
+		summary.Tiers = mergeOvertimeTiers(summary.Tiers, compensation.Tiers)
+	}
+
+	result := make([]EmployeeOvertimeCompensation, 0, len(summaries))
+	for _, summary := range summaries {
+		summary.WeightedHours = roundHours(summary.WeightedHours)
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].EmployeeName < result[j].EmployeeName })
+
+	return result
+}
+
+// loadOvertimePolicy returns the company's saved policy, or the default policy when none is configured.
+func loadOvertimePolicy(overtimePolicyRepo repository.OvertimePolicyRepository, companyID int) (*models.OvertimePolicy, error) {
+	policy, err := overtimePolicyRepo.GetOvertimePolicyByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve overtime policy: %w", err)
+	}
+	if policy == nil {
+		return DefaultOvertimePolicy(companyID), nil
+	}
+	return policy, nil
+}
+
+// parseRestDays reads the comma-separated weekdays of an overtime policy, 0 being Sunday.
+func parseRestDays(restDays string) map[time.Weekday]bool {
+	days := make(map[time.Weekday]bool)
+	for _, day := range strings.Split(restDays, ",") {
+		if weekday, err := strconv.Atoi(strings.TrimSpace(day)); err == nil {
+			days[time.Weekday(weekday)] = true
+		}
+	}
+	return days
+}
+
+// overtimeTier is one multiplier band; UpToHours is cumulative within the day (0 means unbounded).
+type overtimeTier struct {
+	Name       string
+	UpToHours  float64
+	Multiplier float64
+}
+
+// overtimeCalculator converts overtime sessions into weighted hours. Tiers apply per employee per day,
+// so sessions must be fed in check-in order; CalculateAll takes care of that.
+type overtimeCalculator struct {
+	policy   *models.OvertimePolicy
+	restDays map[time.Weekday]bool
+	holidays map[string]bool
+	location *time.Location
+	used     map[string]float64 // Payable hours already weighted per employee and day
+}
+
+func newOvertimeCalculator(companyID int, startDate, endDate *time.Time, overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, companyRepo repository.CompanyRepository) (*overtimeCalculator, error) {
+	company, err := companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	location, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	policy, err := loadOvertimePolicy(overtimePolicyRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := publicHolidayRepo.GetPublicHolidaysByCompanyID(companyID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve public holidays: %w", err)
+	}
+
+	calculator := &overtimeCalculator{
+		policy:   policy,
+		restDays: parseRestDays(policy.RestDays),
+		holidays: make(map[string]bool),
+		location: location,
+		used:     make(map[string]float64),
+	}
+	for _, holiday := range holidays {
+		calculator.holidays[holiday.Date.Format("2006-01-02")] = true
+	}
+
+	return calculator, nil
+}
+
+// CalculateAll weighs every session and returns the results keyed by attendance ID.
+func (c *overtimeCalculator) CalculateAll(attendances []models.AttendancesTable) map[int]OvertimeCompensation {
+	ordered := make([]models.AttendancesTable, len(attendances))
+	copy(ordered, attendances)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].CheckInTime.Before(ordered[j].CheckInTime) })
+
+	results := make(map[int]OvertimeCompensation, len(ordered))
+	for _, att := range ordered {
+		results[att.ID] = c.Calculate(att)
+	}
+	return results
+}
+
+// Calculate weighs a single session on top of the hours already counted for that employee and day.
+func (c *overtimeCalculator) Calculate(att models.AttendancesTable) OvertimeCompensation {
+	checkIn := att.CheckInTime.In(c.location)
+	dayKey := checkIn.Format("2006-01-02")
+
+	dayType := OvertimeDayWeekday
+	if c.holidays[dayKey] {
+		dayType = OvertimeDayHoliday
+	} else if c.restDays[checkIn.Weekday()] {
+		dayType = OvertimeDayRestDay
+	}
+
+	compensation := OvertimeCompensation{
+		DayType:        dayType,
+		PayableMinutes: payableOvertimeMinutes(att),
+	}
+	if compensation.PayableMinutes == 0 {
+		return compensation
+	}
+
+	usedKey := fmt.Sprintf("%d:%s", att.EmployeeID, dayKey)
+	from := c.used[usedKey]
+	to := from + float64(compensation.PayableMinutes)/60
+	c.used[usedKey] = to
+
+	lower := 0.0
+	for _, tier := range c.tiersFor(dayType) {
+		upper := tier.UpToHours
+		if upper == 0 {
+			upper = math.Inf(1)
+		}
+		hours := math.Min(to, upper) - math.Max(from, lower)
+		if hours > 0 {
+			compensation.Tiers = append(compensation.Tiers, OvertimeTierHours{
+				DayType:       dayType,
+				Tier:          tier.Name,
+				Multiplier:    tier.Multiplier,
+				Hours:         roundHours(hours),
+				WeightedHours: roundHours(hours * tier.Multiplier),
+			})
+			compensation.WeightedHours += hours * tier.Multiplier
+		}
+		lower = upper
+	}
+	compensation.WeightedHours = roundHours(compensation.WeightedHours)
+
+	return compensation
+}
+
+func (c *overtimeCalculator) tiersFor(dayType string) []overtimeTier {
+	p := c.policy
+	switch dayType {
+	case OvertimeDayHoliday:
+		regular := float64(p.HolidayRegularHours)
+		return []overtimeTier{
+			{Name: "regular_hours", UpToHours: regular, Multiplier: p.HolidayRegularMultiplier},
+			{Name: "next_hour", UpToHours: regular + 1, Multiplier: p.HolidayNextHourMultiplier},
+			{Name: "after", Multiplier: p.HolidayAfterMultiplier},
+		}
+	case OvertimeDayRestDay:
+		regular := float64(p.RestDayRegularHours)
+		return []overtimeTier{
+			{Name: "regular_hours", UpToHours: regular, Multiplier: p.RestDayRegularMultiplier},
+			{Name: "next_hour", UpToHours: regular + 1, Multiplier: p.RestDayNextHourMultiplier},
+			{Name: "after", Multiplier: p.RestDayAfterMultiplier},
+		}
+	default:
+		return []overtimeTier{
+			{Name: "first_hour", UpToHours: 1, Multiplier: p.WeekdayFirstHourMultiplier},
+			{Name: "next_hours", Multiplier: p.WeekdayNextHoursMultiplier},
+		}
+	}
+}
+
+// payableOvertimeMinutes returns the minutes that count towards pay. Unapproved sessions are not paid,
+// sessions linked to a request are capped at the approval, and older sessions without either are paid in full.
+func payableOvertimeMinutes(att models.AttendancesTable) int {
+	if att.Status != models.AttendanceStatusOvertimeOut || att.IsOvertimeUnapproved {
+		return 0
+	}
+	if att.OvertimeRequestID != nil {
+		return att.ApprovedOvertimeMinutes
+	}
+	return att.OvertimeMinutes
+}
+
+// mergeOvertimeTiers adds the tier hours of one session into an employee's running totals.
+func mergeOvertimeTiers(totals, tiers []OvertimeTierHours) []OvertimeTierHours {
+	for _, tier := range tiers {
+		merged := false
+		for i := range totals {
+			if totals[i].DayType == tier.DayType && totals[i].Tier == tier.Tier && totals[i].Multiplier == tier.Multiplier {
+				totals[i].Hours = roundHours(totals[i].Hours + tier.Hours)
+				totals[i].WeightedHours = roundHours(totals[i].WeightedHours + tier.WeightedHours)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			totals = append(totals, tier)
+		}
+	}
+	return totals
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
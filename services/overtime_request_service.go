@@ -0,0 +1,281 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"log"
+	"time"
+)
+
+// OvertimeRequestService defines the interface for overtime pre-approval business logic.
+type OvertimeRequestService interface {
+	SubmitOvertimeRequest(submitterID uint, req CreateOvertimeRequest) (*models.OvertimeRequest, error)
+	GetMyOvertimeRequests(employeeID uint, startDate, endDate *time.Time) ([]models.OvertimeRequest, error)
+	GetCompanyOvertimeRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.OvertimeRequest, int64, error)
+	ReviewOvertimeRequest(overtimeRequestID, adminID uint, req ReviewOvertimeRequest) (*models.OvertimeRequest, error)
+	ReapproveOvertimeRequest(overtimeRequestID, adminID uint, req ReapproveOvertimeRequest) (*models.OvertimeRequest, error)
+	CancelOvertimeRequest(overtimeRequestID, employeeID uint) (*models.OvertimeRequest, error)
+}
+
+// overtimeRequestService is the concrete implementation of OvertimeRequestService.
+type overtimeRequestService struct {
+	overtimeRequestRepo repository.OvertimeRequestRepository
+	employeeRepo        repository.EmployeeRepository
+	companyRepo         repository.CompanyRepository
+	adminCompanyRepo    repository.AdminCompanyRepository
+	attendanceRepo      repository.AttendanceRepository
+}
+
+// NewOvertimeRequestService creates a new instance of OvertimeRequestService.
+func NewOvertimeRequestService(overtimeRequestRepo repository.OvertimeRequestRepository, employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, adminCompanyRepo repository.AdminCompanyRepository, attendanceRepo repository.AttendanceRepository) OvertimeRequestService {
+	return &overtimeRequestService{
+		overtimeRequestRepo: overtimeRequestRepo,
+		employeeRepo:        employeeRepo,
+		companyRepo:         companyRepo,
+		adminCompanyRepo:    adminCompanyRepo,
+		attendanceRepo:      attendanceRepo,
+	}
+}
+
+// CreateOvertimeRequest defines the payload for submitting an overtime request.
+// EmployeeID is only needed when a supervisor submits on behalf of someone else.
+type CreateOvertimeRequest struct {
+	EmployeeID   uint    `json:"employee_id"`
+	Date         string  `json:"date" binding:"required,datetime=2006-01-02"`
+	PlannedHours float64 `json:"planned_hours" binding:"required,gt=0,lte=24"`
+	Reason       string  `json:"reason" binding:"required,min=10"`
+}
+
+// ReviewOvertimeRequest defines the payload for approving or rejecting a pending overtime request.
+// ApprovedHours defaults to the planned hours when omitted.
+type ReviewOvertimeRequest struct {
+	Status        string   `json:"status" binding:"required,oneof=approved rejected"`
+	ApprovedHours *float64 `json:"approved_hours" binding:"omitempty,gt=0,lte=24"`
+	Notes         string   `json:"notes"`
+}
+
+// ReapproveOvertimeRequest defines the payload for changing the approved amount of an approved request.
+type ReapproveOvertimeRequest struct {
+	ApprovedHours float64 `json:"approved_hours" binding:"required,gt=0,lte=24"`
+	Notes         string  `json:"notes" binding:"required,min=10"`
+}
+
+func (s *overtimeRequestService) SubmitOvertimeRequest(submitterID uint, req CreateOvertimeRequest) (*models.OvertimeRequest, error) {
+	submitter, err := s.employeeRepo.GetEmployeeByID(int(submitterID))
+	if err != nil || submitter == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	// Employees submit for themselves; supervisors may also submit for employees they supervise.
+	employee := submitter
+	if req.EmployeeID != 0 && req.EmployeeID != submitterID {
+		if submitter.Role != "supervisor" {
+			return nil, ErrOvertimeRequestUnauthorized
+		}
+		employee, err = s.employeeRepo.GetEmployeeByID(int(req.EmployeeID))
+		if err != nil || employee == nil {
+			return nil, ErrEmployeeNotFound
+		}
+		if employee.CompanyID != submitter.CompanyID {
+			return nil, ErrOvertimeRequestUnauthorized
+		}
+		if submitter.DivisionID != nil && (employee.DivisionID == nil || *employee.DivisionID != *submitter.DivisionID) {
+			return nil, ErrOvertimeRequestUnauthorized
+		}
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(employee.CompanyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format. Use YYYY-MM-DD")
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if date.Before(today) {
+		return nil, fmt.Errorf("overtime date cannot be in the past")
+	}
+
+	overtimeRequest := &models.OvertimeRequest{
+		EmployeeID:     uint(employee.ID),
+		Date:           time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), // Stored as a plain calendar date
+		PlannedMinutes: int(req.PlannedHours * 60),
+		Reason:         req.Reason,
+		Status:         "pending",
+		SubmittedByID:  submitterID,
+	}
+
+	if err := s.overtimeRequestRepo.CreateOvertimeRequest(overtimeRequest); err != nil {
+		return nil, fmt.Errorf("failed to submit overtime request: %w", err)
+	}
+
+	return overtimeRequest, nil
+}
+
+func (s *overtimeRequestService) GetMyOvertimeRequests(employeeID uint, startDate, endDate *time.Time) ([]models.OvertimeRequest, error) {
+	return s.overtimeRequestRepo.GetOvertimeRequestsByEmployeeID(employeeID, startDate, endDate)
+}
+
+func (s *overtimeRequestService) GetCompanyOvertimeRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.OvertimeRequest, int64, error) {
+	return s.overtimeRequestRepo.GetCompanyOvertimeRequestsPaginated(companyID, status, search, startDate, endDate, page, pageSize)
+}
+
+func (s *overtimeRequestService) ReviewOvertimeRequest(overtimeRequestID, adminID uint, req ReviewOvertimeRequest) (*models.OvertimeRequest, error) {
+	overtimeRequest, err := s.getAuthorizedOvertimeRequest(overtimeRequestID, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if overtimeRequest.Status != "pending" {
+		return nil, ErrOvertimeRequestNotPending
+	}
+
+	overtimeRequest.Status = req.Status
+	overtimeRequest.ReviewNotes = req.Notes
+	overtimeRequest.ReviewedBy = &adminID
+	now := time.Now()
+	overtimeRequest.ReviewedAt = &now
+	if req.Status == "approved" {
+		overtimeRequest.ApprovedMinutes = overtimeRequest.PlannedMinutes
+		if req.ApprovedHours != nil {
+			overtimeRequest.ApprovedMinutes = int(*req.ApprovedHours * 60)
+		}
+	}
+
+	if err := s.overtimeRequestRepo.UpdateOvertimeRequest(overtimeRequest); err != nil {
+		return nil, fmt.Errorf("failed to update overtime request status: %w", err)
+	}
+
+	if overtimeRequest.Status == "approved" {
+		s.applyApprovalToSessions(overtimeRequest)
+	}
+
+	return overtimeRequest, nil
+}
+
+// ReapproveOvertimeRequest changes the approved amount of an already approved request and
+// recalculates the approved minutes of the overtime sessions linked to it.
+func (s *overtimeRequestService) ReapproveOvertimeRequest(overtimeRequestID, adminID uint, req ReapproveOvertimeRequest) (*models.OvertimeRequest, error) {
+	overtimeRequest, err := s.getAuthorizedOvertimeRequest(overtimeRequestID, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if overtimeRequest.Status != "approved" {
+		return nil, ErrOvertimeRequestNotApproved
+	}
+
+	overtimeRequest.ApprovedMinutes = int(req.ApprovedHours * 60)
+	overtimeRequest.ReviewNotes = req.Notes
+	overtimeRequest.ReviewedBy = &adminID
+	now := time.Now()
+	overtimeRequest.ReviewedAt = &now
+
+	if err := s.overtimeRequestRepo.UpdateOvertimeRequest(overtimeRequest); err != nil {
+		return nil, fmt.Errorf("failed to re-approve overtime request: %w", err)
+	}
+
+	s.applyApprovalToSessions(overtimeRequest)
+
+	return overtimeRequest, nil
+}
+
+// CancelOvertimeRequest allows the employee (or the supervisor who submitted it) to cancel a pending request.
+func (s *overtimeRequestService) CancelOvertimeRequest(overtimeRequestID, employeeID uint) (*models.OvertimeRequest, error) {
+	overtimeRequest, err := s.overtimeRequestRepo.GetOvertimeRequestByID(overtimeRequestID)
+	if err != nil || overtimeRequest == nil {
+		return nil, ErrOvertimeRequestNotFound
+	}
+
+	if overtimeRequest.EmployeeID != employeeID && overtimeRequest.SubmittedByID != employeeID {
+		return nil, ErrOvertimeRequestUnauthorized
+	}
+
+	if overtimeRequest.Status != "pending" {
+		return nil, fmt.Errorf("only pending overtime requests can be cancelled")
+	}
+
+	overtimeRequest.Status = "cancelled"
+	if err := s.overtimeRequestRepo.UpdateOvertimeRequest(overtimeRequest); err != nil {
+		return nil, fmt.Errorf("failed to cancel overtime request: %w", err)
+	}
+
+	return overtimeRequest, nil
+}
+
+// getAuthorizedOvertimeRequest loads an overtime request and verifies the admin belongs to the employee's company.
+func (s *overtimeRequestService) getAuthorizedOvertimeRequest(overtimeRequestID, adminID uint) (*models.OvertimeRequest, error) {
+	overtimeRequest, err := s.overtimeRequestRepo.GetOvertimeRequestByID(overtimeRequestID)
+	if err != nil || overtimeRequest == nil {
+		return nil, ErrOvertimeRequestNotFound
+	}
+
+	adminCompany, err := s.adminCompanyRepo.GetAdminCompanyByID(int(adminID))
+	if err != nil || adminCompany == nil || adminCompany.CompanyID != overtimeRequest.Employee.CompanyID {
+		return nil, ErrOvertimeRequestUnauthorized
+	}
+
+	return overtimeRequest, nil
+}
+
+// applyApprovalToSessions links the employee's overtime sessions on the request date to the request
+// and recalculates their approved minutes against the approved amount, in check-in order.
+func (s *overtimeRequestService) applyApprovalToSessions(overtimeRequest *models.OvertimeRequest) {
+	company, err := s.companyRepo.GetCompanyByID(overtimeRequest.Employee.CompanyID)
+	if err != nil || company == nil {
+		log.Printf("Could not load company for overtime request %d: %v", overtimeRequest.ID, err)
+		return
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		log.Printf("Error loading company timezone %s for overtime request %d: %v", company.Timezone, overtimeRequest.ID, err)
+		return
+	}
+
+	date := time.Date(overtimeRequest.Date.Year(), overtimeRequest.Date.Month(), overtimeRequest.Date.Day(), 0, 0, 0, 0, loc)
+	sessions, err := s.attendanceRepo.GetOvertimeAttendancesForDate(int(overtimeRequest.EmployeeID), date)
+	if err != nil {
+		log.Printf("Could not load overtime sessions for overtime request %d: %v", overtimeRequest.ID, err)
+		return
+	}
+
+	remaining := overtimeRequest.ApprovedMinutes
+	for _, session := range sessions {
+		if session.OvertimeRequestID != nil && *session.OvertimeRequestID != overtimeRequest.ID {
+			continue // Linked to a different request
+		}
+		requestID := overtimeRequest.ID
+		session.OvertimeRequestID = &requestID
+		session.IsOvertimeUnapproved = false
+		session.ApprovedOvertimeMinutes = capApprovedOvertime(session.OvertimeMinutes, remaining)
+		remaining -= session.ApprovedOvertimeMinutes
+
+		change := models.AttendanceChange{
+			ActorType: models.AttendanceActorAdmin,
+			ActorID:   overtimeRequest.ReviewedBy,
+			Reason:    fmt.Sprintf("Overtime request #%d approved for %d minutes.", overtimeRequest.ID, overtimeRequest.ApprovedMinutes),
+		}
+		if err := s.attendanceRepo.UpdateAttendance(&session, change); err != nil {
+			log.Printf("Failed to apply overtime request %d to attendance %d: %v", overtimeRequest.ID, session.ID, err)
+		}
+	}
+}
+
+// capApprovedOvertime returns the overtime minutes that can still be paid given the remaining approved minutes.
+func capApprovedOvertime(overtimeMinutes, remainingApproved int) int {
+	if remainingApproved <= 0 {
+		return 0
+	}
+	if overtimeMinutes > remainingApproved {
+		return remainingApproved
+	}
+	return overtimeMinutes
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RemoteWorkService defines the interface for remote (work-from-home) policies, schedules, requests and home locations.
+type RemoteWorkService interface {
+	GetRemoteWorkPolicy(companyID int) (*models.RemoteWorkPolicy, error)
+	UpdateRemoteWorkPolicy(companyID int, req UpdateRemoteWorkPolicyRequest) (*models.RemoteWorkPolicy, error)
+	GetRemoteWorkSchedules(companyID int) ([]models.RemoteWorkSchedule, error)
+	CreateRemoteWorkSchedule(companyID int, req CreateRemoteWorkScheduleRequest) (*models.RemoteWorkSchedule, error)
+	DeleteRemoteWorkSchedule(companyID int, scheduleID uint) error
+	SubmitRemoteWorkRequest(employeeID uint, req CreateRemoteWorkRequest) (*models.RemoteWorkRequest, error)
+	GetMyRemoteWorkRequests(employeeID uint, startDate, endDate *time.Time) ([]models.RemoteWorkRequest, error)
+	CancelRemoteWorkRequest(remoteWorkRequestID, employeeID uint) (*models.RemoteWorkRequest, error)
+	GetCompanyRemoteWorkRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.RemoteWorkRequest, int64, error)
+	ReviewRemoteWorkRequest(remoteWorkRequestID, adminID uint, req ReviewRemoteWorkRequest) (*models.RemoteWorkRequest, error)
+	RegisterHomeLocation(employeeID uint, req RegisterHomeLocationRequest) (*models.EmployeeHomeLocation, error)
+	GetMyHomeLocation(employeeID uint) (*models.EmployeeHomeLocation, error)
+	GetCompanyHomeLocations(companyID int, status string) ([]models.EmployeeHomeLocation, error)
+	ReviewHomeLocation(homeLocationID, adminID uint, req ReviewHomeLocationRequest) (*models.EmployeeHomeLocation, error)
+}
+
+// remoteWorkService is the concrete implementation of RemoteWorkService.
+type remoteWorkService struct {
+	remoteWorkRepo   repository.RemoteWorkRepository
+	employeeRepo     repository.EmployeeRepository
+	companyRepo      repository.CompanyRepository
+	adminCompanyRepo repository.AdminCompanyRepository
+	divisionRepo     repository.DivisionRepository
+}
+
+// NewRemoteWorkService creates a new instance of RemoteWorkService.
+func NewRemoteWorkService(remoteWorkRepo repository.RemoteWorkRepository, employeeRepo repository.EmployeeRepository, companyRepo repository.CompanyRepository, adminCompanyRepo repository.AdminCompanyRepository, divisionRepo repository.DivisionRepository) RemoteWorkService {
+	return &remoteWorkService{
+		remoteWorkRepo:   remoteWorkRepo,
+		employeeRepo:     employeeRepo,
+		companyRepo:      companyRepo,
+		adminCompanyRepo: adminCompanyRepo,
+		divisionRepo:     divisionRepo,
+	}
+}
+
+// UpdateRemoteWorkPolicyRequest defines the payload for updating a company's remote work policy.
+type UpdateRemoteWorkPolicyRequest struct {
+	GeofenceMode string `json:"geofence_mode" binding:"required,oneof=home_location none"`
+	HomeRadius   uint   `json:"home_radius" binding:"required,min=10,max=5000"`
+}
+
+// CreateRemoteWorkScheduleRequest defines the payload for giving an employee or a division recurring remote work days.
+type CreateRemoteWorkScheduleRequest struct {
+	EmployeeID *int   `json:"employee_id"`
+	DivisionID *int   `json:"division_id"`
+	Weekdays   []int  `json:"weekdays" binding:"required,min=1,dive,min=0,max=6"`
+	StartDate  string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// CreateRemoteWorkRequest defines the payload for requesting remote work days.
+type CreateRemoteWorkRequest struct {
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason" binding:"required,min=10"`
+}
+
+// ReviewRemoteWorkRequest defines the payload for approving or rejecting a pending remote work request.
+type ReviewRemoteWorkRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Notes  string `json:"notes"`
+}
+
+// RegisterHomeLocationRequest defines the payload for registering the employee's home location.
+type RegisterHomeLocationRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Address   string  `json:"address"`
+}
+
+// ReviewHomeLocationRequest defines the payload for approving or rejecting a registered home location.
+type ReviewHomeLocationRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+}
+
+// DefaultRemoteWorkPolicy returns the policy used until a company configures its own:
+// remote check-ins must be made within 200 meters of an approved home location.
+func DefaultRemoteWorkPolicy(companyID int) *models.RemoteWorkPolicy {
+	return &models.RemoteWorkPolicy{
+		CompanyID:    companyID,
+		GeofenceMode: models.RemoteGeofenceHomeLocation,
+		HomeRadius:   200,
+	}
+}
+
+func (s *remoteWorkService) GetRemoteWorkPolicy(companyID int) (*models.RemoteWorkPolicy, error) {
+	return loadRemoteWorkPolicy(s.remoteWorkRepo, companyID)
+}
+
+func (s *remoteWorkService) UpdateRemoteWorkPolicy(companyID int, req UpdateRemoteWorkPolicyRequest) (*models.RemoteWorkPolicy, error) {
+	policy, err := loadRemoteWorkPolicy(s.remoteWorkRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.GeofenceMode = req.GeofenceMode
+	policy.HomeRadius = req.HomeRadius
+
+	if err := s.remoteWorkRepo.SaveRemoteWorkPolicy(policy); err != nil {
+		return nil, fmt.Errorf("failed to save remote work policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (s *remoteWorkService) GetRemoteWorkSchedules(companyID int) ([]models.RemoteWorkSchedule, error) {
+	return s.remoteWorkRepo.GetRemoteWorkSchedulesByCompanyID(companyID)
+}
+
+func (s *remoteWorkService) CreateRemoteWorkSchedule(companyID int, req CreateRemoteWorkScheduleRequest) (*models.RemoteWorkSchedule, error) {
+	if (req.EmployeeID == nil) == (req.DivisionID == nil) {
+		return nil, fmt.Errorf("a schedule must target either an employee or a division")
+	}
+	if req.EmployeeID != nil {
+		employee, err := s.employeeRepo.GetEmployeeByID(*req.EmployeeID)
+		if err != nil || employee == nil || employee.CompanyID != companyID {
+			return nil, ErrEmployeeNotFound
+		}
+	}
+	if req.DivisionID != nil {
+		division, err := s.divisionRepo.GetDivisionByID(uint(*req.DivisionID))
+		if err != nil || division == nil || int(division.CompanyID) != companyID {
+			return nil, fmt.Errorf("division not found")
+		}
+	}
+
+	schedule := &models.RemoteWorkSchedule{
+		CompanyID:  companyID,
+		EmployeeID: req.EmployeeID,
+		DivisionID: req.DivisionID,
+	}
+
+	weekdays := make([]string, 0, len(req.Weekdays))
+	for _, day := range req.Weekdays {
+		weekdays = append(weekdays, strconv.Itoa(day))
+	}
+	schedule.Weekdays = strings.Join(weekdays, ",")
+
+	if req.StartDate != "" {
+		startDate, _ := time.Parse("2006-01-02", req.StartDate) // Format checked by the binding
+		schedule.StartDate = &startDate
+	}
+	if req.EndDate != "" {
+		endDate, _ := time.Parse("2006-01-02", req.EndDate)
+		schedule.EndDate = &endDate
+	}
+	if schedule.StartDate != nil && schedule.EndDate != nil && schedule.EndDate.Before(*schedule.StartDate) {
+		return nil, fmt.Errorf("end date cannot be before start date")
+	}
+
+	if err := s.remoteWorkRepo.CreateRemoteWorkSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("failed to create remote work schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *remoteWorkService) DeleteRemoteWorkSchedule(companyID int, scheduleID uint) error {
+	schedule, err := s.remoteWorkRepo.GetRemoteWorkScheduleByID(scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil || schedule.CompanyID != companyID {
+		return ErrRemoteWorkScheduleNotFound
+	}
+
+	return s.remoteWorkRepo.DeleteRemoteWorkSchedule(scheduleID)
+}
+
+func (s *remoteWorkService) SubmitRemoteWorkRequest(employeeID uint, req CreateRemoteWorkRequest) (*models.RemoteWorkRequest, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(int(employeeID))
+	if err != nil || employee == nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(employee.CompanyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date format. Use YYYY-MM-DD")
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", req.EndDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date format. Use YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date cannot be before start date")
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if startDate.Before(today) {
+		return nil, fmt.Errorf("remote work cannot be requested for past days")
+	}
+
+	remoteWorkRequest := &models.RemoteWorkRequest{
+		EmployeeID: employeeID,
+		StartDate:  time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC), // Stored as plain calendar dates
+		EndDate:    time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC),
+		Reason:     req.Reason,
+		Status:     "pending",
+	}
+
+	if err := s.remoteWorkRepo.CreateRemoteWorkRequest(remoteWorkRequest); err != nil {
+		return nil, fmt.Errorf("failed to submit remote work request: %w", err)
+	}
+
+	return remoteWorkRequest, nil
+}
+
+func (s *remoteWorkService) GetMyRemoteWorkRequests(employeeID uint, startDate, endDate *time.Time) ([]models.RemoteWorkRequest, error) {
+	return s.remoteWorkRepo.GetRemoteWorkRequestsByEmployeeID(employeeID, startDate, endDate)
+}
+
+func (s *remoteWorkService) CancelRemoteWorkRequest(remoteWorkRequestID, employeeID uint) (*models.RemoteWorkRequest, error) {
+	remoteWorkRequest, err := s.remoteWorkRepo.GetRemoteWorkRequestByID(remoteWorkRequestID)
+	if err != nil || remoteWorkRequest == nil {
+		return nil, ErrRemoteWorkRequestNotFound
+	}
+
+	if remoteWorkRequest.EmployeeID != employeeID {
+		return nil, ErrRemoteWorkRequestUnauthorized
+	}
+
+	if remoteWorkRequest.Status != "pending" {
+		return nil, fmt.Errorf("only pending remote work requests can be cancelled")
+	}
+
+	remoteWorkRequest.Status = "cancelled"
+	if err := s.remoteWorkRepo.UpdateRemoteWorkRequest(remoteWorkRequest); err != nil {
+		return nil, fmt.Errorf("failed to cancel remote work request: %w", err)
+	}
+
+	return remoteWorkRequest, nil
+}
+
+func (s *remoteWorkService) GetCompanyRemoteWorkRequests(companyID int, status, search string, startDate, endDate *time.Time, page, pageSize int) ([]models.RemoteWorkRequest, int64, error) {
+	return s.remoteWorkRepo.GetCompanyRemoteWorkRequestsPaginated(companyID, status, search, startDate, endDate, page, pageSize)
+}
+
+func (s *remoteWorkService) ReviewRemoteWorkRequest(remoteWorkRequestID, adminID uint, req ReviewRemoteWorkRequest) (*models.RemoteWorkRequest, error) {
+	remoteWorkRequest, err := s.remoteWorkRepo.GetRemoteWorkRequestByID(remoteWorkRequestID)
+	if err != nil || remoteWorkRequest == nil {
+		return nil, ErrRemoteWorkRequestNotFound
+	}
+
+	adminCompany, err := s.adminCompanyRepo.GetAdminCompanyByID(int(adminID))
+	if err != nil || adminCompany == nil || adminCompany.CompanyID != remoteWorkRequest.Employee.CompanyID {
+		return nil, ErrRemoteWorkRequestUnauthorized
+	}
+
+	if remoteWorkRequest.Status != "pending" {
+		return nil, ErrRemoteWorkRequestNotPending
+	}
+
+	remoteWorkRequest.Status = req.Status
+	remoteWorkRequest.ReviewNotes = req.Notes
+	remoteWorkRequest.ReviewedBy = &adminID
+	now := time.Now()
+	remoteWorkRequest.ReviewedAt = &now
+
+	if err := s.remoteWorkRepo.UpdateRemoteWorkRequest(remoteWorkRequest); err != nil {
+		return nil, fmt.Errorf("failed to update remote work request status: %w", err)
+	}
+
+	return remoteWorkRequest, nil
+}
+
+// RegisterHomeLocation registers or moves the employee's home location. It must be approved before it is used.
+func (s *remoteWorkService) RegisterHomeLocation(employeeID uint, req RegisterHomeLocationRequest) (*models.EmployeeHomeLocation, error) {
+	homeLocation, err := s.remoteWorkRepo.GetHomeLocationByEmployeeID(employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve home location: %w", err)
+	}
+	if homeLocation == nil {
+		homeLocation = &models.EmployeeHomeLocation{EmployeeID: employeeID}
+	}
+
+	homeLocation.Latitude = req.Latitude
+	homeLocation.Longitude = req.Longitude
+	homeLocation.Address = req.Address
+	homeLocation.Status = "pending"
+	homeLocation.ReviewedBy = nil
+	homeLocation.ReviewedAt = nil
+
+	if err := s.remoteWorkRepo.SaveHomeLocation(homeLocation); err != nil {
+		return nil, fmt.Errorf("failed to register home location: %w", err)
+	}
+
+	return homeLocation, nil
+}
+
+func (s *remoteWorkService) GetMyHomeLocation(employeeID uint) (*models.EmployeeHomeLocation, error) {
+	homeLocation, err := s.remoteWorkRepo.GetHomeLocationByEmployeeID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if homeLocation == nil {
+		return nil, ErrHomeLocationNotFound
+	}
+	return homeLocation, nil
+}
+
+func (s *remoteWorkService) GetCompanyHomeLocations(companyID int, status string) ([]models.EmployeeHomeLocation, error) {
+	return s.remoteWorkRepo.GetCompanyHomeLocations(companyID, status)
+}
+
+func (s *remoteWorkService) ReviewHomeLocation(homeLocationID, adminID uint, req ReviewHomeLocationRequest) (*models.EmployeeHomeLocation, error) {
+	homeLocation, err := s.remoteWorkRepo.GetHomeLocationByID(homeLocationID)
+	if err != nil || homeLocation == nil {
+		return nil, ErrHomeLocationNotFound
+	}
+
+	adminCompany, err := s.adminCompanyRepo.GetAdminCompanyByID(int(adminID))
+	if err != nil || adminCompany == nil || adminCompany.CompanyID != homeLocation.Employee.CompanyID {
+		return nil, ErrRemoteWorkRequestUnauthorized
+	}
+
+	homeLocation.Status = req.Status
+	homeLocation.ReviewedBy = &adminID
+	now := time.Now()
+	homeLocation.ReviewedAt = &now
+
+	if err := s.remoteWorkRepo.SaveHomeLocation(homeLocation); err != nil {
+		return nil, fmt.Errorf("failed to update home location status: %w", err)
+	}
+
+	return homeLocation, nil
+}
+
+// loadRemoteWorkPolicy returns the company's saved policy, or the default policy when none is configured.
+func loadRemoteWorkPolicy(remoteWorkRepo repository.RemoteWorkRepository, companyID int) (*models.RemoteWorkPolicy, error) {
+	policy, err := remoteWorkRepo.GetRemoteWorkPolicyByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve remote work policy: %w", err)
+	}
+	if policy == nil {
+		return DefaultRemoteWorkPolicy(companyID), nil
+	}
+	return policy, nil
+}
+
+// isRemoteWorkDay reports whether the employee works remotely on the given day, either through an
+// approved request or a schedule for them or their division. day must be in the company's timezone.
+func isRemoteWorkDay(remoteWorkRepo repository.RemoteWorkRepository, employee *models.EmployeesTable, day time.Time) (bool, error) {
+	remoteWorkRequest, err := remoteWorkRepo.GetApprovedRemoteWorkRequestForDate(employee.ID, day)
+	if err != nil {
+		return false, err
+	}
+	if remoteWorkRequest != nil {
+		return true, nil
+	}
+
+	schedules, err := remoteWorkRepo.GetRemoteWorkSchedulesForEmployee(employee.ID, employee.DivisionID)
+	if err != nil {
+		return false, err
+	}
+	dayKey := day.Format("2006-01-02")
+	weekday := strconv.Itoa(int(day.Weekday()))
+	for _, schedule := range schedules {
+		if schedule.StartDate != nil && dayKey < schedule.StartDate.Format("2006-01-02") {
+			continue
+		}
+		if schedule.EndDate != nil && dayKey > schedule.EndDate.Format("2006-01-02") {
+			continue
+		}
+		for _, scheduled := range strings.Split(schedule.Weekdays, ",") {
+			if strings.TrimSpace(scheduled) == weekday {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
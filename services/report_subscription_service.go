@@ -0,0 +1,326 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+	"log"
+	"strings"
+	"time"
+)
+
+// ReportSubscriptionService defines the interface for reports emailed on a schedule.
+type ReportSubscriptionService interface {
+	GetReportSubscriptions(companyID int) ([]models.ReportSubscription, error)
+	CreateReportSubscription(adminID uint, companyID int, req ReportSubscriptionRequest) (*models.ReportSubscription, error)
+	UpdateReportSubscription(companyID int, id uint, req ReportSubscriptionRequest) (*models.ReportSubscription, error)
+	DeleteReportSubscription(companyID int, id uint) error
+	SendReportNow(companyID int, id uint) (*models.ReportDelivery, error)
+	GetReportDeliveries(companyID int, subscriptionID uint, page, pageSize int) ([]models.ReportDelivery, int64, error)
+	DeliverDueReports() (int, error)
+}
+
+// reportSubscriptionService is the concrete implementation of ReportSubscriptionService.
+type reportSubscriptionService struct {
+	reportSubscriptionRepo repository.ReportSubscriptionRepository
+	companyRepo            repository.CompanyRepository
+	attendanceService      AttendanceService
+	leaveRequestService    LeaveRequestService
+	timesheetService       TimesheetService
+}
+
+// NewReportSubscriptionService creates a new instance of ReportSubscriptionService.
+func NewReportSubscriptionService(reportSubscriptionRepo repository.ReportSubscriptionRepository, companyRepo repository.CompanyRepository, attendanceService AttendanceService, leaveRequestService LeaveRequestService, timesheetService TimesheetService) ReportSubscriptionService {
+	return &reportSubscriptionService{
+		reportSubscriptionRepo: reportSubscriptionRepo,
+		companyRepo:            companyRepo,
+		attendanceService:      attendanceService,
+		leaveRequestService:    leaveRequestService,
+		timesheetService:       timesheetService,
+	}
+}
+
+// ReportSubscriptionRequest defines the payload for creating or updating a scheduled report.
+type ReportSubscriptionRequest struct {
+	Name       string   `json:"name" binding:"required,max=100"`
+	ReportType string   `json:"report_type" binding:"required,oneof=attendances overtime unaccounted leave_requests timesheet"`
+	Format     string   `json:"format" binding:"required,oneof=xlsx csv pdf"`
+	Search     string   `json:"search"`
+	Status     string   `json:"status" binding:"omitempty,oneof=pending approved rejected cancelled"` // Leave request reports only
+	Recipients []string `json:"recipients" binding:"required,min=1,max=20,dive,email"`
+	Frequency  string   `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	DayOfWeek  *int     `json:"day_of_week" binding:"omitempty,min=0,max=6"`
+	DayOfMonth *int     `json:"day_of_month" binding:"omitempty,min=1,max=28"`
+	SendTime   string   `json:"send_time" binding:"required"` // HH:MM in the company's timezone
+	IsActive   *bool    `json:"is_active"`
+}
+
+func (s *reportSubscriptionService) GetReportSubscriptions(companyID int) ([]models.ReportSubscription, error) {
+	return s.reportSubscriptionRepo.GetReportSubscriptionsByCompanyID(companyID)
+}
+
+func (s *reportSubscriptionService) CreateReportSubscription(adminID uint, companyID int, req ReportSubscriptionRequest) (*models.ReportSubscription, error) {
+	subscription := &models.ReportSubscription{
+		CompanyID: companyID,
+		CreatedBy: adminID,
+		IsActive:  true,
+	}
+	if err := s.applyReportSubscriptionRequest(subscription, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportSubscriptionRepo.CreateReportSubscription(subscription); err != nil {
+		return nil, fmt.Errorf("failed to create report subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// UpdateReportSubscription replaces the settings of a subscription and reschedules its next delivery.
+func (s *reportSubscriptionService) UpdateReportSubscription(companyID int, id uint, req ReportSubscriptionRequest) (*models.ReportSubscription, error) {
+	subscription, err := s.getCompanyReportSubscription(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyReportSubscriptionRequest(subscription, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportSubscriptionRepo.UpdateReportSubscription(subscription); err != nil {
+		return nil, fmt.Errorf("failed to update report subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (s *reportSubscriptionService) DeleteReportSubscription(companyID int, id uint) error {
+	if _, err := s.getCompanyReportSubscription(companyID, id); err != nil {
+		return err
+	}
+	return s.reportSubscriptionRepo.DeleteReportSubscription(id)
+}
+
+// SendReportNow delivers a subscription's report for the period of its latest scheduled delivery, without
+// changing its schedule.
+func (s *reportSubscriptionService) SendReportNow(companyID int, id uint) (*models.ReportDelivery, error) {
+	subscription, err := s.getCompanyReportSubscription(companyID, id)
+	if err != nil {
+		return nil, err
+	}
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	companyLocation, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	return s.deliverReport(subscription, company, time.Now().In(companyLocation))
+}
+
+func (s *reportSubscriptionService) GetReportDeliveries(companyID int, subscriptionID uint, page, pageSize int) ([]models.ReportDelivery, int64, error) {
+	return s.reportSubscriptionRepo.GetReportDeliveriesPaginated(companyID, subscriptionID, page, pageSize)
+}
+
+// DeliverDueReports sends every scheduled report that is due and schedules its next delivery. Deliveries missed
+// while the server was down are sent once, not once per missed run. It returns the number of reports delivered.
+func (s *reportSubscriptionService) DeliverDueReports() (int, error) {
+	now := time.Now()
+	subscriptions, err := s.reportSubscriptionRepo.GetDueReportSubscriptions(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve due report subscriptions: %w", err)
+	}
+
+	delivered := 0
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		company, err := s.companyRepo.GetCompanyByID(subscription.CompanyID)
+		if err != nil || company == nil {
+			log.Printf("Skipping report subscription %d: company %d not found", subscription.ID, subscription.CompanyID)
+			continue
+		}
+		companyLocation, err := time.LoadLocation(company.Timezone)
+		if err != nil {
+			log.Printf("Skipping report subscription %d: invalid timezone %q", subscription.ID, company.Timezone)
+			continue
+		}
+
+		delivery, err := s.deliverReport(subscription, company, subscription.NextRunAt.In(companyLocation))
+		if err != nil {
+			log.Printf("Error delivering report subscription %d: %v", subscription.ID, err)
+		} else if delivery.Status != models.ReportDeliveryStatusFailed {
+			delivered++
+		}
+
+		subscription.LastRunAt = &now
+		subscription.NextRunAt = nextReportRun(subscription, now.In(companyLocation))
+		if err := s.reportSubscriptionRepo.UpdateReportSubscription(subscription); err != nil {
+			log.Printf("Error scheduling the next delivery of report subscription %d: %v", subscription.ID, err)
+		}
+	}
+	return delivered, nil
+}
+
+// deliverReport generates a subscription's report for the period before runAt, emails it to each recipient and
+// records the delivery.
+func (s *reportSubscriptionService) deliverReport(subscription *models.ReportSubscription, company *models.CompaniesTable, runAt time.Time) (*models.ReportDelivery, error) {
+	periodStart, periodEnd := reportPeriodBefore(subscription.Frequency, runAt)
+	delivery := &models.ReportDelivery{
+		SubscriptionID: subscription.ID,
+		CompanyID:      subscription.CompanyID,
+		ReportType:     subscription.ReportType,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		Recipients:     subscription.Recipients,
+		Status:         models.ReportDeliveryStatusFailed,
+	}
+
+	report, err := s.buildScheduledReport(subscription, periodStart, periodEnd)
+	if err == nil {
+		var content []byte
+		var contentType string
+		content, contentType, delivery.FileName, err = report.Render(subscription.Format)
+		if err == nil {
+			period := fmt.Sprintf("%s s.d. %s", periodStart.Format("02-01-2006"), periodEnd.Format("02-01-2006"))
+			var failed []string
+			for _, recipient := range subscription.Recipients {
+				if err := helper.SendScheduledReportEmail(recipient, company.Name, report.Title, period, delivery.FileName, contentType, content); err != nil {
+					failed = append(failed, fmt.Sprintf("%s: %v", recipient, err))
+				}
+			}
+			if len(failed) < len(subscription.Recipients) {
+				sentAt := time.Now()
+				delivery.SentAt = &sentAt
+				delivery.Status = models.ReportDeliveryStatusSent
+				if len(failed) > 0 {
+					delivery.Status = models.ReportDeliveryStatusPartiallySent
+				}
+			}
+			delivery.Error = strings.Join(failed, "; ")
+		}
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if err := s.reportSubscriptionRepo.CreateReportDelivery(delivery); err != nil {
+		return nil, fmt.Errorf("failed to record report delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// buildScheduledReport generates a subscription's report with the existing exports.
+func (s *reportSubscriptionService) buildScheduledReport(subscription *models.ReportSubscription, periodStart, periodEnd time.Time) (*helper.Report, error) {
+	filters := subscription.Filters
+	switch subscription.ReportType {
+	case models.ScheduledReportAttendances:
+		if !helper.IsStreamableReportFormat(subscription.Format) {
+			count, err := s.attendanceService.CountAllAttendances(subscription.CompanyID, &periodStart, &periodEnd)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count company attendances: %w", err)
+			}
+			if count > MaxPDFExportRows {
+				return nil, ErrPDFExportTooLarge
+			}
+		}
+		return s.attendanceService.ExportAllAttendances(subscription.CompanyID, &periodStart, &periodEnd)
+	case models.ScheduledReportOvertime:
+		return s.attendanceService.ExportOvertime(subscription.CompanyID, &periodStart, &periodEnd, filters.Search)
+	case models.ScheduledReportUnaccounted:
+		return s.attendanceService.ExportUnaccounted(subscription.CompanyID, &periodStart, &periodEnd, filters.Search)
+	case models.ScheduledReportLeaveRequests:
+		return s.leaveRequestService.ExportCompanyLeaveRequests(subscription.CompanyID, filters.Status, filters.Search, &periodStart, &periodEnd)
+	case models.ScheduledReportTimesheet:
+		return s.timesheetService.ExportTimesheet(subscription.CompanyID, periodStart, periodEnd)
+	}
+	return nil, fmt.Errorf("unknown report type %q", subscription.ReportType)
+}
+
+// applyReportSubscriptionRequest validates the schedule of a request, copies it onto a subscription and
+// schedules its next delivery.
+func (s *reportSubscriptionService) applyReportSubscriptionRequest(subscription *models.ReportSubscription, req ReportSubscriptionRequest) error {
+	if _, err := time.Parse("15:04", req.SendTime); err != nil {
+		return ErrInvalidReportSendTime
+	}
+	if (req.Frequency == models.ReportFrequencyWeekly && req.DayOfWeek == nil) || (req.Frequency == models.ReportFrequencyMonthly && req.DayOfMonth == nil) {
+		return ErrReportScheduleDayRequired
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(subscription.CompanyID)
+	if err != nil || company == nil {
+		return ErrCompanyNotFound
+	}
+	companyLocation, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return ErrInvalidTimezone
+	}
+
+	subscription.Name = req.Name
+	subscription.ReportType = req.ReportType
+	subscription.Format = req.Format
+	subscription.Filters = models.ReportFilters{Search: req.Search, Status: req.Status}
+	subscription.Recipients = req.Recipients
+	subscription.Frequency = req.Frequency
+	subscription.DayOfWeek, subscription.DayOfMonth = nil, nil
+	switch req.Frequency {
+	case models.ReportFrequencyWeekly:
+		subscription.DayOfWeek = req.DayOfWeek
+	case models.ReportFrequencyMonthly:
+		subscription.DayOfMonth = req.DayOfMonth
+	}
+	subscription.SendTime = req.SendTime
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+	subscription.NextRunAt = nextReportRun(subscription, time.Now().In(companyLocation))
+	return nil
+}
+
+// getCompanyReportSubscription retrieves a subscription of the company.
+func (s *reportSubscriptionService) getCompanyReportSubscription(companyID int, id uint) (*models.ReportSubscription, error) {
+	subscription, err := s.reportSubscriptionRepo.GetReportSubscriptionByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve report subscription: %w", err)
+	}
+	if subscription == nil || subscription.CompanyID != companyID {
+		return nil, ErrReportSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// nextReportRun returns the first delivery time of a subscription after the given time, which must be in the
+// company's timezone.
+func nextReportRun(subscription *models.ReportSubscription, after time.Time) time.Time {
+	sendTime, _ := time.Parse("15:04", subscription.SendTime)
+	for days := 0; ; days++ {
+		run := time.Date(after.Year(), after.Month(), after.Day()+days, sendTime.Hour(), sendTime.Minute(), 0, 0, after.Location())
+		if !run.After(after) {
+			continue
+		}
+		switch subscription.Frequency {
+		case models.ReportFrequencyWeekly:
+			if subscription.DayOfWeek != nil && int(run.Weekday()) != *subscription.DayOfWeek {
+				continue
+			}
+		case models.ReportFrequencyMonthly:
+			if subscription.DayOfMonth != nil && run.Day() != *subscription.DayOfMonth {
+				continue
+			}
+		}
+		return run
+	}
+}
+
+// reportPeriodBefore returns the dates a delivery at runAt covers: the day before for daily reports, the seven
+// days before for weekly reports and the previous calendar month for monthly reports.
+func reportPeriodBefore(frequency string, runAt time.Time) (time.Time, time.Time) {
+	today := time.Date(runAt.Year(), runAt.Month(), runAt.Day(), 0, 0, 0, 0, runAt.Location())
+	switch frequency {
+	case models.ReportFrequencyWeekly:
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, -1)
+	case models.ReportFrequencyMonthly:
+		firstOfMonth := time.Date(runAt.Year(), runAt.Month(), 1, 0, 0, 0, 0, runAt.Location())
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1)
+	}
+	return today.AddDate(0, 0, -1), today.AddDate(0, 0, -1)
+}
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"fmt"
+	"go-face-auth/database/repository"
+	"go-face-auth/helper"
+	"go-face-auth/models"
+	"sort"
+	"time"
+)
+
+// TimesheetService defines the interface for monthly timesheet and payroll summaries.
+type TimesheetService interface {
+	GetTimesheet(companyID int, startDate, endDate time.Time) (*TimesheetReport, error)
+	ExportTimesheet(companyID int, startDate, endDate time.Time) (*helper.Report, error)
+}
+
+// timesheetService is the concrete implementation of TimesheetService.
+type timesheetService struct {
+	companyRepo          repository.CompanyRepository
+	employeeRepo         repository.EmployeeRepository
+	attendanceRepo       repository.AttendanceRepository
+	leaveRequestRepo     repository.LeaveRequestRepository
+	divisionRepo         repository.DivisionRepository
+	shiftRepo            repository.ShiftRepository
+	overtimePolicyRepo   repository.OvertimePolicyRepository
+	publicHolidayRepo    repository.PublicHolidayRepository
+	leaveTypeRepo        repository.LeaveTypeRepository
+	attendancePolicyRepo repository.AttendancePolicyRepository
+}
+
+// NewTimesheetService creates a new instance of TimesheetService.
+func NewTimesheetService(companyRepo repository.CompanyRepository, employeeRepo repository.EmployeeRepository, attendanceRepo repository.AttendanceRepository, leaveRequestRepo repository.LeaveRequestRepository, divisionRepo repository.DivisionRepository, shiftRepo repository.ShiftRepository, overtimePolicyRepo repository.OvertimePolicyRepository, publicHolidayRepo repository.PublicHolidayRepository, leaveTypeRepo repository.LeaveTypeRepository, attendancePolicyRepo repository.AttendancePolicyRepository) TimesheetService {
+	return &timesheetService{
+		companyRepo:          companyRepo,
+		employeeRepo:         employeeRepo,
+		attendanceRepo:       attendanceRepo,
+		leaveRequestRepo:     leaveRequestRepo,
+		divisionRepo:         divisionRepo,
+		shiftRepo:            shiftRepo,
+		overtimePolicyRepo:   overtimePolicyRepo,
+		publicHolidayRepo:    publicHolidayRepo,
+		leaveTypeRepo:        leaveTypeRepo,
+		attendancePolicyRepo: attendancePolicyRepo,
+	}
+}
+
+// TimesheetTotals holds the payroll figures of an employee, a division or the whole company for a period.
+type TimesheetTotals struct {
+	DaysPresent           int            `json:"days_present"`
+	RemoteDays            int            `json:"remote_days"` // Present days worked remotely
+	LateCount             int            `json:"late_count"`
+	LateMinutes           int            `json:"late_minutes"`
+	Absences              int            `json:"absences"`
+	IncompleteDays        int            `json:"incomplete_days"`
+	LeaveDays             map[string]int `json:"leave_days"`        // Keyed by leave type, e.g. "cuti", "sakit"
+	UnpaidLeaveDays       int            `json:"unpaid_leave_days"` // Days of leave types the company does not pay
+	WorkedHours           float64        `json:"worked_hours"`
+	OvertimeHours         float64        `json:"overtime_hours"`
+	WeightedOvertimeHours float64        `json:"weighted_overtime_hours"`
+}
+
+// EmployeeTimesheet is the timesheet of a single employee.
+type EmployeeTimesheet struct {
+	EmployeeID       int    `json:"employee_id"`
+	EmployeeIDNumber string `json:"employee_id_number"`
+	Name             string `json:"name"`
+	Position         string `json:"position"`
+	TimesheetTotals
+}
+
+// DivisionTimesheet groups employee timesheets by division. DivisionID is nil for employees without a division.
+type DivisionTimesheet struct {
+	DivisionID   *uint               `json:"division_id"`
+	DivisionName string              `json:"division_name"`
+	Employees    []EmployeeTimesheet `json:"employees"`
+	Totals       TimesheetTotals     `json:"totals"`
+}
+
+// TimesheetReport is the company timesheet for a period.
+type TimesheetReport struct {
+	StartDate  string              `json:"start_date"`
+	EndDate    string              `json:"end_date"`
+	LeaveTypes []string            `json:"leave_types"`
+	Divisions  []DivisionTimesheet `json:"divisions"`
+	Totals     TimesheetTotals     `json:"totals"`
+}
+
+func (t *TimesheetTotals) add(other TimesheetTotals) {
+	t.DaysPresent += other.DaysPresent
+	t.RemoteDays += other.RemoteDays
+	t.LateCount += other.LateCount
+	t.LateMinutes += other.LateMinutes
+	t.Absences += other.Absences
+	t.IncompleteDays += other.IncompleteDays
+	if t.LeaveDays == nil {
+		t.LeaveDays = make(map[string]int)
+	}
+	for leaveType, days := range other.LeaveDays {
+		t.LeaveDays[leaveType] += days
+	}
+	t.UnpaidLeaveDays += other.UnpaidLeaveDays
+	t.WorkedHours = roundHours(t.WorkedHours + other.WorkedHours)
+	t.OvertimeHours = roundHours(t.OvertimeHours + other.OvertimeHours)
+	t.WeightedOvertimeHours = roundHours(t.WeightedOvertimeHours + other.WeightedOvertimeHours)
+}
+
+func (s *timesheetService) GetTimesheet(companyID int, startDate, endDate time.Time) (*TimesheetReport, error) {
+	company, err := s.companyRepo.GetCompanyByID(companyID)
+	if err != nil || company == nil {
+		return nil, ErrCompanyNotFound
+	}
+	loc, err := time.LoadLocation(company.Timezone)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date cannot be before start date")
+	}
+
+	// Period boundaries in the company's local time.
+	periodStart := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+	periodEnd := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, loc)
+
+	employees, err := s.employeeRepo.GetEmployeesByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve employees: %w", err)
+	}
+	divisions, err := s.divisionRepo.GetDivisionsByCompanyID(uint(companyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve divisions: %w", err)
+	}
+	shifts, err := s.shiftRepo.GetShiftsByCompanyID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shifts: %w", err)
+	}
+	attendances, err := s.attendanceRepo.GetCompanyAttendancesFiltered(companyID, &periodStart, &periodEnd, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attendances: %w", err)
+	}
+	leaveRequests, err := s.leaveRequestRepo.GetApprovedLeaveRequestsOverlapping(companyID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve leave requests: %w", err)
+	}
+	leaveTypes, err := leaveTypesByCode(s.leaveTypeRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := loadAttendancePolicySet(s.attendancePolicyRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := loadLeaveCalendar(s.overtimePolicyRepo, s.publicHolidayRepo, companyID)
+	if err != nil {
+		return nil, err
+	}
+	calculator, err := newOvertimeCalculator(companyID, &startDate, &endDate, s.overtimePolicyRepo, s.publicHolidayRepo, s.companyRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	divisionMap := make(map[uint]models.DivisionTable)
+	for _, division := range divisions {
+		divisionMap[division.ID] = division
+	}
+	shiftMap := make(map[int]models.ShiftsTable)
+	for _, shift := range shifts {
+		shiftMap[shift.ID] = shift
+	}
+
+	timesheets := make(map[int]*EmployeeTimesheet)
+	for _, employee := range employees {
+		timesheets[employee.ID] = &EmployeeTimesheet{
+			EmployeeID:       employee.ID,
+			EmployeeIDNumber: employee.EmployeeIDNumber,
+			Name:             employee.Name,
+			Position:         employee.Position,
+			TimesheetTotals:  TimesheetTotals{LeaveDays: make(map[string]int)},
+		}
+	}
+
+	// Regular attendance: one present day per local date, plus late, absence and worked hours.
+	var overtimeAttendances []models.AttendancesTable
+	presentDays := make(map[string]bool)
+	employeeByID := make(map[int]models.EmployeesTable)
+	for _, employee := range employees {
+		employeeByID[employee.ID] = employee
+	}
+	for _, att := range attendances {
+		timesheet, ok := timesheets[att.EmployeeID]
+		if !ok {
+			continue
+		}
+		if att.Status.IsOvertime() {
+			overtimeAttendances = append(overtimeAttendances, att)
+			continue
+		}
+
+		checkIn := att.CheckInTime.In(loc)
+		switch att.Status {
+		case models.AttendanceStatusAbsent:
+			timesheet.Absences++
+			continue
+		case models.AttendanceStatusOnLeave, models.AttendanceStatusOnSick:
+			continue // Counted from approved leave requests below
+		case models.AttendanceStatusIncomplete:
+			timesheet.IncompleteDays++
+		}
+
+		dayKey := fmt.Sprintf("%d:%s", att.EmployeeID, checkIn.Format("2006-01-02"))
+		if !presentDays[dayKey] {
+			presentDays[dayKey] = true
+			timesheet.DaysPresent++
+			if att.WorkMode == models.WorkModeRemote {
+				timesheet.RemoteDays++
+			}
+		}
+
+		if lateMinutes, isLate := s.lateMinutes(att, checkIn, employeeByID[att.EmployeeID], divisionMap, shiftMap, policies, loc); isLate {
+			timesheet.LateCount++
+			timesheet.LateMinutes += lateMinutes
+		}
+
+		if att.CheckOutTime != nil && att.CheckOutTime.After(att.CheckInTime) {
+			timesheet.WorkedHours += att.CheckOutTime.Sub(att.CheckInTime).Hours()
+		}
+	}
+
+	// Overtime: raw hours and weighted hours from the company's overtime policy.
+	compensations := calculator.CalculateAll(overtimeAttendances)
+	for _, att := range overtimeAttendances {
+		timesheet := timesheets[att.EmployeeID]
+		timesheet.OvertimeHours += float64(att.OvertimeMinutes) / 60
+		timesheet.WeightedOvertimeHours += compensations[att.ID].WeightedHours
+	}
+
+	// Leave: working days of approved leave that fall inside the period, counted as the leave balance counts them.
+	leaveTypeSet := make(map[string]bool)
+	for _, leave := range leaveRequests {
+		timesheet, ok := timesheets[int(leave.EmployeeID)]
+		if !ok {
+			continue
+		}
+		if days := overlappingLeaveDays(calendar, leave.StartDate, leave.EndDate, startDate, endDate); days > 0 {
+			timesheet.LeaveDays[leave.Type] += days
+			leaveTypeSet[leave.Type] = true
+			if leaveType, ok := leaveTypes[leave.Type]; ok && !leaveType.IsPaid {
+				timesheet.UnpaidLeaveDays += days
+			}
+		}
+	}
+
+	report := &TimesheetReport{
+		StartDate:  startDate.Format("2006-01-02"),
+		EndDate:    endDate.Format("2006-01-02"),
+		LeaveTypes: make([]string, 0, len(leaveTypeSet)),
+		Totals:     TimesheetTotals{LeaveDays: make(map[string]int)},
+	}
+	for leaveType := range leaveTypeSet {
+		report.LeaveTypes = append(report.LeaveTypes, leaveType)
+	}
+	sort.Strings(report.LeaveTypes)
+
+	// Group by division, keeping employees without a division in their own group at the end.
+	groups := make(map[uint]*DivisionTimesheet)
+	var noDivision *DivisionTimesheet
+	for _, employee := range employees {
+		timesheet := timesheets[employee.ID]
+		timesheet.WorkedHours = roundHours(timesheet.WorkedHours)
+		timesheet.OvertimeHours = roundHours(timesheet.OvertimeHours)
+		timesheet.WeightedOvertimeHours = roundHours(timesheet.WeightedOvertimeHours)
+
+		var group *DivisionTimesheet
+		if division, ok := divisionMap[uintFromIntPtr(employee.DivisionID)]; ok && employee.DivisionID != nil {
+			group = groups[division.ID]
+			if group == nil {
+				divisionID := division.ID
+				group = &DivisionTimesheet{DivisionID: &divisionID, DivisionName: division.Name, Totals: TimesheetTotals{LeaveDays: make(map[string]int)}}
+				groups[division.ID] = group
+			}
+		} else {
+			if noDivision == nil {
+				noDivision = &DivisionTimesheet{DivisionName: "No Division", Totals: TimesheetTotals{LeaveDays: make(map[string]int)}}
+			}
+			group = noDivision
+		}
+		group.Employees = append(group.Employees, *timesheet)
+		group.Totals.add(timesheet.TimesheetTotals)
+		report.Totals.add(timesheet.TimesheetTotals)
+	}
+
+	for _, group := range groups {
+		sort.Slice(group.Employees, func(i, j int) bool { return group.Employees[i].Name < group.Employees[j].Name })
+		report.Divisions = append(report.Divisions, *group)
+	}
+	sort.Slice(report.Divisions, func(i, j int) bool { return report.Divisions[i].DivisionName < report.Divisions[j].DivisionName })
+	if noDivision != nil {
+		sort.Slice(noDivision.Employees, func(i, j int) bool { return noDivision.Employees[i].Name < noDivision.Employees[j].Name })
+		report.Divisions = append(report.Divisions, *noDivision)
+	}
+
+	return report, nil
+}
+
+func (s *timesheetService) ExportTimesheet(companyID int, startDate, endDate time.Time) (*helper.Report, error) {
+	report, err := s.GetTimesheet(companyID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	totalsHeaders := []string{"Days Present", "Remote Days", "Late Count", "Late Minutes", "Absences", "Incomplete Days"}
+	for _, leaveType := range report.LeaveTypes {
+		totalsHeaders = append(totalsHeaders, fmt.Sprintf("Leave (%s)", leaveType))
+	}
+	totalsHeaders = append(totalsHeaders, "Unpaid Leave Days", "Worked Hours", "Overtime Hours", "Weighted Overtime Hours")
+
+	// Summary table: one row per division plus a company total.
+	summary := helper.ReportTable{
+		Name:    "Summary",
+		Headers: append([]string{"Division", "Employees"}, totalsHeaders...),
+		Totals:  timesheetReportRow([]interface{}{"Total", countTimesheetEmployees(report)}, report.Totals, report.LeaveTypes),
+	}
+	for _, division := range report.Divisions {
+		summary.Rows = append(summary.Rows, timesheetReportRow([]interface{}{division.DivisionName, len(division.Employees)}, division.Totals, report.LeaveTypes))
+	}
+	tables := []helper.ReportTable{summary}
+
+	// One table per division.
+	for _, division := range report.Divisions {
+		table := helper.ReportTable{
+			Name:    division.DivisionName,
+			Headers: append([]string{"Employee ID Number", "Name", "Position"}, totalsHeaders...),
+			Totals:  timesheetReportRow([]interface{}{"", "Total", ""}, division.Totals, report.LeaveTypes),
+		}
+		for _, employee := range division.Employees {
+			table.Rows = append(table.Rows, timesheetReportRow([]interface{}{employee.EmployeeIDNumber, employee.Name, employee.Position}, employee.TimesheetTotals, report.LeaveTypes))
+		}
+		tables = append(tables, table)
+	}
+
+	return &helper.Report{
+		Title:    "Timesheet",
+		Subtitle: fmt.Sprintf("Period: %s to %s", report.StartDate, report.EndDate),
+		FileName: fmt.Sprintf("timesheet_%s_to_%s", report.StartDate, report.EndDate),
+		Tables:   tables,
+	}, nil
+}
+
+// lateMinutes reports whether a check-in was late against the employee's effective shift and by how many minutes.
+// The check-in status is overwritten on check-out, so lateness is recomputed from the shift where possible, with
+// the late threshold and rounding of the attendance policy as on check-in.
+func (s *timesheetService) lateMinutes(att models.AttendancesTable, checkIn time.Time, employee models.EmployeesTable, divisions map[uint]models.DivisionTable, shifts map[int]models.ShiftsTable, policies *attendancePolicySet, loc *time.Location) (int, bool) {
+	var shift *models.ShiftsTable
+	if division, ok := divisions[uintFromIntPtr(employee.DivisionID)]; ok && employee.DivisionID != nil && len(division.Shifts) > 0 {
+		shift = &division.Shifts[0]
+	} else if employee.ShiftID != nil {
+		if employeeShift, ok := shifts[*employee.ShiftID]; ok {
+			shift = &employeeShift
+		}
+	}
+
+	if shift == nil || att.IsCorrection {
+		return 0, att.Status == models.AttendanceStatusLate
+	}
+
+	shiftStart, err := helper.ParseTime(checkIn, shift.StartTime, loc)
+	if err != nil {
+		return 0, att.Status == models.AttendanceStatusLate
+	}
+	rules := policies.rules(employee.DivisionID, *shift)
+	if rounded := rules.round(checkIn); rounded.After(shiftStart.Add(rules.LateThreshold)) {
+		return int(rounded.Sub(shiftStart).Minutes()), true
+	}
+	return 0, att.Status == models.AttendanceStatusLate
+}
+
+// overlappingLeaveDays counts the working days of leave from leaveStart to leaveEnd that fall inside the inclusive
+// period, leaving out rest days and public holidays.
+func overlappingLeaveDays(calendar *leaveCalendar, leaveStart, leaveEnd, periodStart, periodEnd time.Time) int {
+	start := dateOnly(leaveStart)
+	if b := dateOnly(periodStart); b.After(start) {
+		start = b
+	}
+	end := dateOnly(leaveEnd)
+	if b := dateOnly(periodEnd); b.Before(end) {
+		end = b
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return int(sumLeaveDays(calendar.daysByYear(start, end)))
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func uintFromIntPtr(value *int) uint {
+	if value == nil {
+		return 0
+	}
+	return uint(*value)
+}
+
+func countTimesheetEmployees(report *TimesheetReport) int {
+	count := 0
+	for _, division := range report.Divisions {
+		count += len(division.Employees)
+	}
+	return count
+}
+
+func timesheetReportRow(leading []interface{}, totals TimesheetTotals, leaveTypes []string) []interface{} {
+	values := append([]interface{}{}, leading...)
+	values = append(values, totals.DaysPresent, totals.RemoteDays, totals.LateCount, totals.LateMinutes, totals.Absences, totals.IncompleteDays)
+	for _, leaveType := range leaveTypes {
+		values = append(values, totals.LeaveDays[leaveType])
+	}
+	return append(values, totals.UnpaidLeaveDays, totals.WorkedHours, totals.OvertimeHours, totals.WeightedOvertimeHours)
+}
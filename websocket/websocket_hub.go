@@ -82,6 +82,7 @@ type Client struct {
 	Conn      *websocket.Conn
 	Send      chan []byte
 	CompanyID int           // To identify which company this client belongs to
+	EmployeeID int          // Set for employee notification clients, 0 otherwise
 	Done      chan struct{} // Channel to signal when the client is done
 }
 
@@ -267,6 +268,58 @@ func (h *Hub) SendSuperAdminNotification(payload SuperAdminNotificationPayload)
 	}
 }
 
+// SendMessageToEmployee sends a structured message to the notification clients of a single employee.
+func (h *Hub) SendMessageToEmployee(companyID, employeeID int, messageType string, payload interface{}) {
+	structuredMessage := map[string]interface{}{
+		"type":    messageType,
+		"payload": payload,
+	}
+	messageBytes, err := json.Marshal(structuredMessage)
+	if err != nil {
+		log.Printf("Error marshalling employee message: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		if client.CompanyID == companyID && client.EmployeeID == employeeID {
+			select {
+			case client.Send <- messageBytes:
+			default:
+				log.Printf("Employee client send channel full or closed: %v (Employee ID: %d)", client.Conn.RemoteAddr(), employeeID)
+			}
+		}
+	}
+}
+
+// SendMessageToCompanyAdmins sends a structured message to the admin dashboard clients of a company.
+func (h *Hub) SendMessageToCompanyAdmins(companyID int, messageType string, payload interface{}) {
+	structuredMessage := map[string]interface{}{
+		"type":    messageType,
+		"payload": payload,
+	}
+	messageBytes, err := json.Marshal(structuredMessage)
+	if err != nil {
+		log.Printf("Error marshalling admin message: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		if client.CompanyID == companyID && client.EmployeeID == 0 { // Employee notification clients have an employee ID
+			select {
+			case client.Send <- messageBytes:
+			default:
+				log.Printf("Admin client send channel full or closed: %v (Company ID: %d)", client.Conn.RemoteAddr(), companyID)
+			}
+		}
+	}
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection.
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)